@@ -8,9 +8,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/daemon"
 	"github.com/ubuntu/authd/internal/services"
+	"github.com/ubuntu/authd/internal/services/refresh"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
@@ -42,10 +44,12 @@ type systemPaths struct {
 
 // daemonConfig defines configuration parameters of the daemon.
 type daemonConfig struct {
-	Brokers     []string
-	Verbosity   int
-	Paths       systemPaths
-	UsersConfig *users.Config `mapstructure:",squash" yaml:",inline"`
+	Brokers       []string
+	Verbosity     int
+	Paths         systemPaths
+	BrokersConfig *brokers.Config `mapstructure:",squash" yaml:",inline"`
+	UsersConfig   *users.Config   `mapstructure:",squash" yaml:",inline"`
+	RefreshConfig *refresh.Config `mapstructure:",squash" yaml:",inline"`
 }
 
 // New registers commands and return a new App.
@@ -71,7 +75,9 @@ func New() *App {
 					Database:    consts.DefaultDatabaseDir,
 					Socket:      "",
 				},
-				UsersConfig: &users.DefaultConfig,
+				BrokersConfig: &brokers.DefaultConfig,
+				UsersConfig:   &users.DefaultConfig,
+				RefreshConfig: &refresh.DefaultConfig,
 			}
 
 			// Install and unmarshall configuration
@@ -87,7 +93,7 @@ func New() *App {
 
 			// If we are only checking the configuration, we exit now.
 			if check, _ := cmd.Flags().GetBool("check-config"); check {
-				return nil
+				return checkBrokersConfig(a.config.Paths.BrokersConf)
 			}
 
 			if err := maybeMigrateOldDBDir(oldDBDir, a.config.Paths.Database); err != nil {
@@ -122,6 +128,21 @@ func New() *App {
 	return &a
 }
 
+// checkBrokersConfig warns about the issues found in the brokers configuration, which don't prevent the daemon from
+// starting.
+func checkBrokersConfig(brokersConfPath string) error {
+	ctx := context.Background()
+
+	issues, err := brokers.CheckConfig(ctx, brokersConfPath)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		log.Warningf(ctx, "%v", issue)
+	}
+	return nil
+}
+
 // serve creates new GRPC services and listen on a TCP socket. This call is blocking until we quit it.
 func (a *App) serve(config daemonConfig) error {
 	ctx := context.Background()
@@ -136,8 +157,16 @@ func (a *App) serve(config daemonConfig) error {
 		// This is an assert, since we assume that the daemonConfig on [New] is properly defined.
 		panic("Users config must be set! This is a programmer error.")
 	}
+	if config.BrokersConfig == nil {
+		// This is an assert, since we assume that the daemonConfig on [New] is properly defined.
+		panic("Brokers config must be set! This is a programmer error.")
+	}
+	if config.RefreshConfig == nil {
+		// This is an assert, since we assume that the daemonConfig on [New] is properly defined.
+		panic("Refresh config must be set! This is a programmer error.")
+	}
 
-	m, err := services.NewManager(ctx, dbDir, config.Paths.BrokersConf, config.Brokers, *config.UsersConfig)
+	m, err := services.NewManager(ctx, dbDir, config.Paths.BrokersConf, config.Brokers, *config.BrokersConfig, *config.UsersConfig, *config.RefreshConfig)
 	if err != nil {
 		close(a.ready)
 		return err
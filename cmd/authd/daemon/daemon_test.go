@@ -13,8 +13,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/cmd/authd/daemon"
+	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/fileutils"
+	"github.com/ubuntu/authd/internal/services/refresh"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/log"
@@ -258,11 +260,22 @@ func TestAppGetRootCmd(t *testing.T) {
 
 func TestConfigLoad(t *testing.T) {
 	wantUsersConfig := &users.Config{UIDMin: 10001, UIDMax: 19000, GIDMax: 9999}
+	wantBrokersConfig := &brokers.Config{
+		SessionTTL:           10 * time.Minute,
+		Routes:               []brokers.Route{{Domain: "example.com", Broker: "some-broker", Mandatory: true}},
+		PingInterval:         time.Minute,
+		MaxSessionsPerBroker: 50,
+		MaxSessionsPerUser:   5,
+		UniformResponses:     true,
+	}
+	wantRefreshConfig := &refresh.Config{Interval: 12 * time.Hour, BrokerDelay: 2 * time.Second, TerminateRemovedUserSessions: true}
 	customizedSocketPath := filepath.Join(t.TempDir(), "mysocket")
 	var config daemon.DaemonConfig
 	config.Verbosity = 1
 	config.Paths.Socket = customizedSocketPath
 	config.UsersConfig = wantUsersConfig
+	config.BrokersConfig = wantBrokersConfig
+	config.RefreshConfig = wantRefreshConfig
 
 	a, wait := startDaemon(t, &config)
 	defer wait()
@@ -272,6 +285,8 @@ func TestConfigLoad(t *testing.T) {
 	require.NoError(t, err, "Socket should exist")
 	require.Equal(t, 1, a.Config().Verbosity, "Verbosity is set from config")
 	require.Equal(t, wantUsersConfig, a.Config().UsersConfig, "Unexpected users config")
+	require.Equal(t, wantBrokersConfig, a.Config().BrokersConfig, "Unexpected brokers config")
+	require.Equal(t, wantRefreshConfig, a.Config().RefreshConfig, "Unexpected refresh config")
 }
 
 func TestAutoDetectConfig(t *testing.T) {
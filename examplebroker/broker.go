@@ -100,6 +100,10 @@ type Broker struct {
 
 	privateKey *rsa.PrivateKey
 
+	// settings are the broker-specific settings sent by authd from the broker configuration file.
+	settings   map[string]string
+	settingsMu sync.Mutex
+
 	sleepMultiplier float64
 }
 
@@ -867,6 +871,24 @@ func (b *Broker) UserPreCheck(ctx context.Context, username string) (string, err
 	return userInfoFromName(username), nil
 }
 
+// Configure stores the broker-specific settings read by authd from the broker configuration file.
+func (b *Broker) Configure(ctx context.Context, settings map[string]string) error {
+	b.settingsMu.Lock()
+	defer b.settingsMu.Unlock()
+
+	b.settings = maps.Clone(settings)
+	log.Debugf(ctx, "Broker configured with %d settings", len(settings))
+	return nil
+}
+
+// CheckAccount checks if the account of the user can still be used.
+func (b *Broker) CheckAccount(ctx context.Context, username string) (status, msg string, err error) {
+	if _, err := b.UserPreCheck(ctx, username); err != nil {
+		return "", "", err
+	}
+	return auth.AccountValid, "", nil
+}
+
 // decryptAES is just here to illustrate the encryption and decryption
 // and in no way the right way to perform a secure encryption
 //
@@ -84,6 +84,12 @@ func (b *Bus) NewSession(username, lang, mode string) (sessionID, encryptionKey
 	return sessionID, encryptionKey, nil
 }
 
+// NewSessionWithContext is the method through which the broker and the daemon will communicate once dbusInterface.NewSessionWithContext is called.
+// The example broker doesn't use the PAM context, so this is equivalent to NewSession.
+func (b *Bus) NewSessionWithContext(username, lang, mode string, pamContext map[string]string) (sessionID, encryptionKey string, dbusErr *dbus.Error) {
+	return b.NewSession(username, lang, mode)
+}
+
 // GetAuthenticationModes is the method through which the broker and the daemon will communicate once dbusInterface.GetAuthenticationModes is called.
 func (b *Bus) GetAuthenticationModes(sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, dbusErr *dbus.Error) {
 	authenticationModes, err := b.broker.GetAuthenticationModes(context.Background(), sessionID, supportedUILayouts)
@@ -134,3 +140,20 @@ func (b *Bus) UserPreCheck(username string) (userinfo string, dbusErr *dbus.Erro
 	}
 	return userinfo, nil
 }
+
+// Configure is the method through which the broker and the daemon will communicate once dbusInterface.Configure is called.
+func (b *Bus) Configure(settings map[string]string) (dbusErr *dbus.Error) {
+	if err := b.broker.Configure(context.Background(), settings); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// CheckAccount is the method through which the broker and the daemon will communicate once dbusInterface.CheckAccount is called.
+func (b *Bus) CheckAccount(username string) (status, msg string, dbusErr *dbus.Error) {
+	status, msg, err := b.broker.CheckAccount(context.Background(), username)
+	if err != nil {
+		return "", "", dbus.MakeFailedError(err)
+	}
+	return status, msg, nil
+}
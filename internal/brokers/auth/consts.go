@@ -17,6 +17,34 @@ const (
 // Replies is the list of all possible authentication replies.
 var Replies = []string{Granted, Denied, Cancelled, Retry, Next}
 
+const (
+	// AccountValid is the response when the account can be used.
+	AccountValid = "valid"
+	// AccountExpired is the response when the account expired.
+	AccountExpired = "expired"
+	// AccountPasswordChangeRequired is the response when the user must change their password before using the account.
+	AccountPasswordChangeRequired = "password_change_required"
+	// AccountDenied is the response when the account was disabled or the user is not allowed to use it anymore.
+	AccountDenied = "denied"
+)
+
+// AccountStatuses is the list of all possible account check replies.
+var AccountStatuses = []string{AccountValid, AccountExpired, AccountPasswordChangeRequired, AccountDenied}
+
+const (
+	// CredentialsEstablish is used when the credentials of the user are set before opening a session.
+	CredentialsEstablish = "establish"
+	// CredentialsDelete is used when the credentials of the user are removed after closing a session.
+	CredentialsDelete = "delete"
+	// CredentialsReinitialize is used when the credentials of the user are set again, such as when unlocking a screen.
+	CredentialsReinitialize = "reinitialize"
+	// CredentialsRefresh is used when the lifetime of the credentials of the user is extended.
+	CredentialsRefresh = "refresh"
+)
+
+// CredentialsActions is the list of all possible actions on the credentials of a user.
+var CredentialsActions = []string{CredentialsEstablish, CredentialsDelete, CredentialsReinitialize, CredentialsRefresh}
+
 const (
 	// SessionModeLogin is used when the session is for user login.
 	// TODO: We can change this to "login" once all broker installations are updated to use the new name.
@@ -2,17 +2,25 @@ package brokers
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/users/types"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
@@ -22,8 +30,33 @@ import (
 // LocalBrokerName is the name of the local broker.
 const LocalBrokerName = "local"
 
+// sessionTokenSize is the size in bytes of the random token added to the session IDs.
+const sessionTokenSize = 16
+
+// PAMContext describes where an authentication request comes from, as reported by the PAM module.
+type PAMContext struct {
+	// Service is the PAM service name, such as "login", "sshd" or "gdm-password".
+	Service string
+	// TTY is the terminal, or the X display, the request comes from.
+	TTY string
+	// RHost is the remote host the request comes from, if any.
+	RHost string
+	// RUser is the user requesting the authentication, if any.
+	RUser string
+}
+
+// toMap returns the context in the format sent to the brokers.
+func (c PAMContext) toMap() map[string]string {
+	return map[string]string{
+		"service": c.Service,
+		"tty":     c.TTY,
+		"rhost":   c.RHost,
+		"ruser":   c.RUser,
+	}
+}
+
 type brokerer interface {
-	NewSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error)
+	NewSession(ctx context.Context, username, lang, mode string, pamContext PAMContext) (sessionID, encryptionKey string, err error)
 	GetAuthenticationModes(ctx context.Context, sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, err error)
 	SelectAuthenticationMode(ctx context.Context, sessionID, authenticationModeName string) (uiLayoutInfo map[string]string, err error)
 	IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access, data string, err error)
@@ -31,19 +64,45 @@ type brokerer interface {
 	CancelIsAuthenticated(ctx context.Context, sessionID string)
 
 	UserPreCheck(ctx context.Context, username string) (userinfo string, err error)
+	CheckAccount(ctx context.Context, username string) (status, msg string, err error)
+	OpenUserSession(ctx context.Context, username string, pamContext PAMContext) (env map[string]string, err error)
+	CloseUserSession(ctx context.Context, username string, pamContext PAMContext) (err error)
+	SetUserCredentials(ctx context.Context, username, action string) (env map[string]string, err error)
+
+	Ping(ctx context.Context) (err error)
+}
+
+// progressWatcher is implemented by the brokers which can report the progress of an ongoing authentication.
+type progressWatcher interface {
+	watchAuthenticationProgress(ctx context.Context, sessionID string, onProgress func(message string)) (stop func(), err error)
 }
 
 // Broker represents a broker object that can be used for authentication.
 type Broker struct {
-	ID                    string
-	Name                  string
-	BrandIconPath         string
+	ID            string
+	Name          string
+	Aliases       []string
+	BrandIconPath string
+	// BrandIcon is the icon loaded from BrandIconPath, if it is valid.
+	BrandIcon             *Icon
 	layoutValidators      map[string]map[string]layoutValidator
 	layoutValidatorsMu    *sync.Mutex
 	ongoingUserRequests   map[string]string
 	ongoingUserRequestsMu *sync.Mutex
-
-	brokerer brokerer
+	// latencies shapes the delays of the decoy sessions of the broker.
+	latencies *latencies
+	// encryptionAlgorithm is the algorithm of the last encryption key returned by the broker, which the keys of its
+	// decoy sessions use.
+	encryptionAlgorithm *atomic.Int32
+	// replies are the kinds of the recent replies of the broker, which its decoy sessions mimic.
+	replies *replies
+
+	timeouts  timeouts
+	access    accessPolicy
+	fallbacks []string
+	// webAuthnRPID is the relying party ID that the webauthn layouts of the broker must use.
+	webAuthnRPID string
+	brokerer     brokerer
 }
 
 type layoutValidator map[string]fieldValidator
@@ -59,49 +118,84 @@ func newBroker(ctx context.Context, configFile string, bus *dbus.Conn) (b Broker
 
 	name := LocalBrokerName
 	id := LocalBrokerName
-	var brandIcon string
+	var cfg brokerConfig
 	var broker brokerer
+	var icon *Icon
 
 	if configFile != "" {
 		log.Debugf(ctx, "Loading broker from %q", configFile)
-		broker, name, brandIcon, err = newDbusBroker(ctx, bus, configFile)
+		broker, cfg, err = newDbusBroker(ctx, bus, configFile)
 		if err != nil {
 			return Broker{}, err
 		}
-		h := fnv.New32a()
-		// This can’t error out in Hash32 implementation.
-		_, _ = h.Write([]byte(name))
-		id = fmt.Sprint(h.Sum32())
+		name = cfg.name
+		id = cfg.id
+		if id == "" {
+			h := fnv.New32a()
+			// This can’t error out in Hash32 implementation.
+			_, _ = h.Write([]byte(name))
+			id = fmt.Sprint(h.Sum32())
+		}
+		if slices.Contains(cfg.aliases, id) {
+			return Broker{}, fmt.Errorf("broker ID %q can't be one of its aliases", id)
+		}
+		if slices.Contains(cfg.fallbacks, id) {
+			return Broker{}, fmt.Errorf("broker %q can't be its own fallback", id)
+		}
+		if icon, err = loadBrandIcon(cfg.brandIcon, configFile); err != nil {
+			log.Warningf(ctx, "Ignoring brand icon of broker %q: %v", name, err)
+		}
 	}
 
 	return Broker{
 		ID:                    id,
 		Name:                  name,
-		BrandIconPath:         brandIcon,
+		Aliases:               cfg.aliases,
+		BrandIconPath:         cfg.brandIcon,
+		BrandIcon:             icon,
+		timeouts:              cfg.timeouts,
+		access:                cfg.access,
+		fallbacks:             cfg.fallbacks,
+		webAuthnRPID:          cfg.webAuthnRPID,
 		brokerer:              broker,
 		layoutValidators:      make(map[string]map[string]layoutValidator),
 		layoutValidatorsMu:    &sync.Mutex{},
 		ongoingUserRequests:   make(map[string]string),
 		ongoingUserRequestsMu: &sync.Mutex{},
+		latencies:             newLatencies(),
+		encryptionAlgorithm:   &atomic.Int32{},
+		replies:               newReplies(),
 	}, nil
 }
 
-// newSession calls the broker corresponding method, expanding sessionID with the broker ID prefix.
-func (b Broker) newSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
-	sessionID, encryptionKey, err = b.brokerer.NewSession(ctx, username, lang, mode)
+// newSession calls the broker corresponding method, expanding sessionID with the broker ID prefix and a random token,
+// so that the session IDs can't be guessed even if the broker ones can.
+func (b Broker) newSession(ctx context.Context, username, lang, mode string, pamContext PAMContext) (sessionID, encryptionKey string, err error) {
+	ctx, cancel := withTimeout(ctx, b.timeouts.newSession)
+	defer cancel()
+
+	start := time.Now()
+	sessionID, encryptionKey, err = b.brokerer.NewSession(ctx, username, lang, mode, pamContext)
 	if err != nil {
-		return "", "", err
+		return "", "", b.wrapTimeoutError(ctx, "NewSession", err)
 	}
+	b.latencies.record("NewSession", start)
+	b.encryptionAlgorithm.Store(int32(encryption.AlgorithmOf(encryptionKey)))
 
 	if sessionID == "" {
 		return "", "", errors.New("no session ID provided by broker")
 	}
 
+	token := make([]byte, sessionTokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return "", "", fmt.Errorf("can't generate session token: %v", err)
+	}
+
 	b.ongoingUserRequestsMu.Lock()
 	b.ongoingUserRequests[sessionID] = username
 	b.ongoingUserRequestsMu.Unlock()
 
-	return fmt.Sprintf("%s-%s", b.ID, sessionID), encryptionKey, nil
+	return fmt.Sprintf("%s-%x-%s", b.ID, token, sessionID), encryptionKey, nil
 }
 
 // GetAuthenticationModes calls the broker corresponding method, stripping broker ID prefix from sessionID.
@@ -112,10 +206,15 @@ func (b *Broker) GetAuthenticationModes(ctx context.Context, sessionID string, s
 	b.layoutValidators[sessionID] = generateValidators(ctx, sessionID, supportedUILayouts)
 	b.layoutValidatorsMu.Unlock()
 
+	ctx, cancel := withTimeout(ctx, b.timeouts.getAuthenticationModes)
+	defer cancel()
+
+	start := time.Now()
 	authenticationModes, err = b.brokerer.GetAuthenticationModes(ctx, sessionID, supportedUILayouts)
 	if err != nil {
-		return nil, err
+		return nil, b.wrapTimeoutError(ctx, "GetAuthenticationModes", err)
 	}
+	b.latencies.record("GetAuthenticationModes", start)
 
 	for _, a := range authenticationModes {
 		for _, key := range []string{layouts.ID, layouts.Label} {
@@ -131,26 +230,61 @@ func (b *Broker) GetAuthenticationModes(ctx context.Context, sessionID string, s
 // SelectAuthenticationMode calls the broker corresponding method, stripping broker ID prefix from sessionID.
 func (b Broker) SelectAuthenticationMode(ctx context.Context, sessionID, authenticationModeName string) (uiLayoutInfo map[string]string, err error) {
 	sessionID = b.parseSessionID(sessionID)
+
+	ctx, cancel := withTimeout(ctx, b.timeouts.selectAuthenticationMode)
+	defer cancel()
+
+	start := time.Now()
 	uiLayoutInfo, err = b.brokerer.SelectAuthenticationMode(ctx, sessionID, authenticationModeName)
+	if err != nil {
+		return nil, b.wrapTimeoutError(ctx, "SelectAuthenticationMode", err)
+	}
+	b.latencies.record("SelectAuthenticationMode", start)
+
+	uiLayoutInfo, err = b.validateUILayout(sessionID, uiLayoutInfo)
 	if err != nil {
 		return nil, err
 	}
-	return b.validateUILayout(sessionID, uiLayoutInfo)
+	b.replies.recordUILayout(uiLayoutInfo)
+	return uiLayoutInfo, nil
 }
 
 // IsAuthenticated calls the broker corresponding method, stripping broker ID prefix from sessionID.
 func (b Broker) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access string, data string, err error) {
+	return b.IsAuthenticatedWithProgress(ctx, sessionID, authenticationData, nil)
+}
+
+// IsAuthenticatedWithProgress is like IsAuthenticated, but calls onProgress with the status messages that the broker
+// sends while the authentication is ongoing, if it supports it. onProgress is not called anymore once this returns.
+func (b Broker) IsAuthenticatedWithProgress(ctx context.Context, sessionID, authenticationData string, onProgress func(message string)) (access string, data string, err error) {
 	sessionID = b.parseSessionID(sessionID)
 
+	defer b.watchAuthenticationProgress(ctx, sessionID, onProgress)()
+
+	timeout := b.timeouts.isAuthenticated
+	if isWaitRequest(authenticationData) {
+		timeout = b.timeouts.isAuthenticatedWait
+	}
+
+	// The broker call is not cancelled when ctx is, as we need to explicitly ask the broker to cancel it.
+	iaCtx, cancel := withTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
 	// monitor ctx in goroutine to call cancel
 	done := make(chan struct{})
+	start := time.Now()
 	go func() {
-		access, data, err = b.brokerer.IsAuthenticated(ctx, sessionID, authenticationData)
+		access, data, err = b.brokerer.IsAuthenticated(iaCtx, sessionID, authenticationData)
 		close(done)
 	}()
 
 	select {
 	case <-done:
+		if err != nil && errors.Is(iaCtx.Err(), context.DeadlineExceeded) {
+			// Let the broker know that nobody is waiting for this authentication anymore.
+			b.cancelIsAuthenticated(ctx, sessionID)
+			return "", "", b.wrapTimeoutError(iaCtx, "IsAuthenticated", err)
+		}
 		if err != nil {
 			return "", "", err
 		}
@@ -184,6 +318,16 @@ func (b Broker) IsAuthenticated(ctx context.Context, sessionID, authenticationDa
 			return "", "", err
 		}
 
+		if err = b.checkRequiredGroups(info); err != nil {
+			// The required groups are only logged, so that they are not disclosed to the user.
+			log.Noticef(ctx, "%s: Denying access to %q: %v", sessionID, info.Name, err)
+			d, err := json.Marshal(map[string]string{"message": "You are not a member of the groups required to use this broker"})
+			if err != nil {
+				return "", "", fmt.Errorf("can't marshal denial message: %v", err)
+			}
+			return auth.Denied, string(d), nil
+		}
+
 		d, err := json.Marshal(info)
 		if err != nil {
 			return "", "", fmt.Errorf("can't marshal UserInfo: %v", err)
@@ -194,6 +338,8 @@ func (b Broker) IsAuthenticated(ctx context.Context, sessionID, authenticationDa
 		if _, err := unmarshalAndGetKey(data, "message"); err != nil {
 			return "", "", err
 		}
+		// Only the failed attempts are recorded, as they are what the decoy sessions mimic.
+		b.latencies.record("IsAuthenticated", start)
 
 	case auth.Next:
 		if data == "{}" {
@@ -212,6 +358,22 @@ func (b Broker) IsAuthenticated(ctx context.Context, sessionID, authenticationDa
 	return access, data, nil
 }
 
+// watchAuthenticationProgress forwards the progress messages of the session to onProgress if the broker can send them,
+// until the returned function is called.
+func (b Broker) watchAuthenticationProgress(ctx context.Context, sessionID string, onProgress func(message string)) (stop func()) {
+	w, ok := b.brokerer.(progressWatcher)
+	if onProgress == nil || !ok {
+		return func() {}
+	}
+
+	stop, err := w.watchAuthenticationProgress(ctx, sessionID, onProgress)
+	if err != nil {
+		log.Warningf(ctx, "%s: Authentication progress won't be reported: %v", sessionID, err)
+		return func() {}
+	}
+	return stop
+}
+
 // endSession calls the broker corresponding method, stripping broker ID prefix from sessionID.
 func (b Broker) endSession(ctx context.Context, sessionID string) (err error) {
 	sessionID = b.parseSessionID(sessionID)
@@ -220,7 +382,14 @@ func (b Broker) endSession(ctx context.Context, sessionID string) (err error) {
 	defer b.ongoingUserRequestsMu.Unlock()
 	delete(b.ongoingUserRequests, sessionID)
 
-	return b.brokerer.EndSession(ctx, sessionID)
+	b.layoutValidatorsMu.Lock()
+	delete(b.layoutValidators, sessionID)
+	b.layoutValidatorsMu.Unlock()
+
+	ctx, cancel := withTimeout(ctx, b.timeouts.endSession)
+	defer cancel()
+
+	return b.wrapTimeoutError(ctx, "EndSession", b.brokerer.EndSession(ctx, sessionID))
 }
 
 // cancelIsAuthenticated calls the broker corresponding method.
@@ -228,13 +397,195 @@ func (b Broker) endSession(ctx context.Context, sessionID string) (err error) {
 //
 // Even though this is a public method, it should only be interacted with through IsAuthenticated and ctx cancellation.
 func (b Broker) cancelIsAuthenticated(ctx context.Context, sessionID string) {
+	// We don’t want to cancel the broker call when the parent call is cancelled.
+	ctx, cancel := withTimeout(context.WithoutCancel(ctx), b.timeouts.cancelIsAuthenticated)
+	defer cancel()
+
 	b.brokerer.CancelIsAuthenticated(ctx, sessionID)
 }
 
 // UserPreCheck calls the broker corresponding method.
 func (b Broker) UserPreCheck(ctx context.Context, username string) (userinfo string, err error) {
 	log.Debugf(context.TODO(), "Pre-checking user %q", username)
-	return b.brokerer.UserPreCheck(ctx, username)
+
+	ctx, cancel := withTimeout(ctx, b.timeouts.userPreCheck)
+	defer cancel()
+
+	start := time.Now()
+	userinfo, err = b.brokerer.UserPreCheck(ctx, username)
+	if err != nil {
+		return "", b.wrapTimeoutError(ctx, "UserPreCheck", err)
+	}
+	if userinfo != "" {
+		b.latencies.record("UserPreCheck", start)
+	}
+	return userinfo, nil
+}
+
+// CheckAccount asks the broker whether the account of the user can still be used. It returns one of
+// auth.AccountStatuses and an optional message to show to the user.
+func (b Broker) CheckAccount(ctx context.Context, username string) (status, msg string, err error) {
+	log.Debugf(context.TODO(), "Checking account of user %q", username)
+
+	ctx, cancel := withTimeout(ctx, b.timeouts.checkAccount)
+	defer cancel()
+
+	status, msg, err = b.brokerer.CheckAccount(ctx, username)
+	if err != nil {
+		return "", "", b.wrapTimeoutError(ctx, "CheckAccount", err)
+	}
+	if !slices.Contains(auth.AccountStatuses, status) {
+		return "", "", fmt.Errorf("broker %q returned an invalid account status %q", b.Name, status)
+	}
+
+	return status, msg, nil
+}
+
+// OpenUserSession notifies the broker that a session of the user is opened. It returns the environment variables to
+// set in the session.
+func (b Broker) OpenUserSession(ctx context.Context, username string, pamContext PAMContext) (env map[string]string, err error) {
+	log.Debugf(context.TODO(), "Opening session of user %q", username)
+
+	ctx, cancel := withTimeout(ctx, b.timeouts.userSession)
+	defer cancel()
+
+	env, err = b.brokerer.OpenUserSession(ctx, username, pamContext)
+	if err != nil {
+		return nil, b.wrapTimeoutError(ctx, "OpenUserSession", err)
+	}
+	if err = validateEnv(env); err != nil {
+		return nil, fmt.Errorf("broker %q returned an invalid environment: %v", b.Name, err)
+	}
+
+	return env, nil
+}
+
+// CloseUserSession notifies the broker that a session of the user is closed.
+func (b Broker) CloseUserSession(ctx context.Context, username string, pamContext PAMContext) (err error) {
+	log.Debugf(context.TODO(), "Closing session of user %q", username)
+
+	ctx, cancel := withTimeout(ctx, b.timeouts.userSession)
+	defer cancel()
+
+	if err = b.brokerer.CloseUserSession(ctx, username, pamContext); err != nil {
+		return b.wrapTimeoutError(ctx, "CloseUserSession", err)
+	}
+	return nil
+}
+
+// SetUserCredentials asks the broker to apply one of auth.CredentialsActions to the credentials of the user. It
+// returns the environment variables to set in the session.
+func (b Broker) SetUserCredentials(ctx context.Context, username, action string) (env map[string]string, err error) {
+	log.Debugf(context.TODO(), "Setting credentials of user %q: %s", username, action)
+
+	if !slices.Contains(auth.CredentialsActions, action) {
+		return nil, fmt.Errorf("invalid credentials action %q", action)
+	}
+
+	ctx, cancel := withTimeout(ctx, b.timeouts.userSession)
+	defer cancel()
+
+	env, err = b.brokerer.SetUserCredentials(ctx, username, action)
+	if err != nil {
+		return nil, b.wrapTimeoutError(ctx, "SetUserCredentials", err)
+	}
+	if err = validateEnv(env); err != nil {
+		return nil, fmt.Errorf("broker %q returned an invalid environment: %v", b.Name, err)
+	}
+
+	return env, nil
+}
+
+// Ping checks that the broker is reachable.
+func (b Broker) Ping(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, b.timeouts.ping)
+	defer cancel()
+
+	if err := b.brokerer.Ping(ctx); err != nil {
+		return b.wrapTimeoutError(ctx, "Ping", err)
+	}
+	return nil
+}
+
+// UserInfo returns the current information of a user known by the broker, as returned by UserPreCheck.
+// The information must include the groups of the user, even if empty, as they replace the stored ones.
+func (b Broker) UserInfo(ctx context.Context, username string) (info types.UserInfo, err error) {
+	defer decorate.OnError(&err, "can't get information of user %q from broker %q", username, b.Name)
+
+	userinfo, err := b.UserPreCheck(ctx, username)
+	if err != nil {
+		return types.UserInfo{}, err
+	}
+
+	info, err = unmarshalUserInfo(json.RawMessage(userinfo))
+	if err != nil {
+		return types.UserInfo{}, err
+	}
+	if err = validateUserInfo(info); err != nil {
+		return types.UserInfo{}, err
+	}
+	if !strings.EqualFold(info.Name, username) {
+		return types.UserInfo{}, fmt.Errorf("broker returned the information of user %q", info.Name)
+	}
+	if info.Groups == nil {
+		return types.UserInfo{}, errors.New("broker did not return the groups of the user")
+	}
+
+	return info, nil
+}
+
+// withTimeout returns a copy of ctx that is cancelled after timeout. A zero timeout means no timeout.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// wrapTimeoutError replaces err with an error to display to the user if the broker did not reply to method before
+// the ctx deadline.
+func (b Broker) wrapTimeoutError(ctx context.Context, method string, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	log.Warningf(ctx, "Broker %q did not reply in time to %s: %v", b.Name, method, err)
+	return newBrokerUnavailableError("broker %q took too long to respond, please try again later", b.Name)
+}
+
+// ErrNotSupported is returned when the broker does not implement an optional method.
+var ErrNotSupported = errors.New("not supported by the broker")
+
+// brokerUnavailableError is returned when the broker can't be reached or doesn't reply in time.
+type brokerUnavailableError struct {
+	error
+}
+
+// newBrokerUnavailableError returns a brokerUnavailableError with a message to display to the user, reported to the
+// client with the broker unavailable error code.
+func newBrokerUnavailableError(format string, args ...any) error {
+	return brokerUnavailableError{errmessages.WithErrorCode(authd.ErrorCode_ERROR_BROKER_UNAVAILABLE,
+		errmessages.NewToDisplayErrorf(format, args...))}
+}
+
+// Unwrap returns the error to display to the user.
+func (e brokerUnavailableError) Unwrap() error {
+	return e.error
+}
+
+// IsBrokerUnavailable returns true if the error is due to the broker not being reachable.
+func IsBrokerUnavailable(err error) bool {
+	return errors.As(err, &brokerUnavailableError{})
+}
+
+// isWaitRequest returns true if the authentication data is for an interactive wait mode rather than a secret check.
+func isWaitRequest(authenticationData string) bool {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(authenticationData), &data); err != nil {
+		return false
+	}
+	_, isWait := data[layouts.Wait]
+	return isWait
 }
 
 // generateValidators generates layout validators based on what is supported by the system.
@@ -280,6 +631,11 @@ func generateValidators(ctx context.Context, sessionID string, supportedUILayout
 //
 // If the layout is not valid (missing required fields or invalid values), an error is returned instead.
 func (b Broker) validateUILayout(sessionID string, layout map[string]string) (r map[string]string, err error) {
+	defer func() {
+		if err != nil {
+			err = errmessages.WithErrorCode(authd.ErrorCode_ERROR_INVALID_LAYOUT, err)
+		}
+	}()
 	defer decorate.OnError(&err, "could not validate UI layout")
 
 	b.layoutValidatorsMu.Lock()
@@ -318,12 +674,93 @@ func (b Broker) validateUILayout(sessionID string, layout map[string]string) (r
 			return nil, fmt.Errorf("field %q has invalid value %q, expected one of %s", key, value, strings.Join(validator.supportedValues, ","))
 		}
 	}
+	// The choices of a choice layout are provided by the broker, so they can only be checked to be well-formed.
+	if layout[layouts.Type] == layouts.Choice {
+		if _, err := layouts.ParseChoices(layout[layouts.Choices]); err != nil {
+			return nil, fmt.Errorf("field %q is invalid: %v", layouts.Choices, err)
+		}
+	}
+	// The security key data of a webauthn layout are provided by the broker, so they can only be checked to be
+	// well-formed. The relying party must be the one pinned in the broker configuration, so that a broker can't get
+	// the security keys to sign for another service.
+	if layout[layouts.Type] == layouts.WebAuthn {
+		if b.webAuthnRPID == "" {
+			return nil, fmt.Errorf("broker %q has no webauthn relying party ID configured", b.Name)
+		}
+		if rpID := layout[layouts.RPID]; rpID != b.webAuthnRPID {
+			return nil, fmt.Errorf("field %q has invalid value %q, expected %q", layouts.RPID, rpID, b.webAuthnRPID)
+		}
+		if _, err := layouts.ParseChallenge(layout[layouts.Challenge]); err != nil {
+			return nil, fmt.Errorf("field %q is invalid: %v", layouts.Challenge, err)
+		}
+		if _, err := layouts.ParseCredentials(layout[layouts.Credentials]); err != nil {
+			return nil, fmt.Errorf("field %q is invalid: %v", layouts.Credentials, err)
+		}
+	}
+	// The fields of a multi-field form must each use an entry kind supported by the client.
+	if fields, exists := layout[layouts.Fields]; exists && fields != "" {
+		items, err := layouts.ParseFields(fields)
+		if err != nil {
+			return nil, fmt.Errorf("field %q is invalid: %v", layouts.Fields, err)
+		}
+		supportedEntries := layoutValidator[layouts.Entry].supportedValues
+		for _, item := range items {
+			if !slices.Contains(supportedEntries, item.Entry) {
+				return nil, fmt.Errorf("field %q has invalid entry %q, expected one of %s", item.ID, item.Entry, strings.Join(supportedEntries, ","))
+			}
+		}
+	}
 	return layout, nil
 }
 
+// IsUserAllowed returns whether the broker access policy allows the given user.
+func (b Broker) IsUserAllowed(username string) bool {
+	if len(b.access.usernames) == 0 {
+		return true
+	}
+	for _, re := range b.access.usernames {
+		if re.MatchString(username) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsServiceAllowed returns whether the broker access policy allows the given PAM service. An empty service is not
+// allowed when the policy restricts the services.
+func (b Broker) IsServiceAllowed(service string) bool {
+	return len(b.access.services) == 0 || slices.Contains(b.access.services, service)
+}
+
+// checkRequiredGroups returns an error if the user is not a member of all the groups required by the broker access
+// policy.
+func (b Broker) checkRequiredGroups(info types.UserInfo) error {
+	for _, required := range b.access.requiredGroups {
+		if !slices.ContainsFunc(info.Groups, func(g types.GroupInfo) bool {
+			return strings.EqualFold(g.Name, required)
+		}) {
+			return fmt.Errorf("user %q is not a member of the required group %q", info.Name, required)
+		}
+	}
+	return nil
+}
+
 // parseSessionID strips broker ID prefix from sessionID.
 func (b Broker) parseSessionID(sessionID string) string {
-	return strings.TrimPrefix(sessionID, fmt.Sprintf("%s-", b.ID))
+	return BrokerSessionID(b.ID, sessionID)
+}
+
+// BrokerSessionID returns the ID known by the broker brokerID of the session sessionID, stripping the broker ID
+// prefix and the random token from it.
+func BrokerSessionID(brokerID, sessionID string) string {
+	id, found := strings.CutPrefix(sessionID, brokerID+"-")
+	if !found {
+		return sessionID
+	}
+	if _, brokerSessionID, found := strings.Cut(id, "-"); found {
+		return brokerSessionID
+	}
+	return id
 }
 
 // unmarshalUserInfo tries to unmarshal the rawMsg into a userinfo.
@@ -364,6 +801,46 @@ func validateUserInfo(uInfo types.UserInfo) (err error) {
 	return nil
 }
 
+// envNameRegex matches the valid names of environment variables.
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvVars are the environment variables that brokers can't set, as they are set by the login process or could
+// be used to run arbitrary code in the session.
+var reservedEnvVars = []string{
+	// Set by the login process.
+	"HOME", "SHELL", "USER", "LOGNAME", "PATH",
+	// Read by the shells.
+	"BASH_ENV", "ENV", "IFS", "CDPATH", "PS4", "PROMPT_COMMAND", "SHELLOPTS", "BASHOPTS", "GLOBIGNORE", "ZDOTDIR",
+	// Read by the C library.
+	"GCONV_PATH", "GLIBC_TUNABLES", "HOSTALIASES", "LOCPATH", "NLSPATH", "RESOLV_HOST_CONF",
+	// Read by the interpreters.
+	"PYTHONPATH", "PYTHONHOME", "PYTHONSTARTUP", "PERL5LIB", "PERLLIB", "PERL5OPT", "RUBYLIB", "RUBYOPT",
+	"NODE_OPTIONS", "NODE_PATH", "LUA_PATH", "LUA_CPATH", "JAVA_TOOL_OPTIONS", "_JAVA_OPTIONS", "JDK_JAVA_OPTIONS",
+	// Commands run by other programs.
+	"EDITOR", "VISUAL", "PAGER", "BROWSER", "SSH_ASKPASS", "SUDO_ASKPASS", "GIT_SSH", "GIT_SSH_COMMAND",
+}
+
+// reservedEnvPrefixes are the prefixes of the environment variables that brokers can't set, as they are read by the
+// dynamic linker or define shell functions.
+var reservedEnvPrefixes = []string{"LD_", "BASH_FUNC_"}
+
+// validateEnv checks that the environment variables returned by a broker can be set in the session of the user.
+func validateEnv(env map[string]string) error {
+	for name, value := range env {
+		if !envNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid variable name %q", name)
+		}
+		if slices.Contains(reservedEnvVars, name) ||
+			slices.ContainsFunc(reservedEnvPrefixes, func(p string) bool { return strings.HasPrefix(name, p) }) {
+			return fmt.Errorf("variable %q can't be set by brokers", name)
+		}
+		if strings.ContainsFunc(value, unicode.IsControl) {
+			return fmt.Errorf("value of variable %q contains control characters", name)
+		}
+	}
+	return nil
+}
+
 // unmarshalAndGetKey tries to unmarshal the content in data and returns the value of the requested key.
 func unmarshalAndGetKey(data, key string) (json.RawMessage, error) {
 	var returnedData map[string]json.RawMessage
@@ -3,7 +3,9 @@ package brokers_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -13,6 +15,8 @@ import (
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 )
@@ -33,6 +37,29 @@ var supportedLayouts = map[string]map[string]string{
 		layouts.Type:  "misconfigured-layout",
 		layouts.Entry: "required-but-misformatted",
 	},
+	"choice": {
+		layouts.Type:    layouts.Choice,
+		layouts.Label:   layouts.Optional,
+		layouts.Choices: layouts.Required,
+	},
+	"form-with-fields": {
+		layouts.Type:   layouts.Form,
+		layouts.Label:  layouts.Required,
+		layouts.Entry:  layouts.OptionalItems(entries.Chars, entries.CharsPassword),
+		layouts.Fields: layouts.Optional,
+	},
+	"form": {
+		layouts.Type:  layouts.Form,
+		layouts.Label: layouts.Required,
+		layouts.Entry: layouts.OptionalItems(entries.Chars, entries.CharsPassword),
+	},
+	"webauthn": {
+		layouts.Type:        layouts.WebAuthn,
+		layouts.Label:       layouts.Optional,
+		layouts.Challenge:   layouts.Required,
+		layouts.RPID:        layouts.Required,
+		layouts.Credentials: layouts.Optional,
+	},
 	"layout-with-spaces": {
 		layouts.Type:  "layout-with-spaces",
 		layouts.Entry: layouts.RequiredItems(" entry_type ", "other_entry_type"),
@@ -44,11 +71,13 @@ func TestNewBroker(t *testing.T) {
 
 	tests := map[string]struct {
 		configFile string
+		configDir  string
 
 		wantErr bool
 	}{
 		"No_config_means_local_broker":                        {configFile: "-"},
 		"Successfully_create_broker_with_correct_config_file": {configFile: "valid.conf"},
+		"Successfully_create_broker_with_id_and_aliases":      {configFile: "broker_a.conf", configDir: "brokers_with_ids"},
 
 		// General config errors
 		"Error_when_config_file_is_invalid":     {configFile: "invalid.conf", wantErr: true},
@@ -59,6 +88,31 @@ func TestNewBroker(t *testing.T) {
 		"Error_when_config_does_not_have_brand_icon_field":  {configFile: "no_brand_icon.conf", wantErr: true},
 		"Error_when_config_does_not_have_dbus_name_field":   {configFile: "no_dbus_name.conf", wantErr: true},
 		"Error_when_config_does_not_have_dbus_object_field": {configFile: "no_dbus_object.conf", wantErr: true},
+
+		// Timeouts errors
+		"Error_when_config_has_an_invalid_timeout": {configFile: "invalid_timeout.conf", wantErr: true},
+		"Error_when_config_has_a_negative_timeout": {configFile: "negative_timeout.conf", wantErr: true},
+
+		// Access policy errors
+		"Error_when_config_has_an_invalid_username_pattern": {configFile: "invalid_access_pattern.conf", wantErr: true},
+		"Error_when_config_has_an_unknown_access_key":       {configFile: "unknown_access_key.conf", wantErr: true},
+
+		// ID errors
+		"Error_when_config_has_an_empty_id":        {configFile: "empty_id.conf", wantErr: true},
+		"Error_when_config_has_a_reserved_id":      {configFile: "reserved_id.conf", wantErr: true},
+		"Error_when_config_has_an_invalid_alias":   {configFile: "invalid_alias.conf", wantErr: true},
+		"Error_when_config_has_its_id_as_an_alias": {configFile: "alias_is_id.conf", wantErr: true},
+
+		// Fallback errors
+		"Error_when_config_has_itself_as_fallback":           {configFile: "fallback_is_self.conf", wantErr: true},
+		"Error_when_config_has_the_local_broker_as_fallback": {configFile: "local_fallback.conf", wantErr: true},
+
+		// Broker settings errors
+		"Error_when_config_has_an_invalid_settings_section_name": {configFile: "invalid_settings_section.conf", wantErr: true},
+		"Error_when_config_has_an_invalid_setting_name":          {configFile: "invalid_setting_name.conf", wantErr: true},
+
+		// WebAuthn errors
+		"Error_when_config_has_an_invalid_webauthn_relying_party_id": {configFile: "invalid_webauthn_rp_id.conf", wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -71,6 +125,9 @@ func TestNewBroker(t *testing.T) {
 			if tc.wantErr {
 				configDir = filepath.Join(brokerConfFixtures, "invalid_brokers")
 			}
+			if tc.configDir != "" {
+				configDir = filepath.Join(brokerConfFixtures, tc.configDir)
+			}
 			if tc.configFile == "-" {
 				tc.configFile = ""
 			} else if tc.configFile != "" {
@@ -85,12 +142,38 @@ func TestNewBroker(t *testing.T) {
 			require.NoError(t, err, "NewBroker should not return an error, but did")
 
 			gotString := fmt.Sprintf("ID: %s\nName: %s\nBrand Icon: %s\n", got.ID, got.Name, got.BrandIconPath)
+			if len(got.Aliases) > 0 {
+				gotString += fmt.Sprintf("Aliases: %s\n", strings.Join(got.Aliases, ", "))
+			}
 
 			golden.CheckOrUpdate(t, gotString)
 		})
 	}
 }
 
+func TestBrokerSessionID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		sessionID string
+
+		want string
+	}{
+		"Strips_broker_ID_and_token":                {sessionID: "broker-0123abcd-session", want: "session"},
+		"Keeps_dashes_of_broker_session_ID":         {sessionID: "broker-0123abcd-session-with-dashes", want: "session-with-dashes"},
+		"Strips_broker_ID_of_session_without_token": {sessionID: "broker-session", want: "session"},
+		"Keeps_session_ID_of_another_broker":        {sessionID: "other-0123abcd-session", want: "other-0123abcd-session"},
+		"Keeps_session_ID_without_broker_ID":        {sessionID: "session", want: "session"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, brokers.BrokerSessionID("broker", tc.sessionID), "Unexpected broker session ID")
+		})
+	}
+}
+
 func TestGetAuthenticationModes(t *testing.T) {
 	t.Parallel()
 
@@ -156,6 +239,9 @@ func TestSelectAuthenticationMode(t *testing.T) {
 		"Successfully_select_mode_with_required_value":         {sessionID: "sam_success_required_entry"},
 		"Successfully_select_mode_with_optional_value":         {sessionID: "sam_success_optional_entry", supportedUILayouts: []string{"optional-entry"}},
 		"Successfully_select_mode_with_missing_optional_value": {sessionID: "sam_missing_optional_entry", supportedUILayouts: []string{"optional-entry"}},
+		"Successfully_select_mode_with_choices":                {sessionID: "sam_success_choice", supportedUILayouts: []string{"choice"}},
+		"Successfully_select_mode_with_fields":                 {sessionID: "sam_success_fields", supportedUILayouts: []string{"form-with-fields"}},
+		"Successfully_select_mode_with_webauthn":               {sessionID: "sam_success_webauthn", supportedUILayouts: []string{"webauthn"}},
 
 		// broker errors
 		"Error_when_selecting_invalid_auth_mode":              {sessionID: "sam_error", wantErr: true},
@@ -170,6 +256,14 @@ func TestSelectAuthenticationMode(t *testing.T) {
 		"Error_when_returns_layout_with_unknown_field":          {sessionID: "sam_unknown_field", wantErr: true},
 		"Error_when_returns_layout_with_invalid_required_value": {sessionID: "sam_invalid_required_entry", wantErr: true},
 		"Error_when_returns_layout_with_invalid_optional_value": {sessionID: "sam_invalid_optional_entry", wantErr: true},
+		"Error_when_returns_choice_layout_without_choices":      {sessionID: "sam_missing_choices", supportedUILayouts: []string{"choice"}, wantErr: true},
+		"Error_when_returns_choice_layout_with_invalid_choices": {sessionID: "sam_invalid_choices", supportedUILayouts: []string{"choice"}, wantErr: true},
+		"Error_when_returns_fields_not_supported_by_the_client": {sessionID: "sam_success_fields", supportedUILayouts: []string{"form"}, wantErr: true},
+		"Error_when_returns_form_layout_with_invalid_fields":    {sessionID: "sam_invalid_fields", supportedUILayouts: []string{"form-with-fields"}, wantErr: true},
+		"Error_when_returns_fields_with_unsupported_entry":      {sessionID: "sam_fields_with_unsupported_entry", supportedUILayouts: []string{"form-with-fields"}, wantErr: true},
+		"Error_when_returns_webauthn_with_invalid_challenge":    {sessionID: "sam_invalid_webauthn_challenge", supportedUILayouts: []string{"webauthn"}, wantErr: true},
+		"Error_when_returns_webauthn_with_invalid_credentials":  {sessionID: "sam_invalid_webauthn_credentials", supportedUILayouts: []string{"webauthn"}, wantErr: true},
+		"Error_when_returns_webauthn_for_another_relying_party": {sessionID: "sam_webauthn_other_rp_id", supportedUILayouts: []string{"webauthn"}, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -211,6 +305,8 @@ func TestIsAuthenticated(t *testing.T) {
 		secondCall bool
 
 		cancelFirstCall bool
+
+		wantProgress []string
 	}{
 		"Successfully_authenticate":                                        {sessionID: "success"},
 		"Successfully_authenticate_after_cancelling_first_call":            {sessionID: "ia_second_call", secondCall: true},
@@ -222,6 +318,11 @@ func TestIsAuthenticated(t *testing.T) {
 		"No_error_when_broker_returns_userinfo_with_group_with_empty_UGID": {sessionID: "ia_info_empty_ugid"},
 		"No_error_when_broker_returns_userinfo_with_mismatching_username":  {sessionID: "ia_info_mismatching_user_name"},
 
+		"Successfully_authenticate_with_progress_messages": {
+			sessionID:    "ia_progress",
+			wantProgress: []string{"Push sent to your phone", "Number to match: 42"},
+		},
+
 		// broker errors
 		"Error_when_authenticating":                                           {sessionID: "ia_error"},
 		"Error_on_empty_data_even_if_granted":                                 {sessionID: "ia_empty_data"},
@@ -253,10 +354,13 @@ func TestIsAuthenticated(t *testing.T) {
 			// Add username to the ongoing requests
 			b.AddOngoingUserRequest(sessionID, t.Name()+testutils.IDSeparator+tc.sessionID)
 
+			var gotProgress []string
 			done := make(chan struct{})
 			go func() {
 				defer close(done)
-				access, gotData, err := b.IsAuthenticated(ctx, sessionID, "password")
+				access, gotData, err := b.IsAuthenticatedWithProgress(ctx, sessionID, "password", func(message string) {
+					gotProgress = append(gotProgress, message)
+				})
 				firstCallReturn = fmt.Sprintf("FIRST CALL:\n\taccess: %s\n\tdata: %s\n\terr: %v\n", access, gotData, err)
 			}()
 
@@ -273,6 +377,8 @@ func TestIsAuthenticated(t *testing.T) {
 			}
 
 			<-done
+			require.Equal(t, tc.wantProgress, gotProgress, "Progress messages should match")
+
 			gotStr := firstCallReturn + secondCallReturn
 			golden.CheckOrUpdate(t, gotStr)
 		})
@@ -346,9 +452,454 @@ func TestUserPreCheck(t *testing.T) {
 	}
 }
 
+func TestConfigure(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		extraConfig string
+
+		wantSettings       map[string]string
+		wantConfigureCalls int
+		wantErr            bool
+	}{
+		"Sends_settings_to_broker_before_the_first_call": {
+			extraConfig: "[broker]\nissuer = https://example.com\nclient_id = some-client-id\n[users]\nallowed_users = OWNER, user1@example.com\n",
+			wantSettings: map[string]string{
+				"broker.issuer":       "https://example.com",
+				"broker.client_id":    "some-client-id",
+				"users.allowed_users": "OWNER, user1@example.com",
+			},
+			wantConfigureCalls: 1,
+		},
+		"Does_not_send_sections_used_by_authd": {
+			extraConfig:        "[timeouts]\nnew_session = 10s\n[access]\nallowed_services = sshd\n[broker]\nissuer = https://example.com\n",
+			wantSettings:       map[string]string{"broker.issuer": "https://example.com"},
+			wantConfigureCalls: 1,
+		},
+		"Does_not_configure_broker_without_settings": {},
+
+		"Error_when_broker_rejects_settings": {extraConfig: "[broker]\nreject = true\n", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newBrokerForTestsWithConfig(t, "", "", tc.extraConfig)
+
+			// The broker is only configured once, whatever the number of calls.
+			for range 2 {
+				_, err := b.UserPreCheck(context.Background(), "user-pre-check")
+				if tc.wantErr {
+					require.Error(t, err, "UserPreCheck should return an error when the broker can't be configured, but did not")
+					continue
+				}
+				require.NoError(t, err, "UserPreCheck should not return an error, but did")
+			}
+
+			settings, calls, err := testutils.BrokerMockSettings(b.Name)
+			require.NoError(t, err, "Setup: could not get broker mock settings")
+			require.Equal(t, tc.wantSettings, settings, "Broker should receive the settings from its configuration file")
+			require.Equal(t, tc.wantConfigureCalls, calls, "Broker should be configured the expected number of times")
+		})
+	}
+}
+
+func TestConfigureAfterBrokerRestart(t *testing.T) {
+	t.Parallel()
+
+	cfgDir := t.TempDir()
+	brokerName := t.Name()
+
+	cfgPath, stopBroker, err := testutils.StartBusBrokerMock(cfgDir, brokerName)
+	require.NoError(t, err, "Setup: could not start bus broker mock")
+	f, err := os.OpenFile(cfgPath, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err, "Setup: could not open broker configuration file")
+	_, err = f.WriteString("[broker]\nissuer = https://example.com\n")
+	require.NoError(t, err, "Setup: could not extend broker configuration file")
+	require.NoError(t, f.Close(), "Setup: could not close broker configuration file")
+
+	conn, err := testutils.GetSystemBusConnection(t)
+	require.NoError(t, err, "Setup: could not connect to system bus")
+	t.Cleanup(func() { require.NoError(t, conn.Close(), "Teardown: Failed to close the connection") })
+
+	b, err := brokers.NewBroker(context.Background(), cfgPath, conn)
+	require.NoError(t, err, "Setup: could not create broker")
+
+	_, err = b.UserPreCheck(context.Background(), "user-pre-check")
+	require.NoError(t, err, "UserPreCheck should not return an error, but did")
+	_, calls, err := testutils.BrokerMockSettings(brokerName)
+	require.NoError(t, err, "Setup: could not get broker mock settings")
+	require.Equal(t, 1, calls, "Broker should be configured before the first call")
+
+	// Restart the broker, which gets a new unique name on the bus.
+	stopBroker()
+	_, stopBroker, err = testutils.StartBusBrokerMock(t.TempDir(), brokerName)
+	require.NoError(t, err, "Setup: could not restart bus broker mock")
+	t.Cleanup(stopBroker)
+
+	// The restart is notified asynchronously by the bus, so the first calls can still reach the new instance unconfigured.
+	require.Eventually(t, func() bool {
+		_, err = b.UserPreCheck(context.Background(), "user-pre-check")
+		require.NoError(t, err, "UserPreCheck should not return an error, but did")
+		_, calls, err = testutils.BrokerMockSettings(brokerName)
+		require.NoError(t, err, "Setup: could not get broker mock settings")
+		return calls > 0
+	}, 5*time.Second, 10*time.Millisecond, "Restarted broker should be configured again")
+	settings, calls, err := testutils.BrokerMockSettings(brokerName)
+	require.NoError(t, err, "Setup: could not get broker mock settings")
+	require.Equal(t, 1, calls, "Restarted broker should be configured only once")
+	require.Equal(t, map[string]string{"broker.issuer": "https://example.com"}, settings, "Restarted broker should receive the settings")
+}
+
+func TestUserInfo(t *testing.T) {
+	t.Parallel()
+
+	b := newBrokerForTests(t, "", "")
+
+	tests := map[string]struct {
+		username string
+
+		wantErr bool
+	}{
+		"Successfully_get_user_info": {username: "user-pre-check"},
+
+		"Error_if_user_is_not_available":                 {username: "unexistent", wantErr: true},
+		"Error_if_broker_does_not_return_the_groups":     {username: "user-pre-check-no-groups", wantErr: true},
+		"Error_if_broker_returns_the_info_of_other_user": {username: "user-pre-check-other-user", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := b.UserInfo(context.Background(), tc.username)
+			if tc.wantErr {
+				require.Error(t, err, "UserInfo should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "UserInfo should not return an error, but did")
+
+			golden.CheckOrUpdateYAML(t, got)
+		})
+	}
+}
+
+func TestCheckAccount(t *testing.T) {
+	t.Parallel()
+
+	b := newBrokerForTests(t, "", "")
+
+	tests := map[string]struct {
+		username string
+
+		wantStatus          string
+		wantMsg             string
+		wantNotSupportedErr bool
+		wantErr             bool
+	}{
+		"Account_is_valid":                   {username: "user-account-valid", wantStatus: auth.AccountValid},
+		"Account_expired":                    {username: "user-account-expired", wantStatus: auth.AccountExpired, wantMsg: "Your account expired"},
+		"Account_requires_a_password_change": {username: "user-account-password-change", wantStatus: auth.AccountPasswordChangeRequired},
+		"Account_is_denied":                  {username: "user-account-denied", wantStatus: auth.AccountDenied, wantMsg: "Your account was disabled"},
+
+		"Error_if_broker_does_not_support_it":       {username: "user-without-account-check", wantNotSupportedErr: true, wantErr: true},
+		"Error_if_broker_returns_an_invalid_status": {username: "user-account-invalid-status", wantErr: true},
+		"Error_if_broker_errors_out":                {username: "user-account-error", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStatus, gotMsg, err := b.CheckAccount(context.Background(), tc.username)
+			if tc.wantErr {
+				require.Error(t, err, "CheckAccount should return an error, but did not")
+				require.Equal(t, tc.wantNotSupportedErr, errors.Is(err, brokers.ErrNotSupported),
+					"CheckAccount should only report that account checks are not supported if the broker does not support them")
+				return
+			}
+			require.NoError(t, err, "CheckAccount should not return an error, but did")
+			require.Equal(t, tc.wantStatus, gotStatus, "CheckAccount should return the expected status")
+			require.Equal(t, tc.wantMsg, gotMsg, "CheckAccount should return the expected message")
+		})
+	}
+}
+
+func TestUserSession(t *testing.T) {
+	t.Parallel()
+
+	b := newBrokerForTests(t, "", "")
+
+	tests := map[string]struct {
+		method   string
+		username string
+		action   string
+
+		wantEnv             map[string]string
+		wantNotSupportedErr bool
+		wantErr             bool
+	}{
+		"OpenUserSession_without_environment":    {method: "OpenUserSession", username: "user-session", wantEnv: map[string]string{}},
+		"OpenUserSession_with_environment":       {method: "OpenUserSession", username: "user-session-env", wantEnv: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_user-session-env", "AUTHD_BROKER": b.Name}},
+		"CloseUserSession":                       {method: "CloseUserSession", username: "user-session"},
+		"SetUserCredentials_with_environment":    {method: "SetUserCredentials", username: "user-session-env", action: auth.CredentialsEstablish, wantEnv: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_user-session-env", "AUTHD_BROKER": b.Name}},
+		"SetUserCredentials_deleting_them":       {method: "SetUserCredentials", username: "user-session", action: auth.CredentialsDelete, wantEnv: map[string]string{}},
+		"SetUserCredentials_reinitializing_them": {method: "SetUserCredentials", username: "user-session", action: auth.CredentialsReinitialize, wantEnv: map[string]string{}},
+		"SetUserCredentials_refreshing_them":     {method: "SetUserCredentials", username: "user-session", action: auth.CredentialsRefresh, wantEnv: map[string]string{}},
+
+		"Error_when_broker_does_not_support_OpenUserSession":    {method: "OpenUserSession", username: "user-without-session-hooks", wantNotSupportedErr: true, wantErr: true},
+		"Error_when_broker_does_not_support_CloseUserSession":   {method: "CloseUserSession", username: "user-without-session-hooks", wantNotSupportedErr: true, wantErr: true},
+		"Error_when_broker_does_not_support_SetUserCredentials": {method: "SetUserCredentials", username: "user-without-session-hooks", action: auth.CredentialsEstablish, wantNotSupportedErr: true, wantErr: true},
+		"Error_when_OpenUserSession_returns_an_invalid_env":     {method: "OpenUserSession", username: "user-session-invalid-env", wantErr: true},
+		"Error_when_SetUserCredentials_returns_an_invalid_env":  {method: "SetUserCredentials", username: "user-session-invalid-env", action: auth.CredentialsEstablish, wantErr: true},
+		"Error_when_SetUserCredentials_action_is_invalid":       {method: "SetUserCredentials", username: "user-session", action: "invalid", wantErr: true},
+		"Error_when_OpenUserSession_errors_out":                 {method: "OpenUserSession", username: "user-session-error", wantErr: true},
+		"Error_when_CloseUserSession_errors_out":                {method: "CloseUserSession", username: "user-session-error", wantErr: true},
+		"Error_when_SetUserCredentials_errors_out":              {method: "SetUserCredentials", username: "user-session-error", action: auth.CredentialsEstablish, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var env map[string]string
+			var err error
+			switch tc.method {
+			case "OpenUserSession":
+				env, err = b.OpenUserSession(context.Background(), tc.username, brokers.PAMContext{Service: "sshd"})
+			case "CloseUserSession":
+				err = b.CloseUserSession(context.Background(), tc.username, brokers.PAMContext{Service: "sshd"})
+			case "SetUserCredentials":
+				env, err = b.SetUserCredentials(context.Background(), tc.username, tc.action)
+			}
+			if tc.wantErr {
+				require.Error(t, err, "%s should return an error, but did not", tc.method)
+				require.Equal(t, tc.wantNotSupportedErr, errors.Is(err, brokers.ErrNotSupported),
+					"%s should only report that it is not supported if the broker does not support it", tc.method)
+				return
+			}
+			require.NoError(t, err, "%s should not return an error, but did", tc.method)
+			require.Equal(t, tc.wantEnv, env, "%s should return the expected environment", tc.method)
+		})
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env map[string]string
+
+		wantErr bool
+	}{
+		"Empty_environment_is_valid":                     {env: map[string]string{}},
+		"Environment_with_variables_is_valid":            {env: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_1000", "EMPTY": ""}},
+		"Variable_containing_a_reserved_prefix_is_valid": {env: map[string]string{"MY_LD_PRELOAD": "/tmp/lib.so"}},
+
+		"Error_on_invalid_variable_name":             {env: map[string]string{"1INVALID": "value"}, wantErr: true},
+		"Error_on_value_with_control_characters":     {env: map[string]string{"FOO": "bar\nbaz"}, wantErr: true},
+		"Error_on_variable_set_by_the_login_process": {env: map[string]string{"HOME": "/tmp"}, wantErr: true},
+		"Error_on_PATH":                               {env: map[string]string{"PATH": "/tmp"}, wantErr: true},
+		"Error_on_dynamic_linker_variable":            {env: map[string]string{"LD_PRELOAD": "/tmp/lib.so"}, wantErr: true},
+		"Error_on_other_dynamic_linker_variable":      {env: map[string]string{"LD_AUDIT": "/tmp/lib.so"}, wantErr: true},
+		"Error_on_BASH_ENV":                           {env: map[string]string{"BASH_ENV": "/tmp/script"}, wantErr: true},
+		"Error_on_ENV":                                {env: map[string]string{"ENV": "/tmp/script"}, wantErr: true},
+		"Error_on_IFS":                                {env: map[string]string{"IFS": "/"}, wantErr: true},
+		"Error_on_PROMPT_COMMAND":                     {env: map[string]string{"PROMPT_COMMAND": "/tmp/script"}, wantErr: true},
+		"Error_on_shell_function":                     {env: map[string]string{"BASH_FUNC_ls": "() { /tmp/script; }"}, wantErr: true},
+		"Error_on_GCONV_PATH":                         {env: map[string]string{"GCONV_PATH": "/tmp"}, wantErr: true},
+		"Error_on_GLIBC_TUNABLES":                     {env: map[string]string{"GLIBC_TUNABLES": "glibc.malloc.check=3"}, wantErr: true},
+		"Error_on_PYTHONPATH":                         {env: map[string]string{"PYTHONPATH": "/tmp"}, wantErr: true},
+		"Error_on_PERL5LIB":                           {env: map[string]string{"PERL5LIB": "/tmp"}, wantErr: true},
+		"Error_on_NODE_OPTIONS":                       {env: map[string]string{"NODE_OPTIONS": "--require /tmp/script.js"}, wantErr: true},
+		"Error_on_command_run_by_other_programs":      {env: map[string]string{"GIT_SSH_COMMAND": "/tmp/script"}, wantErr: true},
+		"Error_on_reserved_variable_among_valid_ones": {env: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_1000", "PYTHONSTARTUP": "/tmp/script.py"}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := brokers.ValidateEnv(tc.env)
+			if tc.wantErr {
+				require.Error(t, err, "ValidateEnv should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ValidateEnv should not return an error, but did")
+		})
+	}
+}
+
+func TestBrokerTimeouts(t *testing.T) {
+	t.Parallel()
+
+	timeoutsConfig := `
+[timeouts]
+new_session = 200ms
+get_authentication_modes = 200ms
+select_authentication_mode = 200ms
+is_authenticated = 200ms
+is_authenticated_wait = 2s
+end_session = 200ms
+user_pre_check = 200ms
+check_account = 200ms
+user_session = 200ms
+`
+
+	tests := map[string]struct {
+		method    string
+		sessionID string
+		authData  string
+
+		wantAccess string
+		wantErr    bool
+	}{
+		"IsAuthenticated_with_secret_replies_before_timeout": {method: "IsAuthenticated", sessionID: "success", wantAccess: auth.Granted},
+		"IsAuthenticated_wait_uses_the_wait_timeout":         {method: "IsAuthenticated", sessionID: "ia_timeout", authData: `{"wait":"true"}`, wantAccess: auth.Denied},
+
+		"Error_when_NewSession_times_out":                     {method: "NewSession", sessionID: "ns_hang", wantErr: true},
+		"Error_when_GetAuthenticationModes_times_out":         {method: "GetAuthenticationModes", sessionID: "gam_hang", wantErr: true},
+		"Error_when_SelectAuthenticationMode_times_out":       {method: "SelectAuthenticationMode", sessionID: "sam_hang", wantErr: true},
+		"Error_when_IsAuthenticated_with_secret_times_out":    {method: "IsAuthenticated", sessionID: "ia_timeout", wantErr: true},
+		"Error_when_IsAuthenticated_with_wait_mode_times_out": {method: "IsAuthenticated", sessionID: "ia_wait", authData: `{"wait":"true"}`, wantErr: true},
+		"Error_when_EndSession_times_out":                     {method: "EndSession", sessionID: "es_hang", wantErr: true},
+		"Error_when_UserPreCheck_times_out":                   {method: "UserPreCheck", sessionID: "user-pre-check-hang", wantErr: true},
+		"Error_when_CheckAccount_times_out":                   {method: "CheckAccount", sessionID: "user-account-hang", wantErr: true},
+		"Error_when_OpenUserSession_times_out":                {method: "OpenUserSession", sessionID: "user-session-hang", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newBrokerForTestsWithConfig(t, "", "", timeoutsConfig)
+
+			if tc.authData == "" {
+				tc.authData = `{"secret":"password"}`
+			}
+
+			sessionID := prefixID(t, tc.sessionID)
+			var access string
+			var err error
+			switch tc.method {
+			case "NewSession":
+				_, _, err = brokers.NewSession(&b, context.Background(), sessionID)
+			case "GetAuthenticationModes":
+				_, err = b.GetAuthenticationModes(context.Background(), sessionID, nil)
+			case "SelectAuthenticationMode":
+				brokers.GenerateLayoutValidators(&b, sessionID, []map[string]string{supportedLayouts["required-entry"]})
+				_, err = b.SelectAuthenticationMode(context.Background(), sessionID, "mode1")
+			case "IsAuthenticated":
+				b.AddOngoingUserRequest(sessionID, t.Name()+testutils.IDSeparator+tc.sessionID)
+				access, _, err = b.IsAuthenticated(context.Background(), sessionID, tc.authData)
+			case "EndSession":
+				err = brokers.EndSession(&b, context.Background(), sessionID)
+			case "UserPreCheck":
+				_, err = b.UserPreCheck(context.Background(), tc.sessionID)
+			case "CheckAccount":
+				_, _, err = b.CheckAccount(context.Background(), tc.sessionID)
+			case "OpenUserSession":
+				_, err = b.OpenUserSession(context.Background(), tc.sessionID, brokers.PAMContext{})
+			}
+
+			if tc.wantErr {
+				require.Error(t, err, "%s should return an error, but did not", tc.method)
+				require.ErrorAs(t, err, &errmessages.ToDisplayError{}, "%s should return an error to display", tc.method)
+				require.ErrorContains(t, err, "took too long to respond", "%s should return a timeout error", tc.method)
+				return
+			}
+			require.NoError(t, err, "%s should not return an error, but did", tc.method)
+			require.Equal(t, tc.wantAccess, access, "%s should return the expected access", tc.method)
+		})
+	}
+}
+
+func TestBrokerAccessPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		accessConfig string
+		username     string
+		service      string
+
+		wantUserAllowed    bool
+		wantServiceAllowed bool
+		wantAccess         string
+	}{
+		"Allows_everything_without_policy": {wantUserAllowed: true, wantServiceAllowed: true, wantAccess: auth.Granted},
+		"Allows_user_matching_a_pattern": {
+			accessConfig:    "allowed_usernames = nomatch, .*_separator_success",
+			wantUserAllowed: true, wantServiceAllowed: true, wantAccess: auth.Granted,
+		},
+		"Allows_user_matching_a_pattern_with_different_case": {
+			accessConfig:    "allowed_usernames = .*_SEPARATOR_SUCCESS",
+			wantUserAllowed: true, wantServiceAllowed: true, wantAccess: auth.Granted,
+		},
+		"Allows_listed_service": {
+			accessConfig: "allowed_services = sshd, login", service: "login",
+			wantUserAllowed: true, wantServiceAllowed: true, wantAccess: auth.Granted,
+		},
+		"Grants_access_to_member_of_required_groups": {
+			accessConfig:    "required_groups = group-success",
+			wantUserAllowed: true, wantServiceAllowed: true, wantAccess: auth.Granted,
+		},
+
+		"Denies_user_not_matching_any_pattern": {
+			accessConfig:       "allowed_usernames = .*@example.com",
+			wantServiceAllowed: true, wantAccess: auth.Granted,
+		},
+		"Denies_user_matching_only_part_of_a_pattern": {
+			accessConfig:       "allowed_usernames = success",
+			wantServiceAllowed: true, wantAccess: auth.Granted,
+		},
+		"Denies_service_not_listed": {
+			accessConfig: "allowed_services = sshd, login", service: "gdm-password",
+			wantUserAllowed: true, wantAccess: auth.Granted,
+		},
+		"Denies_missing_service_when_services_are_restricted": {
+			accessConfig:    "allowed_services = sshd, login",
+			wantUserAllowed: true, wantAccess: auth.Granted,
+		},
+		"Denies_access_to_non_member_of_required_groups": {
+			accessConfig:    "required_groups = group-success, admins",
+			wantUserAllowed: true, wantServiceAllowed: true, wantAccess: auth.Denied,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var config string
+			if tc.accessConfig != "" {
+				config = "\n[access]\n" + tc.accessConfig + "\n"
+			}
+			b := newBrokerForTestsWithConfig(t, "", "", config)
+
+			if tc.username == "" {
+				tc.username = prefixID(t, "success")
+			}
+
+			require.Equal(t, tc.wantUserAllowed, b.IsUserAllowed(tc.username), "IsUserAllowed should return the expected value")
+			require.Equal(t, tc.wantServiceAllowed, b.IsServiceAllowed(tc.service), "IsServiceAllowed should return the expected value")
+
+			sessionID := prefixID(t, "success")
+			b.AddOngoingUserRequest(sessionID, tc.username)
+			access, data, err := b.IsAuthenticated(context.Background(), sessionID, `{"secret":"password"}`)
+			require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+			require.Equal(t, tc.wantAccess, access, "IsAuthenticated should return the expected access")
+			if tc.wantAccess == auth.Denied {
+				require.Contains(t, data, "not a member of the groups required", "IsAuthenticated should explain the denial")
+				require.NotContains(t, data, "admins", "IsAuthenticated should not disclose the required groups")
+			}
+		})
+	}
+}
+
 func newBrokerForTests(t *testing.T, cfgDir, brokerCfg string) (b brokers.Broker) {
 	t.Helper()
 
+	return newBrokerForTestsWithConfig(t, cfgDir, brokerCfg, "")
+}
+
+// newBrokerForTestsWithConfig is like newBrokerForTests, but extends the broker configuration file with extraConfig.
+func newBrokerForTestsWithConfig(t *testing.T, cfgDir, brokerCfg, extraConfig string) (b brokers.Broker) {
+	t.Helper()
+
 	if cfgDir == "" {
 		cfgDir = t.TempDir()
 	}
@@ -361,6 +912,10 @@ func newBrokerForTests(t *testing.T, cfgDir, brokerCfg string) (b brokers.Broker
 	require.NoError(t, err, "Setup: could not start bus broker mock")
 	t.Cleanup(cleanup)
 
+	if extraConfig != "" {
+		appendBrokerConfig(t, cfgPath, extraConfig)
+	}
+
 	conn, err := testutils.GetSystemBusConnection(t)
 	require.NoError(t, err, "Setup: could not connect to system bus")
 	t.Cleanup(func() { require.NoError(t, conn.Close(), "Teardown: Failed to close the connection") })
@@ -371,6 +926,17 @@ func newBrokerForTests(t *testing.T, cfgDir, brokerCfg string) (b brokers.Broker
 	return b
 }
 
+// appendBrokerConfig is a helper that extends the configuration file of a broker.
+func appendBrokerConfig(t *testing.T, cfgPath, extraConfig string) {
+	t.Helper()
+
+	f, err := os.OpenFile(cfgPath, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err, "Setup: could not open broker configuration file")
+	_, err = f.WriteString(extraConfig)
+	require.NoError(t, err, "Setup: could not extend broker configuration file")
+	require.NoError(t, f.Close(), "Setup: could not close broker configuration file")
+}
+
 // prefixID is a helper function that prefixes the given ID with the test name to avoid conflicts.
 func prefixID(t *testing.T, id string) string {
 	t.Helper()
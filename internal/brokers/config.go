@@ -0,0 +1,296 @@
+package brokers
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/ini.v1"
+)
+
+// brokerConfig holds the broker attributes read from its configuration file.
+type brokerConfig struct {
+	// id is the broker ID set in the configuration file, if any.
+	id string
+	// aliases are the previous IDs of the broker, whose stored assignments are migrated to id.
+	aliases []string
+	// fallbacks are the IDs of the brokers to start the session on, in order, when this one is unavailable.
+	fallbacks []string
+	name      string
+	brandIcon string
+
+	dbusName   string
+	dbusObject string
+
+	// settings are the broker-specific settings, read from the sections not used by authd, that are sent to the
+	// broker. Their keys are in the form section.key.
+	settings map[string]string
+
+	timeouts timeouts
+	access   accessPolicy
+
+	// webAuthnRPID is the only relying party ID the broker can ask the security keys to sign for, if any.
+	webAuthnRPID string
+}
+
+// readBrokerConfig reads and validates the broker configuration file.
+func readBrokerConfig(configFile string) (config brokerConfig, err error) {
+	cfg, err := ini.Load(configFile)
+	if err != nil {
+		return config, fmt.Errorf("could not read ini configuration for broker %v", err)
+	}
+
+	nameVal, err := cfg.Section("authd").GetKey("name")
+	if err != nil {
+		return config, fmt.Errorf("missing field for broker: %v", err)
+	}
+
+	brandIconVal, err := cfg.Section("authd").GetKey("brand_icon")
+	if err != nil {
+		return config, fmt.Errorf("missing field for broker: %v", err)
+	}
+
+	dbusName, err := cfg.Section("authd").GetKey("dbus_name")
+	if err != nil {
+		return config, fmt.Errorf("missing field for broker: %v", err)
+	}
+
+	objectName, err := cfg.Section("authd").GetKey("dbus_object")
+	if err != nil {
+		return config, fmt.Errorf("missing field for broker: %v", err)
+	}
+
+	// The ID is optional, and defaults to a hash of the name.
+	var id string
+	if cfg.Section("authd").HasKey("id") {
+		id = cfg.Section("authd").Key("id").String()
+		if err := validateBrokerID(id); err != nil {
+			return config, err
+		}
+	}
+
+	var aliases []string
+	for _, alias := range cfg.Section("authd").Key("aliases").Strings(",") {
+		if err := validateBrokerID(alias); err != nil {
+			return config, fmt.Errorf("invalid alias: %v", err)
+		}
+		aliases = append(aliases, alias)
+	}
+
+	var fallbacks []string
+	for _, fallback := range cfg.Section("authd").Key("fallback_brokers").Strings(",") {
+		if err := validateBrokerID(fallback); err != nil {
+			return config, fmt.Errorf("invalid fallback broker: %v", err)
+		}
+		fallbacks = append(fallbacks, fallback)
+	}
+
+	webAuthnRPID := cfg.Section("authd").Key("webauthn_rp_id").String()
+	if strings.ContainsFunc(webAuthnRPID, unicode.IsSpace) {
+		return config, fmt.Errorf("invalid webauthn relying party ID %q", webAuthnRPID)
+	}
+
+	timeouts, err := parseTimeouts(cfg)
+	if err != nil {
+		return config, err
+	}
+
+	access, err := parseAccess(cfg)
+	if err != nil {
+		return config, err
+	}
+
+	settings, err := parseSettings(cfg)
+	if err != nil {
+		return config, err
+	}
+
+	return brokerConfig{
+		id:         id,
+		aliases:    aliases,
+		fallbacks:  fallbacks,
+		name:       nameVal.String(),
+		brandIcon:  brandIconVal.String(),
+		dbusName:   dbusName.String(),
+		dbusObject: objectName.String(),
+		timeouts:   timeouts,
+		access:     access,
+		settings:   settings,
+
+		webAuthnRPID: webAuthnRPID,
+	}, nil
+}
+
+// timeouts defines how long each broker method is allowed to run before we give up on it.
+// A zero value means that the call is never timed out.
+type timeouts struct {
+	newSession               time.Duration
+	getAuthenticationModes   time.Duration
+	selectAuthenticationMode time.Duration
+	// isAuthenticated is used when the client sends a secret to be checked by the broker.
+	isAuthenticated time.Duration
+	// isAuthenticatedWait is used for interactive wait modes (QR code, device code, push notifications…), in which
+	// the broker waits for the user to complete the authentication on another device.
+	isAuthenticatedWait   time.Duration
+	endSession            time.Duration
+	cancelIsAuthenticated time.Duration
+	userPreCheck          time.Duration
+	checkAccount          time.Duration
+	// userSession is used when notifying the broker that a session of the user is opened or closed, and when setting
+	// the credentials of the user.
+	userSession time.Duration
+	// ping is used when checking that the broker is reachable for the health service.
+	ping time.Duration
+}
+
+// defaultTimeouts are the timeouts used for any method that is not set in the broker configuration file.
+var defaultTimeouts = timeouts{
+	newSession:               30 * time.Second,
+	getAuthenticationModes:   30 * time.Second,
+	selectAuthenticationMode: 30 * time.Second,
+	isAuthenticated:          2 * time.Minute,
+	isAuthenticatedWait:      15 * time.Minute,
+	endSession:               30 * time.Second,
+	cancelIsAuthenticated:    10 * time.Second,
+	userPreCheck:             30 * time.Second,
+	checkAccount:             10 * time.Second,
+	userSession:              10 * time.Second,
+	ping:                     5 * time.Second,
+}
+
+// timeoutsSection is the name of the broker configuration section holding the per-method timeouts.
+const timeoutsSection = "timeouts"
+
+// parseTimeouts reads the optional timeouts section of the broker configuration, using the default value for any
+// unset key.
+func parseTimeouts(cfg *ini.File) (t timeouts, err error) {
+	t = defaultTimeouts
+	if !cfg.HasSection(timeoutsSection) {
+		return t, nil
+	}
+
+	keys := map[string]*time.Duration{
+		"new_session":                &t.newSession,
+		"get_authentication_modes":   &t.getAuthenticationModes,
+		"select_authentication_mode": &t.selectAuthenticationMode,
+		"is_authenticated":           &t.isAuthenticated,
+		"is_authenticated_wait":      &t.isAuthenticatedWait,
+		"end_session":                &t.endSession,
+		"cancel_is_authenticated":    &t.cancelIsAuthenticated,
+		"user_pre_check":             &t.userPreCheck,
+		"check_account":              &t.checkAccount,
+		"user_session":               &t.userSession,
+		"ping":                       &t.ping,
+	}
+
+	for _, key := range cfg.Section(timeoutsSection).Keys() {
+		timeout, ok := keys[key.Name()]
+		if !ok {
+			return timeouts{}, fmt.Errorf("unknown timeout %q", key.Name())
+		}
+
+		d, err := key.Duration()
+		if err != nil {
+			return timeouts{}, fmt.Errorf("invalid value %q for timeout %q: %v", key.String(), key.Name(), err)
+		}
+		if d < 0 {
+			return timeouts{}, fmt.Errorf("timeout %q can't be negative, got %v", key.Name(), d)
+		}
+		*timeout = d
+	}
+
+	return t, nil
+}
+
+// accessPolicy defines which users and PAM services are allowed to use a broker.
+// An empty field means that there is no restriction.
+type accessPolicy struct {
+	// usernames are the patterns that the username must match, case-insensitively.
+	usernames []*regexp.Regexp
+	// requiredGroups are the groups, as returned by the broker, that the user must be member of.
+	requiredGroups []string
+	// services are the PAM services the broker can be used from.
+	services []string
+}
+
+// accessSection is the name of the broker configuration section holding the access policy.
+const accessSection = "access"
+
+// parseAccess reads the optional access section of the broker configuration.
+func parseAccess(cfg *ini.File) (a accessPolicy, err error) {
+	if !cfg.HasSection(accessSection) {
+		return a, nil
+	}
+
+	for _, key := range cfg.Section(accessSection).Keys() {
+		switch key.Name() {
+		case "allowed_usernames":
+			for _, pattern := range key.Strings(",") {
+				// Usernames must match the whole pattern, and authd handles them in lowercase.
+				re, err := regexp.Compile("(?i)^(?:" + pattern + ")$")
+				if err != nil {
+					return accessPolicy{}, fmt.Errorf("invalid username pattern %q: %v", pattern, err)
+				}
+				a.usernames = append(a.usernames, re)
+			}
+		case "required_groups":
+			a.requiredGroups = key.Strings(",")
+		case "allowed_services":
+			a.services = key.Strings(",")
+		default:
+			return accessPolicy{}, fmt.Errorf("unknown access key %q", key.Name())
+		}
+	}
+
+	return a, nil
+}
+
+// authdSections are the sections of the broker configuration used by authd, which are not sent to the broker.
+var authdSections = []string{ini.DefaultSection, "authd", timeoutsSection, accessSection}
+
+// settingNameRegex matches the valid names of the sections and keys of the broker-specific settings.
+var settingNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// parseSettings reads the broker-specific settings from all the sections not used by authd.
+func parseSettings(cfg *ini.File) (settings map[string]string, err error) {
+	for _, section := range cfg.Sections() {
+		if slices.Contains(authdSections, section.Name()) {
+			continue
+		}
+		if !settingNameRegex.MatchString(section.Name()) {
+			return nil, fmt.Errorf("invalid broker settings section name %q", section.Name())
+		}
+
+		for _, key := range section.Keys() {
+			if !settingNameRegex.MatchString(key.Name()) {
+				return nil, fmt.Errorf("invalid broker setting name %q in section %q", key.Name(), section.Name())
+			}
+			if strings.ContainsFunc(key.Value(), func(r rune) bool { return unicode.IsControl(r) && r != '\t' }) {
+				return nil, fmt.Errorf("broker setting %q in section %q can't contain control characters", key.Name(), section.Name())
+			}
+			if settings == nil {
+				settings = make(map[string]string)
+			}
+			settings[section.Name()+"."+key.Name()] = key.Value()
+		}
+	}
+
+	return settings, nil
+}
+
+// validateBrokerID ensures that the broker ID, or alias, set in the configuration file can be used.
+func validateBrokerID(id string) error {
+	if id == "" {
+		return fmt.Errorf("broker ID can't be empty")
+	}
+	if id == LocalBrokerName {
+		return fmt.Errorf("broker ID %q is reserved", id)
+	}
+	if strings.ContainsFunc(id, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		return fmt.Errorf("broker ID %q can't contain spaces or control characters", id)
+	}
+	return nil
+}
@@ -4,12 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
-	"gopkg.in/ini.v1"
 )
 
 // DbusInterface is the expected interface that should be implemented by the brokers.
@@ -18,49 +19,95 @@ const DbusInterface string = "com.ubuntu.authd.Broker"
 type dbusBroker struct {
 	name string
 
+	bus        *dbus.Conn
 	dbusObject dbus.BusObject
+
+	// settings are sent to the broker with Configure before any other call.
+	settings  map[string]string
+	configure *configureState
+}
+
+// configureState tracks which instance of the broker received its settings.
+type configureState struct {
+	mu sync.Mutex
+	// instance is incremented each time a running instance of the broker leaves the bus.
+	instance atomic.Uint64
+	// configured is the instance which received the settings, plus one so that the zero value means not configured.
+	configured atomic.Uint64
 }
 
 // newDbusBroker returns a dbus broker and broker attributes from its configuration file.
-func newDbusBroker(ctx context.Context, bus *dbus.Conn, configFile string) (b dbusBroker, name, brandIcon string, err error) {
+func newDbusBroker(ctx context.Context, bus *dbus.Conn, configFile string) (b dbusBroker, config brokerConfig, err error) {
 	defer decorate.OnError(&err, "D-Bus broker from configuration file: %q", configFile)
 
 	log.Debugf(ctx, "D-Bus broker configuration at %q", configFile)
 
-	cfg, err := ini.Load(configFile)
+	config, err = readBrokerConfig(configFile)
 	if err != nil {
-		return b, "", "", fmt.Errorf("could not read ini configuration for broker %v", err)
+		return b, config, err
 	}
 
-	nameVal, err := cfg.Section("authd").GetKey("name")
-	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
+	b = dbusBroker{
+		name:       config.name,
+		bus:        bus,
+		dbusObject: bus.Object(config.dbusName, dbus.ObjectPath(config.dbusObject)),
+		settings:   config.settings,
+		configure:  &configureState{},
 	}
 
-	brandIconVal, err := cfg.Section("authd").GetKey("brand_icon")
-	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
+	if len(b.settings) > 0 {
+		if err := b.watchRestarts(); err != nil {
+			return b, config, err
+		}
 	}
 
-	dbusName, err := cfg.Section("authd").GetKey("dbus_name")
-	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
-	}
+	return b, config, nil
+}
 
-	objectName, err := cfg.Section("authd").GetKey("dbus_object")
-	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
+// watchRestarts subscribes to the owner changes of the broker name on the bus, so that a new instance of the broker is
+// configured again.
+func (b dbusBroker) watchRestarts() error {
+	name := b.dbusObject.Destination()
+	if err := b.bus.AddMatchSignal(
+		dbus.WithMatchSender("org.freedesktop.DBus"),
+		dbus.WithMatchObjectPath("/org/freedesktop/DBus"),
+		dbus.WithMatchInterface("org.freedesktop.DBus"),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, name),
+	); err != nil {
+		return fmt.Errorf("could not watch restarts of broker %q: %w", b.name, err)
 	}
 
-	return dbusBroker{
-		name:       nameVal.String(),
-		dbusObject: bus.Object(dbusName.String(), dbus.ObjectPath(objectName.String())),
-	}, nameVal.String(), brandIconVal.String(), nil
+	signals := make(chan *dbus.Signal, 16)
+	b.bus.Signal(signals)
+
+	// The channel is closed with the bus connection.
+	go func() {
+		for s := range signals {
+			if s.Name != "org.freedesktop.DBus.NameOwnerChanged" || len(s.Body) != 3 {
+				continue
+			}
+			changedName, _ := s.Body[0].(string)
+			oldOwner, _ := s.Body[1].(string)
+			// A broker started on demand has no previous owner, and is the instance configured by the starting call.
+			if changedName != name || oldOwner == "" {
+				continue
+			}
+			b.configure.instance.Add(1)
+		}
+	}()
+
+	return nil
 }
 
 // NewSession calls the corresponding method on the broker bus and returns the session ID and encryption key.
-func (b dbusBroker) NewSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
-	call, err := b.call(ctx, "NewSession", username, lang, mode)
+// The PAM context is only sent to the brokers implementing NewSessionWithContext.
+func (b dbusBroker) NewSession(ctx context.Context, username, lang, mode string, pamContext PAMContext) (sessionID, encryptionKey string, err error) {
+	call, err := b.call(ctx, "NewSessionWithContext", username, lang, mode, pamContext.toMap())
+	if isUnknownMethod(err) {
+		log.Debugf(ctx, "Broker %q does not support NewSessionWithContext, falling back to NewSession", b.name)
+		call, err = b.call(ctx, "NewSession", username, lang, mode)
+	}
 	if err != nil {
 		return "", "", err
 	}
@@ -98,9 +145,11 @@ func (b dbusBroker) SelectAuthenticationMode(ctx context.Context, sessionID, aut
 }
 
 // IsAuthenticated calls the corresponding method on the broker bus and returns the user information and access.
-func (b dbusBroker) IsAuthenticated(_ context.Context, sessionID, authenticationData string) (access, data string, err error) {
-	// We don’t want to cancel the context when the parent call is cancelled.
-	call, err := b.call(context.Background(), "IsAuthenticated", sessionID, authenticationData)
+//
+// The context is expected to not be cancelled when the parent call is, as the caller needs to explicitly cancel the
+// call through CancelIsAuthenticated.
+func (b dbusBroker) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access, data string, err error) {
+	call, err := b.call(ctx, "IsAuthenticated", sessionID, authenticationData)
 	if err != nil {
 		return "", "", err
 	}
@@ -120,9 +169,10 @@ func (b dbusBroker) EndSession(ctx context.Context, sessionID string) (err error
 }
 
 // CancelIsAuthenticated calls the corresponding method on the broker bus.
+//
+// As for IsAuthenticated, the context is expected to not be cancelled when the parent call is.
 func (b dbusBroker) CancelIsAuthenticated(ctx context.Context, sessionID string) {
-	// We don’t want to cancel the context when the parent call is cancelled.
-	if _, err := b.call(context.Background(), "CancelIsAuthenticated", sessionID); err != nil {
+	if _, err := b.call(ctx, "CancelIsAuthenticated", sessionID); err != nil {
 		log.Errorf(ctx, "could not cancel IsAuthenticated call for session %q: %v", sessionID, err)
 	}
 }
@@ -140,20 +190,150 @@ func (b dbusBroker) UserPreCheck(ctx context.Context, username string) (userinfo
 	return userinfo, nil
 }
 
+// CheckAccount calls the corresponding method on the broker bus.
+func (b dbusBroker) CheckAccount(ctx context.Context, username string) (status, msg string, err error) {
+	call, err := b.call(ctx, "CheckAccount", username)
+	if isUnknownMethod(err) {
+		return "", "", ErrNotSupported
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if err = call.Store(&status, &msg); err != nil {
+		return "", "", err
+	}
+
+	return status, msg, nil
+}
+
+// OpenUserSession calls the corresponding method on the broker bus.
+func (b dbusBroker) OpenUserSession(ctx context.Context, username string, pamContext PAMContext) (env map[string]string, err error) {
+	call, err := b.call(ctx, "OpenUserSession", username, pamContext.toMap())
+	if isUnknownMethod(err) {
+		return nil, ErrNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = call.Store(&env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// CloseUserSession calls the corresponding method on the broker bus.
+func (b dbusBroker) CloseUserSession(ctx context.Context, username string, pamContext PAMContext) (err error) {
+	_, err = b.call(ctx, "CloseUserSession", username, pamContext.toMap())
+	if isUnknownMethod(err) {
+		return ErrNotSupported
+	}
+	return err
+}
+
+// SetUserCredentials calls the corresponding method on the broker bus.
+func (b dbusBroker) SetUserCredentials(ctx context.Context, username, action string) (env map[string]string, err error) {
+	call, err := b.call(ctx, "SetUserCredentials", username, action)
+	if isUnknownMethod(err) {
+		return nil, ErrNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = call.Store(&env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// Ping checks that the broker replies on the bus, using the standard org.freedesktop.DBus.Peer interface which brokers
+// don't need to implement. The broker is neither started nor configured by the ping.
+func (b dbusBroker) Ping(ctx context.Context) error {
+	return b.callError(b.dbusObject.CallWithContext(ctx, "org.freedesktop.DBus.Peer.Ping", dbus.FlagNoAutoStart).Err)
+}
+
+// isUnknownMethod returns true if the error is due to the broker not implementing the called method, which is the
+// case for the optional methods.
+func isUnknownMethod(err error) bool {
+	var dbusError dbus.Error
+	return errors.As(err, &dbusError) && dbusError.Name == "org.freedesktop.DBus.Error.UnknownMethod"
+}
+
 // call is an abstraction over dbus calls to ensure we wrap the returned error to an ErrorToDisplay.
 // All wrapped errors will be logged, but not returned to the UI.
+// The broker is configured first if it did not receive its settings yet.
 func (b dbusBroker) call(ctx context.Context, method string, args ...interface{}) (*dbus.Call, error) {
+	if err := b.ensureConfigured(ctx); err != nil {
+		return nil, err
+	}
+	return b.callMethod(ctx, method, args...)
+}
+
+// ensureConfigured sends its settings to the broker if the current instance of the broker did not receive them yet,
+// which is the case on the first call and after the broker restarted.
+func (b dbusBroker) ensureConfigured(ctx context.Context) error {
+	if len(b.settings) == 0 {
+		return nil
+	}
+
+	if b.configure.configured.Load() == b.configure.instance.Load()+1 {
+		return nil
+	}
+
+	b.configure.mu.Lock()
+	defer b.configure.mu.Unlock()
+
+	// If the broker restarts during the call, the instance changes and the new one is configured on the next call.
+	instance := b.configure.instance.Load()
+	if b.configure.configured.Load() == instance+1 {
+		return nil
+	}
+
+	_, err := b.callMethod(ctx, "Configure", b.settings)
+	if isUnknownMethod(err) {
+		log.Warningf(ctx, "Broker %q does not support Configure, ignoring the settings from its configuration file", b.name)
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not configure broker %q: %w", b.name, err)
+	}
+
+	b.configure.configured.Store(instance + 1)
+	return nil
+}
+
+// nameOwner returns the unique D-Bus name of the current instance of the broker, or an empty string if it's not running.
+func (b dbusBroker) nameOwner(ctx context.Context) string {
+	var owner string
+	if err := b.bus.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.GetNameOwner", 0, b.dbusObject.Destination()).Store(&owner); err != nil {
+		return ""
+	}
+	return owner
+}
+
+// callMethod calls the method on the broker bus, replacing the error when the broker is not available.
+func (b dbusBroker) callMethod(ctx context.Context, method string, args ...interface{}) (*dbus.Call, error) {
 	dbusMethod := DbusInterface + "." + method
 	call := b.dbusObject.CallWithContext(ctx, dbusMethod, 0, args...)
-	if err := call.Err; err != nil {
-		var dbusError dbus.Error
-		// If the broker is not available ib dbus, the original "method was not provided by any .service files" isn't
-		// user-friendly, so we replace it with a better message.
-		if errors.As(err, &dbusError) && dbusError.Name == "org.freedesktop.DBus.Error.ServiceUnknown" {
-			err = fmt.Errorf("couldn't connect to broker %q. Is it running?", b.name)
-		}
-		return nil, errmessages.NewToDisplayError(err)
+	if err := b.callError(call.Err); err != nil {
+		return nil, err
 	}
 
 	return call, nil
 }
+
+// callError wraps the error of a dbus call to an ErrorToDisplay.
+func (b dbusBroker) callError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var dbusError dbus.Error
+	// If the broker is not available ib dbus, the original "method was not provided by any .service files" isn't
+	// user-friendly, so we replace it with a better message.
+	if errors.As(err, &dbusError) && dbusError.Name == "org.freedesktop.DBus.Error.ServiceUnknown" {
+		err = newBrokerUnavailableError("couldn't connect to broker %q. Is it running?", b.name)
+	}
+	return errmessages.NewToDisplayError(err)
+}
@@ -0,0 +1,294 @@
+package brokers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// decoyMaxAttempts is the number of authentication attempts after which a decoy session denies access.
+const decoyMaxAttempts = 3
+
+// decoyBroker answers the sessions of the unknown users in place of a broker, without ever calling it. Its answers and
+// delays are shaped like the ones of the broker, so that the unknown users can't be told apart from the existing ones,
+// and it always ends up denying access.
+//
+// Only the kinds of the replies of the broker are mimicked, never their content, which can be specific to a user or a
+// session: the decoy sessions offer generic password forms matching the forms of the broker, and a fresh encryption
+// key of the algorithm of the broker.
+type decoyBroker struct {
+	latencies           *latencies
+	encryptionAlgorithm *atomic.Int32
+	replies             *replies
+
+	sessions   map[string]*decoySession
+	sessionsMu *sync.Mutex
+}
+
+// decoySession is the state of a session of a decoy broker.
+type decoySession struct {
+	attempts int
+	// cancel cancels the ongoing authentication, if any.
+	cancel context.CancelFunc
+	// cancelled is set when the authentication is cancelled before it started waiting.
+	cancelled bool
+}
+
+// decoyForm is the authentication mode offered by the decoy sessions for the forms of the broker with an entry.
+type decoyForm struct {
+	id        string
+	modeLabel string
+	label     string
+}
+
+// decoyForms are the authentication modes of the decoy sessions, by entry of the forms of the broker.
+var decoyForms = map[string]decoyForm{
+	entries.CharsPassword:  {id: "password", modeLabel: "Password authentication", label: "Password"},
+	entries.DigitsPassword: {id: "pin", modeLabel: "PIN authentication", label: "PIN"},
+	entries.Chars:          {id: "code", modeLabel: "Code authentication", label: "Code"},
+	entries.Digits:         {id: "otp", modeLabel: "One-time code authentication", label: "One-time code"},
+}
+
+// newDecoy returns a broker which looks like b, but whose sessions are answered by a decoy.
+func newDecoy(b *Broker) *Broker {
+	decoy := *b
+	decoy.fallbacks = nil
+	// The calls of the decoy must not be mistaken for the ones of the broker.
+	decoy.latencies = newLatencies()
+	decoy.encryptionAlgorithm = &atomic.Int32{}
+	decoy.replies = newReplies()
+	decoy.brokerer = decoyBroker{
+		latencies:           b.latencies,
+		encryptionAlgorithm: b.encryptionAlgorithm,
+		replies:             b.replies,
+		sessions:            make(map[string]*decoySession),
+		sessionsMu:          &sync.Mutex{},
+	}
+	return &decoy
+}
+
+// NewSession returns a new session ID and a new encryption key, of the algorithm of the last key of the broker.
+// Its delay is shaped by the caller, with Manager.WaitSessionStart.
+func (b decoyBroker) NewSession(ctx context.Context, username, lang, mode string, pamContext PAMContext) (sessionID, encryptionKey string, err error) {
+	// Each session gets its own key, which can't link it to the sessions of the broker or of other users.
+	if authd.EncryptionAlgorithm(b.encryptionAlgorithm.Load()) == authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305 {
+		if _, encryptionKey, err = encryption.NewX25519Key(); err != nil {
+			return "", "", fmt.Errorf("can't generate decoy encryption key: %v", err)
+		}
+	} else if encryptionKey, err = newDecoyRSAKey(); err != nil {
+		return "", "", err
+	}
+
+	sessionID = uuid.NewString()
+	b.sessionsMu.Lock()
+	b.sessions[sessionID] = &decoySession{}
+	b.sessionsMu.Unlock()
+
+	return sessionID, encryptionKey, nil
+}
+
+// newDecoyRSAKey returns a RSA public key encoded like the brokers do.
+func newDecoyRSAKey() (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", fmt.Errorf("can't generate decoy encryption key: %v", err)
+	}
+	pubASN1, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("can't marshal decoy encryption key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(pubASN1), nil
+}
+
+// GetAuthenticationModes returns an authentication mode for each kind of form recently used by the broker and
+// supported by the client, or a password authentication mode if there are none.
+func (b decoyBroker) GetAuthenticationModes(ctx context.Context, sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, err error) {
+	waitUntil(ctx, time.Now(), b.latencies.delay("GetAuthenticationModes"))
+
+	var supportedEntries []string
+	for _, layout := range supportedUILayouts {
+		if layout[layouts.Type] == layouts.Form {
+			_, items := layouts.ParseItems(layout[layouts.Entry])
+			supportedEntries = append(supportedEntries, items...)
+		}
+	}
+
+	for _, entry := range b.replies.formEntries() {
+		form, exists := decoyForms[entry]
+		if !exists || !slices.Contains(supportedEntries, entry) {
+			continue
+		}
+		authenticationModes = append(authenticationModes, map[string]string{layouts.ID: form.id, layouts.Label: form.modeLabel})
+	}
+	if len(authenticationModes) == 0 {
+		form := decoyForms[entries.CharsPassword]
+		return []map[string]string{{layouts.ID: form.id, layouts.Label: form.modeLabel}}, nil
+	}
+	return authenticationModes, nil
+}
+
+// SelectAuthenticationMode returns the form of the authentication mode, or a password form if it's not a mode of the
+// decoy sessions.
+func (b decoyBroker) SelectAuthenticationMode(ctx context.Context, sessionID, authenticationModeName string) (uiLayoutInfo map[string]string, err error) {
+	waitUntil(ctx, time.Now(), b.latencies.delay("SelectAuthenticationMode"))
+
+	entry := entries.CharsPassword
+	for e, form := range decoyForms {
+		if form.id == authenticationModeName {
+			entry = e
+		}
+	}
+	return map[string]string{
+		layouts.Type:  layouts.Form,
+		layouts.Label: decoyForms[entry].label,
+		layouts.Entry: entry,
+	}, nil
+}
+
+// IsAuthenticated asks to retry until the maximum number of attempts is reached, and then denies access.
+func (b decoyBroker) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access, data string, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	b.sessionsMu.Lock()
+	s, exists := b.sessions[sessionID]
+	if !exists {
+		b.sessionsMu.Unlock()
+		return "", "", fmt.Errorf("no decoy session %q", sessionID)
+	}
+	if s.cancelled {
+		s.cancelled = false
+		b.sessionsMu.Unlock()
+		return auth.Cancelled, "", nil
+	}
+	s.cancel = cancel
+	b.sessionsMu.Unlock()
+
+	waitUntil(ctx, time.Now(), b.latencies.delay("IsAuthenticated"))
+
+	b.sessionsMu.Lock()
+	s.cancel = nil
+	if ctx.Err() != nil {
+		b.sessionsMu.Unlock()
+		return auth.Cancelled, "", nil
+	}
+	s.attempts++
+	access = auth.Retry
+	if s.attempts >= decoyMaxAttempts {
+		access = auth.Denied
+	}
+	b.sessionsMu.Unlock()
+
+	d, err := json.Marshal(map[string]string{"message": "Authentication failure"})
+	if err != nil {
+		return "", "", fmt.Errorf("can't marshal decoy message: %v", err)
+	}
+	return access, string(d), nil
+}
+
+// replies holds the kinds of the recent replies of a broker, which its decoy sessions mimic.
+type replies struct {
+	mu sync.Mutex
+	// seenFormEntries are the entries of the forms returned by the broker, in the order they were first returned.
+	seenFormEntries []string
+}
+
+func newReplies() *replies {
+	return &replies{}
+}
+
+// recordUILayout keeps the kind of the UI layout returned by the broker. Its content is never kept.
+func (r *replies) recordUILayout(layout map[string]string) {
+	entry := layout[layouts.Entry]
+	if layout[layouts.Type] != layouts.Form || entry == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.Contains(r.seenFormEntries, entry) {
+		r.seenFormEntries = append(r.seenFormEntries, entry)
+	}
+}
+
+// formEntries returns the entries of the forms returned by the broker.
+func (r *replies) formEntries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.seenFormEntries)
+}
+
+// EndSession forgets the session.
+func (b decoyBroker) EndSession(ctx context.Context, sessionID string) (err error) {
+	b.sessionsMu.Lock()
+	defer b.sessionsMu.Unlock()
+
+	if s, exists := b.sessions[sessionID]; exists && s.cancel != nil {
+		s.cancel()
+	}
+	delete(b.sessions, sessionID)
+	return nil
+}
+
+// CancelIsAuthenticated cancels the ongoing IsAuthenticated call of the session.
+func (b decoyBroker) CancelIsAuthenticated(ctx context.Context, sessionID string) {
+	b.sessionsMu.Lock()
+	defer b.sessionsMu.Unlock()
+
+	s, exists := b.sessions[sessionID]
+	if !exists {
+		return
+	}
+	if s.cancel == nil {
+		// The call didn't start waiting yet.
+		s.cancelled = true
+		return
+	}
+	s.cancel()
+}
+
+// UserPreCheck should never be called on a decoy broker, which only handles authentication sessions.
+func (b decoyBroker) UserPreCheck(ctx context.Context, username string) (string, error) {
+	return "", errors.New("UserPreCheck should never be called on decoy broker")
+}
+
+// CheckAccount should never be called on a decoy broker, which only handles authentication sessions.
+func (b decoyBroker) CheckAccount(ctx context.Context, username string) (string, string, error) {
+	return "", "", errors.New("CheckAccount should never be called on decoy broker")
+}
+
+// OpenUserSession should never be called on a decoy broker, which only handles authentication sessions.
+func (b decoyBroker) OpenUserSession(ctx context.Context, username string, pamContext PAMContext) (map[string]string, error) {
+	return nil, errors.New("OpenUserSession should never be called on decoy broker")
+}
+
+// CloseUserSession should never be called on a decoy broker, which only handles authentication sessions.
+func (b decoyBroker) CloseUserSession(ctx context.Context, username string, pamContext PAMContext) error {
+	return errors.New("CloseUserSession should never be called on decoy broker")
+}
+
+// SetUserCredentials should never be called on a decoy broker, which only handles authentication sessions.
+func (b decoyBroker) SetUserCredentials(ctx context.Context, username, action string) (map[string]string, error) {
+	return nil, errors.New("SetUserCredentials should never be called on decoy broker")
+}
+
+// Ping should never be called on a decoy broker, which only handles authentication sessions.
+func (b decoyBroker) Ping(ctx context.Context) error {
+	return errors.New("Ping should never be called on decoy broker")
+}
@@ -0,0 +1,196 @@
+// Package encryption implements the schemes used to encrypt the secrets sent to the brokers with their encryption key.
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// x25519Info is the HKDF info of the keys derived for the X25519 scheme.
+const x25519Info = "authd X25519-ChaCha20-Poly1305"
+
+// Encrypter encrypts the secrets sent to a broker session.
+type Encrypter interface {
+	// Encrypt returns the secret encrypted for the broker session, encoded in base64.
+	Encrypt(secret string) (string, error)
+}
+
+// AlgorithmOf returns the encryption algorithm matching the encryption key returned by a broker.
+//
+// Brokers using X25519 return their public key encoded in base64 PKIX. Any other key is used with RSA-OAEP, so that
+// the existing brokers keep working unchanged.
+func AlgorithmOf(encryptionKey string) authd.EncryptionAlgorithm {
+	if _, err := parseX25519Key(encryptionKey); err == nil {
+		return authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305
+	}
+	return authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP
+}
+
+// NewEncrypter returns an Encrypter of the secrets sent to the broker session sessionID, with the base64 PKIX
+// encryptionKey returned by the broker.
+//
+// sessionID is the ID of the session known by the broker, which is bound to the secrets by the schemes supporting
+// associated data.
+func NewEncrypter(algorithm authd.EncryptionAlgorithm, encryptionKey, sessionID string) (Encrypter, error) {
+	switch algorithm {
+	case authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP:
+		pubASN1, err := base64.StdEncoding.DecodeString(encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key sent by broker is not a valid base64 encoded string: %v", err)
+		}
+		pubKey, err := x509.ParsePKIXPublicKey(pubASN1)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key send by broker is not valid: %v", err)
+		}
+		rsaPublicKey, ok := pubKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("expected encryption key sent by broker to be  RSA public key, got %T", pubKey)
+		}
+		return rsaEncrypter{publicKey: rsaPublicKey}, nil
+
+	case authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305:
+		publicKey, err := parseX25519Key(encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		return x25519Encrypter{publicKey: publicKey, sessionID: sessionID}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm %v", algorithm)
+	}
+}
+
+// parseX25519Key parses the base64 PKIX X25519 public key.
+func parseX25519Key(encryptionKey string) (*ecdh.PublicKey, error) {
+	pubASN1, err := base64.StdEncoding.DecodeString(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key sent by broker is not a valid base64 encoded string: %v", err)
+	}
+	pubKey, err := x509.ParsePKIXPublicKey(pubASN1)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key send by broker is not valid: %v", err)
+	}
+	ecdhPublicKey, ok := pubKey.(*ecdh.PublicKey)
+	if !ok || ecdhPublicKey.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("expected encryption key sent by broker to be X25519 public key, got %T", pubKey)
+	}
+	return ecdhPublicKey, nil
+}
+
+// rsaEncrypter encrypts the secrets with RSA-OAEP and SHA-512.
+type rsaEncrypter struct {
+	publicKey *rsa.PublicKey
+}
+
+// Encrypt encrypts the secret with the broker public key and returns it encoded in base64.
+func (e rsaEncrypter) Encrypt(secret string) (string, error) {
+	ciphertext, err := rsa.EncryptOAEP(sha512.New(), rand.Reader, e.publicKey, []byte(secret), nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// x25519Encrypter encrypts the secrets with ChaCha20-Poly1305, using a key agreed with an ephemeral X25519 key.
+type x25519Encrypter struct {
+	publicKey *ecdh.PublicKey
+	sessionID string
+}
+
+// Encrypt encrypts the secret with a key derived from a new ephemeral key and the broker public key, binding the
+// session ID as associated data.
+//
+// It returns the ephemeral public key, the nonce and the ciphertext, concatenated and encoded in base64.
+func (e x25519Encrypter) Encrypt(secret string) (string, error) {
+	ephemeralKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("can't generate ephemeral key: %v", err)
+	}
+	aead, err := x25519AEAD(ephemeralKey, e.publicKey, ephemeralKey.PublicKey(), e.publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	out := ephemeralKey.PublicKey().Bytes()
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("can't generate nonce: %v", err)
+	}
+	out = append(out, nonce...)
+	out = aead.Seal(out, nonce, []byte(secret), []byte(e.sessionID))
+
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// NewX25519Key generates the key of a broker session using X25519, and returns it with its public key as expected by
+// the clients.
+func NewX25519Key() (key *ecdh.PrivateKey, encryptionKey string, err error) {
+	key, err = ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, "", err
+	}
+	pubASN1, err := x509.MarshalPKIXPublicKey(key.PublicKey())
+	if err != nil {
+		return nil, "", err
+	}
+	return key, base64.StdEncoding.EncodeToString(pubASN1), nil
+}
+
+// DecryptX25519 decrypts the base64 secret encrypted for the broker session sessionID with the X25519 scheme.
+func DecryptX25519(key *ecdh.PrivateKey, sessionID, secret string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+
+	keySize := len(key.PublicKey().Bytes())
+	if len(data) < keySize+chacha20poly1305.NonceSize {
+		return "", errors.New("encrypted secret is too short")
+	}
+	ephemeralPublicKey, err := ecdh.X25519().NewPublicKey(data[:keySize])
+	if err != nil {
+		return "", fmt.Errorf("invalid ephemeral key: %v", err)
+	}
+	nonce := data[keySize : keySize+chacha20poly1305.NonceSize]
+
+	aead, err := x25519AEAD(key, ephemeralPublicKey, ephemeralPublicKey, key.PublicKey())
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := aead.Open(nil, nonce, data[keySize+chacha20poly1305.NonceSize:], []byte(sessionID))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// x25519AEAD returns the ChaCha20-Poly1305 AEAD keyed with the secret shared by key and peer, which is derived with
+// the public keys of the client and of the broker.
+func x25519AEAD(key *ecdh.PrivateKey, peer, clientPublicKey, brokerPublicKey *ecdh.PublicKey) (cipher.AEAD, error) {
+	shared, err := key.ECDH(peer)
+	if err != nil {
+		return nil, fmt.Errorf("can't agree on encryption key: %v", err)
+	}
+
+	info := append([]byte(x25519Info), clientPublicKey.Bytes()...)
+	info = append(info, brokerPublicKey.Bytes()...)
+	aeadKey := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, nil, info), aeadKey); err != nil {
+		return nil, fmt.Errorf("can't derive encryption key: %v", err)
+	}
+
+	return chacha20poly1305.New(aeadKey)
+}
@@ -0,0 +1,234 @@
+package encryption_test
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+func TestAlgorithmOf(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Setup: could not generate RSA key")
+	_, x25519EncryptionKey, err := encryption.NewX25519Key()
+	require.NoError(t, err, "Setup: could not generate X25519 key")
+	p256Key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err, "Setup: could not generate P-256 key")
+
+	testCases := map[string]struct {
+		encryptionKey string
+
+		want authd.EncryptionAlgorithm
+	}{
+		"RSA_key":    {encryptionKey: encodeKey(t, &rsaKey.PublicKey), want: authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP},
+		"X25519_key": {encryptionKey: x25519EncryptionKey, want: authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305},
+
+		"RSA_for_other_curves":    {encryptionKey: encodeKey(t, p256Key.PublicKey()), want: authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP},
+		"RSA_for_invalid_keys":    {encryptionKey: base64.StdEncoding.EncodeToString([]byte("not a key")), want: authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP},
+		"RSA_for_non_base64_keys": {encryptionKey: "not base64!", want: authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP},
+		"RSA_for_empty_keys":      {want: authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, encryption.AlgorithmOf(tc.encryptionKey), "Unexpected encryption algorithm")
+		})
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Setup: could not generate RSA key")
+	x25519Key, x25519EncryptionKey, err := encryption.NewX25519Key()
+	require.NoError(t, err, "Setup: could not generate X25519 key")
+	_, otherX25519EncryptionKey, err := encryption.NewX25519Key()
+	require.NoError(t, err, "Setup: could not generate X25519 key")
+
+	const sessionID = "session-id"
+	// The X25519 scheme has no size limit, unlike RSA-OAEP.
+	longSecret := string(make([]byte, 4096))
+
+	testCases := map[string]struct {
+		algorithm        authd.EncryptionAlgorithm
+		encryptionKey    string
+		secret           string
+		decryptSessionID string
+
+		wantNewErr     bool
+		wantEncryptErr bool
+		wantDecryptErr bool
+	}{
+		"RSA": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+			encryptionKey: encodeKey(t, &rsaKey.PublicKey),
+			secret:        "super-secret",
+		},
+		"X25519": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			encryptionKey: x25519EncryptionKey,
+			secret:        "super-secret",
+		},
+		"X25519_with_long_secret": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			encryptionKey: x25519EncryptionKey,
+			secret:        longSecret,
+		},
+		"X25519_with_empty_secret": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			encryptionKey: x25519EncryptionKey,
+		},
+
+		// Error cases
+		"Error_when_RSA_secret_is_too_long": {
+			algorithm:      authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+			encryptionKey:  encodeKey(t, &rsaKey.PublicKey),
+			secret:         longSecret,
+			wantEncryptErr: true,
+		},
+		"Error_when_RSA_key_is_not_base64": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+			encryptionKey: "not base64!",
+			wantNewErr:    true,
+		},
+		"Error_when_RSA_key_is_not_a_key": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+			encryptionKey: base64.StdEncoding.EncodeToString([]byte("not a key")),
+			wantNewErr:    true,
+		},
+		"Error_when_RSA_key_is_X25519": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+			encryptionKey: x25519EncryptionKey,
+			wantNewErr:    true,
+		},
+		"Error_when_X25519_key_is_RSA": {
+			algorithm:     authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			encryptionKey: encodeKey(t, &rsaKey.PublicKey),
+			wantNewErr:    true,
+		},
+		"Error_when_algorithm_is_unknown": {
+			algorithm:     authd.EncryptionAlgorithm(42),
+			encryptionKey: x25519EncryptionKey,
+			wantNewErr:    true,
+		},
+		"Error_when_X25519_secret_is_decrypted_for_another_session": {
+			algorithm:        authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			encryptionKey:    x25519EncryptionKey,
+			secret:           "super-secret",
+			decryptSessionID: "other-session-id",
+			wantDecryptErr:   true,
+		},
+		"Error_when_X25519_secret_is_encrypted_for_another_key": {
+			algorithm:      authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			encryptionKey:  otherX25519EncryptionKey,
+			secret:         "super-secret",
+			wantDecryptErr: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encrypter, err := encryption.NewEncrypter(tc.algorithm, tc.encryptionKey, sessionID)
+			if tc.wantNewErr {
+				require.Error(t, err, "NewEncrypter should have failed")
+				return
+			}
+			require.NoError(t, err, "NewEncrypter should not have failed")
+
+			encrypted, err := encrypter.Encrypt(tc.secret)
+			if tc.wantEncryptErr {
+				require.Error(t, err, "Encrypt should have failed")
+				return
+			}
+			require.NoError(t, err, "Encrypt should not have failed")
+			require.NotEqual(t, tc.secret, encrypted, "Secret should have been encrypted")
+
+			var decrypted string
+			switch tc.algorithm {
+			case authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP:
+				ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+				require.NoError(t, err, "Encrypted secret should be encoded in base64")
+				plaintext, err := rsa.DecryptOAEP(sha512.New(), nil, rsaKey, ciphertext, nil)
+				require.NoError(t, err, "Secret should be decrypted")
+				decrypted = string(plaintext)
+
+			case authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305:
+				decryptSessionID := sessionID
+				if tc.decryptSessionID != "" {
+					decryptSessionID = tc.decryptSessionID
+				}
+				decrypted, err = encryption.DecryptX25519(x25519Key, decryptSessionID, encrypted)
+				if tc.wantDecryptErr {
+					require.Error(t, err, "DecryptX25519 should have failed")
+					return
+				}
+				require.NoError(t, err, "DecryptX25519 should not have failed")
+
+				again, err := encrypter.Encrypt(tc.secret)
+				require.NoError(t, err, "Encrypt should not have failed")
+				require.NotEqual(t, encrypted, again, "Each secret should be encrypted with a new ephemeral key")
+			}
+			require.Equal(t, tc.secret, decrypted, "Unexpected decrypted secret")
+		})
+	}
+}
+
+func TestDecryptX25519RejectsTamperedSecrets(t *testing.T) {
+	t.Parallel()
+
+	key, encryptionKey, err := encryption.NewX25519Key()
+	require.NoError(t, err, "Setup: could not generate X25519 key")
+	encrypter, err := encryption.NewEncrypter(authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+		encryptionKey, "session-id")
+	require.NoError(t, err, "Setup: could not create encrypter")
+	encrypted, err := encrypter.Encrypt("super-secret")
+	require.NoError(t, err, "Setup: could not encrypt secret")
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err, "Setup: could not decode secret")
+
+	testCases := map[string]struct {
+		secret string
+	}{
+		"Error_when_secret_is_not_base64":      {secret: "not base64!"},
+		"Error_when_secret_is_too_short":       {secret: base64.StdEncoding.EncodeToString(data[:40])},
+		"Error_when_ephemeral_key_is_modified": {secret: tamper(data, 0)},
+		"Error_when_nonce_is_modified":         {secret: tamper(data, 32)},
+		"Error_when_ciphertext_is_modified":    {secret: tamper(data, len(data)-1)},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := encryption.DecryptX25519(key, "session-id", tc.secret)
+			require.Error(t, err, "DecryptX25519 should have failed")
+		})
+	}
+}
+
+// encodeKey returns the public key encoded as the brokers do.
+func encodeKey(t *testing.T, key any) string {
+	t.Helper()
+
+	pubASN1, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err, "Setup: could not marshal public key")
+	return base64.StdEncoding.EncodeToString(pubASN1)
+}
+
+// tamper returns data encoded in base64, with the byte at index i flipped.
+func tamper(data []byte, i int) string {
+	tampered := append([]byte(nil), data...)
+	tampered[i] ^= 0xff
+	return base64.StdEncoding.EncodeToString(tampered)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -13,13 +14,53 @@ func NewBroker(ctx context.Context, configFile string, bus *dbus.Conn) (Broker,
 	return newBroker(ctx, configFile, bus)
 }
 
+// NewSession exports the private newSession method for testing purposes.
+func NewSession(b *Broker, ctx context.Context, username string) (sessionID, encryptionKey string, err error) {
+	return b.newSession(ctx, username, "some_lang", "auth", PAMContext{})
+}
+
+// ValidateEnv exports the private validateEnv function for testing purposes.
+func ValidateEnv(env map[string]string) error {
+	return validateEnv(env)
+}
+
+// EndSession exports the private endSession method for testing purposes.
+func EndSession(b *Broker, ctx context.Context, sessionID string) error {
+	return b.endSession(ctx, sessionID)
+}
+
 // SetBrokerForSession sets the broker for a given session.
 //
 // This is to be used only in tests.
 func (m *Manager) SetBrokerForSession(b *Broker, sessionID string) {
-	m.transactionsToBrokerMu.Lock()
-	m.transactionsToBroker[sessionID] = b
-	m.transactionsToBrokerMu.Unlock()
+	m.sessionsMu.Lock()
+	m.sessions[sessionID] = &session{broker: b, startTime: time.Now(), lastActivity: time.Now()}
+	m.sessionsMu.Unlock()
+}
+
+// ReapExpiredSessions runs a single pass of the session reaper.
+func (m *Manager) ReapExpiredSessions() {
+	m.reapExpiredSessions(context.Background())
+}
+
+// CheckBrokersHealth runs a single pass of the broker pinger.
+func (m *Manager) CheckBrokersHealth() {
+	m.checkBrokersHealth(context.Background())
+}
+
+// ExpireSession makes the session look like it had no activity for longer than the session TTL.
+func (m *Manager) ExpireSession(sessionID string) {
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
+	m.sessions[sessionID].lastActivity = time.Now().Add(-m.sessionTTL - time.Second)
+}
+
+// HasLayoutValidators returns whether the broker holds layout validators for the session.
+func (b *Broker) HasLayoutValidators(sessionID string) bool {
+	b.layoutValidatorsMu.Lock()
+	defer b.layoutValidatorsMu.Unlock()
+	_, exists := b.layoutValidators[b.parseSessionID(sessionID)]
+	return exists
 }
 
 // GenerateLayoutValidators generates the layout validators and assign them to the specified broker.
@@ -69,3 +110,12 @@ func (b *Broker) AddOngoingUserRequest(sessionID, username string) {
 	defer b.ongoingUserRequestsMu.Unlock()
 	b.ongoingUserRequests[sessionID] = username
 }
+
+// SetLatencies makes all the calls of the broker look like they take d to the decoy sessions.
+func (b *Broker) SetLatencies(d time.Duration) {
+	b.latencies.mu.Lock()
+	defer b.latencies.mu.Unlock()
+	for method := range defaultLatencies {
+		b.latencies.averages[method] = d
+	}
+}
@@ -0,0 +1,99 @@
+package brokers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/log"
+)
+
+// BrokerHealthHandler is called whenever a broker becomes reachable or unreachable.
+type BrokerHealthHandler func(brokerID string, healthy bool)
+
+// brokersHealth holds the result of the last ping of each broker.
+type brokersHealth struct {
+	mu sync.Mutex
+	// unreachable are the IDs of the brokers whose last ping failed.
+	unreachable map[string]bool
+	handler     BrokerHealthHandler
+}
+
+// SetBrokerHealthHandler registers the function called when a broker becomes reachable or unreachable. It is called
+// right away with the current state of each broker. It must not call the manager back.
+func (m *Manager) SetBrokerHealthHandler(f BrokerHealthHandler) {
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+
+	m.health.handler = f
+	if f == nil {
+		return
+	}
+	for _, id := range m.brokersOrder {
+		f(id, !m.health.unreachable[id])
+	}
+}
+
+// pingBrokers periodically pings all the brokers to keep their health up to date.
+func (m *Manager) pingBrokers(ctx context.Context) {
+	defer close(m.pingerDone)
+
+	ticker := time.NewTicker(m.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		m.checkBrokersHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkBrokersHealth pings all the brokers concurrently and notifies the changes of their health.
+// The local broker is part of authd, so it's always healthy.
+func (m *Manager) checkBrokersHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, id := range m.brokersOrder {
+		if id == LocalBrokerName {
+			continue
+		}
+		b := m.brokers[id]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.setBrokerHealth(ctx, b, b.Ping(ctx))
+		}()
+	}
+	wg.Wait()
+}
+
+// setBrokerHealth records the result of the ping of the broker, notifying the handler if its health changed.
+func (m *Manager) setBrokerHealth(ctx context.Context, b *Broker, pingErr error) {
+	// The pings interrupted by the manager being stopped don't tell anything about the broker.
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+
+	unreachable := pingErr != nil
+	if m.health.unreachable[b.ID] == unreachable {
+		return
+	}
+
+	if unreachable {
+		log.Warningf(ctx, "Broker %q is unreachable: %v", b.Name, pingErr)
+		m.health.unreachable[b.ID] = true
+	} else {
+		log.Noticef(ctx, "Broker %q is reachable again", b.Name)
+		delete(m.health.unreachable, b.ID)
+	}
+
+	if m.health.handler != nil {
+		m.health.handler(b.ID, !unreachable)
+	}
+}
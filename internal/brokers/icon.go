@@ -0,0 +1,110 @@
+package brokers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ubuntu/decorate"
+)
+
+// maxBrandIconSize is the maximum size of a broker brand icon file.
+const maxBrandIconSize = 256 * 1024
+
+const (
+	// IconContentTypePNG is the content type of PNG brand icons.
+	IconContentTypePNG = "image/png"
+	// IconContentTypeSVG is the content type of SVG brand icons.
+	IconContentTypeSVG = "image/svg+xml"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Icon is the brand icon of a broker, loaded along with the broker.
+type Icon struct {
+	Data        []byte
+	ContentType string
+	// Hash is the hex-encoded SHA-256 of the data, which clients can use to cache the icon.
+	Hash string
+}
+
+// loadBrandIcon reads the brand icon of a broker and checks that it is a PNG or SVG image that is not too large.
+// A relative path is relative to the directory of the broker configuration file.
+func loadBrandIcon(path, configFile string) (icon *Icon, err error) {
+	defer decorate.OnError(&err, "invalid brand icon %q", path)
+
+	if path == "" {
+		return nil, errors.New("no path set")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(configFile), path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, errors.New("not a regular file")
+	}
+
+	// Read one byte more than allowed to detect files growing after the stat.
+	data, err := io.ReadAll(io.LimitReader(f, maxBrandIconSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBrandIconSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxBrandIconSize)
+	}
+
+	contentType, err := iconContentType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	return &Icon{
+		Data:        data,
+		ContentType: contentType,
+		Hash:        hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+// iconContentType returns the content type of the icon data, which must be a PNG or SVG image.
+func iconContentType(data []byte) (string, error) {
+	if bytes.HasPrefix(data, pngSignature) {
+		return IconContentTypePNG, nil
+	}
+
+	// An SVG image is an XML document whose root element is svg.
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", errors.New("unsupported format, only PNG and SVG images are supported")
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return "", errors.New("unsupported format, only PNG and SVG images are supported")
+			}
+		case xml.StartElement:
+			if t.Name.Local != "svg" {
+				return "", fmt.Errorf("unsupported XML document with root element %q, only SVG images are supported", t.Name.Local)
+			}
+			return IconContentTypeSVG, nil
+		}
+	}
+}
@@ -1,10 +1,16 @@
 package brokers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 )
 
@@ -93,3 +99,57 @@ func TestUnmarshalUserInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBrandIcon(t *testing.T) {
+	t.Parallel()
+
+	tooLarge := filepath.Join(t.TempDir(), "too_large.svg")
+	err := os.WriteFile(tooLarge, []byte("<svg>"+strings.Repeat(" ", maxBrandIconSize)+"</svg>"), 0600)
+	require.NoError(t, err, "Setup: could not write large icon")
+
+	configFile := filepath.Join("testdata", "brand_icons", "broker.conf")
+
+	tests := map[string]struct {
+		path string
+
+		wantContentType string
+		wantErr         bool
+	}{
+		"Loads_PNG_icon":                    {path: "icon.png", wantContentType: IconContentTypePNG},
+		"Loads_SVG_icon":                    {path: "icon.svg", wantContentType: IconContentTypeSVG},
+		"Loads_SVG_icon_with_an_XML_header": {path: "icon_with_xml_header.svg", wantContentType: IconContentTypeSVG},
+		"Loads_icon_from_an_absolute_path":  {path: filepath.Join(testutils.CurrentDir(), "testdata", "brand_icons", "icon.svg"), wantContentType: IconContentTypeSVG},
+
+		"Error_when_path_is_empty":               {wantErr: true},
+		"Error_when_file_does_not_exist":         {path: "does_not_exist.png", wantErr: true},
+		"Error_when_path_is_a_directory":         {path: ".", wantErr: true},
+		"Error_when_file_is_empty":               {path: "empty.png", wantErr: true},
+		"Error_when_file_is_not_an_image":        {path: "not_an_image.png", wantErr: true},
+		"Error_when_XML_document_is_not_an_SVG":  {path: "not_an_svg.svg", wantErr: true},
+		"Error_when_file_is_larger_than_allowed": {path: tooLarge, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			icon, err := loadBrandIcon(tc.path, configFile)
+			if tc.wantErr {
+				require.Error(t, err, "loadBrandIcon should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "loadBrandIcon should not return an error, but did")
+
+			path := tc.path
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(configFile), path)
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "Setup: could not read icon")
+			hash := sha256.Sum256(want)
+
+			require.Equal(t, want, icon.Data, "loadBrandIcon should return the content of the icon")
+			require.Equal(t, tc.wantContentType, icon.ContentType, "loadBrandIcon should detect the content type of the icon")
+			require.Equal(t, hex.EncodeToString(hash[:]), icon.Hash, "loadBrandIcon should return the hash of the icon")
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package brokers
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// latencySmoothing is the weight of the previous average when recording the latency of a call.
+	latencySmoothing = 8
+	// latencyJitter is the fraction of the average latency by which the delays vary.
+	latencyJitter = 4
+)
+
+// defaultLatencies are the latencies used before any call of the broker succeeded.
+var defaultLatencies = map[string]time.Duration{
+	"NewSession":               50 * time.Millisecond,
+	"GetAuthenticationModes":   20 * time.Millisecond,
+	"SelectAuthenticationMode": 20 * time.Millisecond,
+	"IsAuthenticated":          time.Second,
+	"UserPreCheck":             100 * time.Millisecond,
+}
+
+// latencies tracks how long the calls to a broker typically take, so that the responses which don't involve the broker
+// can be delayed to be indistinguishable from the ones which do.
+type latencies struct {
+	averages map[string]time.Duration
+	mu       sync.Mutex
+}
+
+func newLatencies() *latencies {
+	return &latencies{averages: make(map[string]time.Duration)}
+}
+
+// record updates the moving average of the latency of the method with the duration of a call started at start.
+func (l *latencies) record(method string, start time.Time) {
+	d := time.Since(start)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	average, exists := l.averages[method]
+	if !exists {
+		l.averages[method] = d
+		return
+	}
+	l.averages[method] = average + (d-average)/latencySmoothing
+}
+
+// delay returns a duration shaped like the latency of the method, varying around its average.
+func (l *latencies) delay(method string) time.Duration {
+	l.mu.Lock()
+	average, exists := l.averages[method]
+	l.mu.Unlock()
+	if !exists {
+		average = defaultLatencies[method]
+	}
+
+	jitter := average / latencyJitter
+	if jitter <= 0 {
+		return average
+	}
+	return average - jitter + rand.N(2*jitter)
+}
+
+// waitUntil sleeps until the duration since start reaches d, or ctx is done.
+func waitUntil(ctx context.Context, start time.Time, d time.Duration) {
+	t := time.NewTimer(time.Until(start.Add(d)))
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,47 @@
+package layouts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ChoiceItem is one of the options of a choice UI layout.
+type ChoiceItem struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// FormatChoices returns the value of the choices field of a choice UI layout offering the items.
+func FormatChoices(items ...ChoiceItem) string {
+	// This can't fail, as the items only hold strings.
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+// ParseChoices parses the value of the choices field of a choice UI layout, which is a JSON list of items with an id
+// and a label. There must be at least one item, and the IDs must be unique.
+func ParseChoices(choices string) (items []ChoiceItem, err error) {
+	if err := json.Unmarshal([]byte(choices), &items); err != nil {
+		return nil, fmt.Errorf("choices must be a JSON list of items with an id and a label: %v", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("no choice provided")
+	}
+
+	ids := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if item.ID == "" {
+			return nil, errors.New("choice with empty id")
+		}
+		if item.Label == "" {
+			return nil, fmt.Errorf("choice %q has an empty label", item.ID)
+		}
+		if _, exists := ids[item.ID]; exists {
+			return nil, fmt.Errorf("duplicate choice %q", item.ID)
+		}
+		ids[item.ID] = struct{}{}
+	}
+
+	return items, nil
+}
@@ -8,6 +8,10 @@ const (
 	QrCode = "qrcode"
 	// NewPassword the layout used by new password UI layouts.
 	NewPassword = "newpassword"
+	// Choice is the layout used by UI layouts letting the user pick one of the options provided by the broker.
+	Choice = "choice"
+	// WebAuthn is the layout used by UI layouts asking for an assertion of a FIDO2 security key.
+	WebAuthn = "webauthn"
 )
 
 const (
@@ -43,6 +47,16 @@ const (
 	Code = "code"
 	// RendersQrCode is the key for the layout renders qrcode.
 	RendersQrCode = "renders_qrcode"
+	// Choices is the key for the layout choices.
+	Choices = "choices"
+	// Fields is the key for the layout fields.
+	Fields = "fields"
+	// Challenge is the key for the layout webauthn challenge.
+	Challenge = "challenge"
+	// RPID is the key for the layout webauthn relying party ID.
+	RPID = "rp_id"
+	// Credentials is the key for the layout webauthn allowed credentials.
+	Credentials = "credentials"
 )
 
 var (
@@ -0,0 +1,51 @@
+package layouts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// FieldItem is one of the inputs of a multi-field form UI layout.
+type FieldItem struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Entry string `json:"entry"`
+}
+
+// FormatFields returns the value of the fields field of a form UI layout asking for the items.
+func FormatFields(items ...FieldItem) string {
+	// This can't fail, as the items only hold strings.
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+// ParseFields parses the value of the fields field of a form UI layout, which is a JSON list of items with an id, a
+// label and an entry kind. There must be at least one item, and the IDs must be unique.
+func ParseFields(fields string) (items []FieldItem, err error) {
+	if err := json.Unmarshal([]byte(fields), &items); err != nil {
+		return nil, fmt.Errorf("fields must be a JSON list of items with an id, a label and an entry: %v", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("no field provided")
+	}
+
+	ids := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if item.ID == "" {
+			return nil, errors.New("field with empty id")
+		}
+		if item.Label == "" {
+			return nil, fmt.Errorf("field %q has an empty label", item.ID)
+		}
+		if item.Entry == "" {
+			return nil, fmt.Errorf("field %q has an empty entry", item.ID)
+		}
+		if _, exists := ids[item.ID]; exists {
+			return nil, fmt.Errorf("duplicate field %q", item.ID)
+		}
+		ids[item.ID] = struct{}{}
+	}
+
+	return items, nil
+}
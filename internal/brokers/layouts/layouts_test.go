@@ -131,3 +131,164 @@ func TestRequiredWithBooleans(t *testing.T) {
 	require.Equal(t, layouts.RequiredWithBooleans, fmt.Sprintf("%s:%s,%s",
 		layouts.Required, layouts.True, layouts.False), "Unexpected value")
 }
+
+func TestParseChoices(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		choices string
+
+		wantItems []layouts.ChoiceItem
+		wantErr   bool
+	}{
+		"Parse_one_choice": {
+			choices:   `[{"id":"phone","label":"My phone"}]`,
+			wantItems: []layouts.ChoiceItem{{ID: "phone", Label: "My phone"}},
+		},
+		"Parse_multiple_choices_keeping_their_order": {
+			choices:   layouts.FormatChoices(layouts.ChoiceItem{ID: "b", Label: "Second tenant"}, layouts.ChoiceItem{ID: "a", Label: "First tenant"}),
+			wantItems: []layouts.ChoiceItem{{ID: "b", Label: "Second tenant"}, {ID: "a", Label: "First tenant"}},
+		},
+
+		"Error_when_choices_are_not_JSON":      {choices: "phone,tablet", wantErr: true},
+		"Error_when_choices_are_not_a_list":    {choices: `{"id":"phone","label":"My phone"}`, wantErr: true},
+		"Error_when_there_is_no_choice":        {choices: "[]", wantErr: true},
+		"Error_when_choice_has_no_id":          {choices: `[{"label":"My phone"}]`, wantErr: true},
+		"Error_when_choice_has_no_label":       {choices: `[{"id":"phone"}]`, wantErr: true},
+		"Error_when_choice_ids_are_duplicated": {choices: `[{"id":"phone","label":"My phone"},{"id":"phone","label":"Other"}]`, wantErr: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			items, err := layouts.ParseChoices(tc.choices)
+			if tc.wantErr {
+				require.Error(t, err, "ParseChoices should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ParseChoices should not return an error, but did")
+			require.Equal(t, tc.wantItems, items, "Unexpected choices")
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fields string
+
+		wantItems []layouts.FieldItem
+		wantErr   bool
+	}{
+		"Parse_one_field": {
+			fields:    `[{"id":"pin","label":"PIN","entry":"digits_password"}]`,
+			wantItems: []layouts.FieldItem{{ID: "pin", Label: "PIN", Entry: "digits_password"}},
+		},
+		"Parse_multiple_fields_keeping_their_order": {
+			fields: layouts.FormatFields(
+				layouts.FieldItem{ID: "username", Label: "Username", Entry: "chars"},
+				layouts.FieldItem{ID: "otp", Label: "One-time code", Entry: "digits"},
+			),
+			wantItems: []layouts.FieldItem{
+				{ID: "username", Label: "Username", Entry: "chars"},
+				{ID: "otp", Label: "One-time code", Entry: "digits"},
+			},
+		},
+
+		"Error_when_fields_are_not_JSON":      {fields: "username,otp", wantErr: true},
+		"Error_when_fields_are_not_a_list":    {fields: `{"id":"pin","label":"PIN","entry":"digits"}`, wantErr: true},
+		"Error_when_there_is_no_field":        {fields: "[]", wantErr: true},
+		"Error_when_field_has_no_id":          {fields: `[{"label":"PIN","entry":"digits"}]`, wantErr: true},
+		"Error_when_field_has_no_label":       {fields: `[{"id":"pin","entry":"digits"}]`, wantErr: true},
+		"Error_when_field_has_no_entry":       {fields: `[{"id":"pin","label":"PIN"}]`, wantErr: true},
+		"Error_when_field_ids_are_duplicated": {fields: `[{"id":"pin","label":"PIN","entry":"digits"},{"id":"pin","label":"Other","entry":"chars"}]`, wantErr: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			items, err := layouts.ParseFields(tc.fields)
+			if tc.wantErr {
+				require.Error(t, err, "ParseFields should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ParseFields should not return an error, but did")
+			require.Equal(t, tc.wantItems, items, "Unexpected fields")
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		challenge string
+
+		want    []byte
+		wantErr bool
+	}{
+		"Parse_challenge": {challenge: "Y2hhbGxlbmdl", want: []byte("challenge")},
+
+		"Error_when_challenge_is_empty":           {challenge: "", wantErr: true},
+		"Error_when_challenge_is_not_base64":      {challenge: "not base64!", wantErr: true},
+		"Error_when_challenge_is_url_safe_base64": {challenge: "-_-_", wantErr: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := layouts.ParseChallenge(tc.challenge)
+			if tc.wantErr {
+				require.Error(t, err, "ParseChallenge should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ParseChallenge should not return an error, but did")
+			require.Equal(t, tc.want, got, "Unexpected challenge")
+		})
+	}
+}
+
+func TestFormatClientData(t *testing.T) {
+	t.Parallel()
+
+	got, err := layouts.FormatClientData([]byte("challenge?"), "authd.example.com")
+	require.NoError(t, err, "FormatClientData should not return an error, but did")
+	require.JSONEq(t, `{"type": "webauthn.get", "challenge": "Y2hhbGxlbmdlPw", "origin": "https://authd.example.com"}`,
+		string(got), "Unexpected client data")
+}
+
+func TestParseCredentials(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		credentials string
+
+		want    [][]byte
+		wantErr bool
+	}{
+		"Parse_no_credential_allowing_any": {credentials: ""},
+		"Parse_one_credential":             {credentials: "Y3JlZDE=", want: [][]byte{[]byte("cred1")}},
+		"Parse_multiple_credentials": {
+			credentials: layouts.FormatCredentials([]byte("cred1"), []byte("cred2")),
+			want:        [][]byte{[]byte("cred1"), []byte("cred2")},
+		},
+		"Parse_credentials_with_spaces": {credentials: "Y3JlZDE=, Y3JlZDI=", want: [][]byte{[]byte("cred1"), []byte("cred2")}},
+
+		"Error_when_credential_is_not_base64": {credentials: "Y3JlZDE=,not base64!", wantErr: true},
+		"Error_when_credential_is_empty":      {credentials: "Y3JlZDE=,", wantErr: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := layouts.ParseCredentials(tc.credentials)
+			if tc.wantErr {
+				require.Error(t, err, "ParseCredentials should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ParseCredentials should not return an error, but did")
+			require.Equal(t, tc.want, got, "Unexpected credentials")
+		})
+	}
+}
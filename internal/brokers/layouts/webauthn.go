@@ -0,0 +1,68 @@
+package layouts
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseChallenge decodes the value of the challenge field of a webauthn UI layout, which is a non-empty base64 string.
+// The challenge is part of the client data of the assertion, see [FormatClientData].
+func ParseChallenge(challenge string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(challenge)
+	if err != nil {
+		return nil, fmt.Errorf("challenge must be base64 encoded: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty challenge")
+	}
+	return data, nil
+}
+
+// ClientData is the client data of a webauthn assertion, as defined by the WebAuthn specification.
+type ClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// FormatClientData returns the JSON client data of the assertion of the challenge for the relying party. The
+// authenticator signs its SHA-256 hash, so the broker has to check it against the challenge it sent before checking
+// the signature. The origin is the HTTPS origin of the relying party.
+func FormatClientData(challenge []byte, rpID string) ([]byte, error) {
+	return json.Marshal(ClientData{
+		Type:      "webauthn.get",
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    "https://" + rpID,
+	})
+}
+
+// FormatCredentials returns the value of the credentials field of a webauthn UI layout allowing the credential IDs.
+func FormatCredentials(ids ...[]byte) string {
+	var encoded []string
+	for _, id := range ids {
+		encoded = append(encoded, base64.StdEncoding.EncodeToString(id))
+	}
+	return strings.Join(encoded, ",")
+}
+
+// ParseCredentials decodes the value of the credentials field of a webauthn UI layout, which is a comma separated
+// list of base64 encoded credential IDs. An empty value allows any credential.
+func ParseCredentials(credentials string) (ids [][]byte, err error) {
+	if credentials == "" {
+		return nil, nil
+	}
+	for _, c := range strings.Split(credentials, ",") {
+		id, err := base64.StdEncoding.DecodeString(strings.TrimSpace(c))
+		if err != nil {
+			return nil, fmt.Errorf("credential %q must be base64 encoded: %v", c, err)
+		}
+		if len(id) == 0 {
+			return nil, errors.New("empty credential")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -10,7 +10,7 @@ type localBroker struct {
 }
 
 //nolint:unused // We still need localBroker to implement the brokerer interface, even though this method should never be called on it.
-func (b localBroker) NewSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
+func (b localBroker) NewSession(ctx context.Context, username, lang, mode string, pamContext PAMContext) (sessionID, encryptionKey string, err error) {
 	return "", "", errors.New("NewSession should never be called on local broker")
 }
 
@@ -42,3 +42,28 @@ func (b localBroker) CancelIsAuthenticated(ctx context.Context, sessionID string
 func (b localBroker) UserPreCheck(ctx context.Context, username string) (string, error) {
 	return "", errors.New("UserPreCheck should never be called on local broker")
 }
+
+//nolint:unused // We still need localBroker to implement the brokerer interface, even though this method should never be called on it.
+func (b localBroker) CheckAccount(ctx context.Context, username string) (string, string, error) {
+	return "", "", errors.New("CheckAccount should never be called on local broker")
+}
+
+//nolint:unused // We still need localBroker to implement the brokerer interface, even though this method should never be called on it.
+func (b localBroker) OpenUserSession(ctx context.Context, username string, pamContext PAMContext) (map[string]string, error) {
+	return nil, errors.New("OpenUserSession should never be called on local broker")
+}
+
+//nolint:unused // We still need localBroker to implement the brokerer interface, even though this method should never be called on it.
+func (b localBroker) CloseUserSession(ctx context.Context, username string, pamContext PAMContext) error {
+	return errors.New("CloseUserSession should never be called on local broker")
+}
+
+//nolint:unused // We still need localBroker to implement the brokerer interface, even though this method should never be called on it.
+func (b localBroker) SetUserCredentials(ctx context.Context, username, action string) (map[string]string, error) {
+	return nil, errors.New("SetUserCredentials should never be called on local broker")
+}
+
+//nolint:unused // We still need localBroker to implement the brokerer interface, even though this method should never be called on it.
+func (b localBroker) Ping(ctx context.Context) error {
+	return errors.New("Ping should never be called on local broker")
+}
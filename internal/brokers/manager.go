@@ -7,32 +7,114 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/internal/services/errmessages"
+	"github.com/ubuntu/authd/internal/services/permissions"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
 )
 
+// Config is the configuration for the broker manager.
+type Config struct {
+	// SessionTTL is how long a session can stay without any activity before being ended. Zero means never.
+	SessionTTL time.Duration `mapstructure:"session_ttl" yaml:"session_ttl"`
+	// Routes assign users to brokers based on their username. The first matching route is used.
+	Routes []Route `mapstructure:"broker_routes" yaml:"broker_routes,omitempty"`
+	// PingInterval is how often the brokers are pinged to check that they are reachable. Zero means never.
+	PingInterval time.Duration `mapstructure:"broker_ping_interval" yaml:"broker_ping_interval"`
+	// MaxSessionsPerBroker is the maximum number of concurrent authentication sessions on a broker. Zero means no limit.
+	MaxSessionsPerBroker int `mapstructure:"max_sessions_per_broker" yaml:"max_sessions_per_broker"`
+	// MaxSessionsPerUser is the maximum number of concurrent authentication sessions for a user. Zero means no limit.
+	MaxSessionsPerUser int `mapstructure:"max_sessions_per_user" yaml:"max_sessions_per_user"`
+	// UniformResponses makes the responses about the unknown users look like the ones about the existing users, so
+	// that remote clients can't enumerate the accounts. The unknown users get decoy sessions which always deny access.
+	UniformResponses bool `mapstructure:"uniform_responses" yaml:"uniform_responses"`
+}
+
+// DefaultConfig is the default configuration for the broker manager.
+var DefaultConfig = Config{
+	SessionTTL:           time.Hour,
+	PingInterval:         30 * time.Second,
+	MaxSessionsPerBroker: 100,
+	MaxSessionsPerUser:   10,
+}
+
+// ErrNotSessionOwner is returned when a session is used by another process than the one which started it.
+var ErrNotSessionOwner = errors.New("session was started by another process")
+
 // Manager is the object that manages the available brokers and the session->broker and user->broker relationships.
 type Manager struct {
 	brokers      map[string]*Broker
 	brokersOrder []string
+	// aliases maps the previous IDs of the brokers to their current ID.
+	aliases map[string]string
 
 	usersToBroker   map[string]*Broker
 	usersToBrokerMu sync.RWMutex
+	routes          []route
+
+	sessions   map[string]*session
+	sessionsMu sync.RWMutex
+
+	// decoys are the brokers answering the sessions of the unknown users in place of the brokers with the same ID.
+	// It is nil if uniform responses are disabled.
+	decoys map[string]*Broker
+
+	maxSessionsPerBroker int
+	maxSessionsPerUser   int
+	sessionCounts        sessionCounts
+	sessionLimitsHandler SessionLimitsHandler
 
-	transactionsToBroker   map[string]*Broker
-	transactionsToBrokerMu sync.RWMutex
+	bus *dbus.Conn
+
+	sessionTTL time.Duration
+	stopReaper func()
+	reaperDone chan struct{}
+
+	health       brokersHealth
+	pingInterval time.Duration
+	stopPinger   func()
+	pingerDone   chan struct{}
 
 	cleanup func()
 }
 
+// session holds the state of an ongoing session.
+type session struct {
+	broker     *Broker
+	username   string
+	mode       string
+	pamContext PAMContext
+	// owner is the process which started the session, and the only one allowed to use it.
+	owner permissions.Peer
+
+	startTime    time.Time
+	lastActivity time.Time
+	// activeCalls is the number of broker calls currently running for the session.
+	activeCalls int
+}
+
+// SessionInfo is the public information about an ongoing session.
+type SessionInfo struct {
+	ID           string
+	BrokerID     string
+	Username     string
+	Mode         string
+	PAMContext   PAMContext
+	StartTime    time.Time
+	LastActivity time.Time
+}
+
 // NewManager creates a new broker manager object.
-func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers []string) (m *Manager, err error) {
+func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers []string, config Config) (m *Manager, err error) {
 	defer decorate.OnError(&err /*i18n.G(*/, "can't create brokers detection object") //)
 
 	log.Debug(ctx, "Building broker detection")
@@ -60,25 +142,8 @@ func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers [
 
 	// Select all brokers in ascii order if none is configured
 	if len(configuredBrokers) == 0 {
-		log.Debug(ctx, "Auto-detecting brokers")
-
-		entries, err := os.ReadDir(brokersConfPath)
-		if errors.Is(err, fs.ErrNotExist) {
-			log.Noticef(ctx, "Broker configuration directory %q does not exist, so using only the local broker", brokersConfPath)
-		} else if err != nil {
-			return m, fmt.Errorf("could not read brokers directory to detect brokers: %v", err)
-		}
-
-		for _, e := range entries {
-			if !e.Type().IsRegular() {
-				log.Noticef(ctx, "Skipping non-regular file %q in brokers configuration directory", e.Name())
-				continue
-			}
-			if !strings.HasSuffix(e.Name(), ".conf") {
-				log.Noticef(ctx, "Skipping file %q in brokers configuration directory, only .conf files are supported", e.Name())
-				continue
-			}
-			configuredBrokers = append(configuredBrokers, e.Name())
+		if configuredBrokers, err = brokerConfigFiles(ctx, brokersConfPath); err != nil {
+			return m, err
 		}
 	}
 
@@ -87,6 +152,7 @@ func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers [
 	}
 
 	brokers := make(map[string]*Broker)
+	aliases := make(map[string]string)
 	var brokersOrder []string
 
 	// First broker is always the local one.
@@ -102,19 +168,151 @@ func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers [
 			log.Warningf(ctx, "Skipping broker %q is not correctly configured: %v", cfgFileName, err)
 			continue
 		}
+		if _, exists := brokers[b.ID]; exists {
+			log.Warningf(ctx, "Skipping broker %q: another broker has the same ID %q", cfgFileName, b.ID)
+			continue
+		}
+		if _, exists := aliases[b.ID]; exists {
+			log.Warningf(ctx, "Skipping broker %q: its ID %q is an alias of another broker", cfgFileName, b.ID)
+			continue
+		}
+		log.Debugf(ctx, "Loaded broker %q with ID %q", b.Name, b.ID)
 		brokersOrder = append(brokersOrder, b.ID)
 		brokers[b.ID] = &b
+
+		for _, alias := range b.Aliases {
+			if _, exists := brokers[alias]; exists {
+				log.Warningf(ctx, "Ignoring alias %q of broker %q: another broker has this ID", alias, b.Name)
+				continue
+			}
+			if id, exists := aliases[alias]; exists {
+				log.Warningf(ctx, "Ignoring alias %q of broker %q: it's already an alias of broker %q", alias, b.Name, id)
+				continue
+			}
+			aliases[alias] = b.ID
+		}
+	}
+
+	if config.SessionTTL < 0 {
+		return nil, fmt.Errorf("session TTL can't be negative, got %v", config.SessionTTL)
+	}
+	if config.PingInterval < 0 {
+		return nil, fmt.Errorf("broker ping interval can't be negative, got %v", config.PingInterval)
+	}
+	if config.MaxSessionsPerBroker < 0 {
+		return nil, fmt.Errorf("maximum number of sessions per broker can't be negative, got %d", config.MaxSessionsPerBroker)
+	}
+	if config.MaxSessionsPerUser < 0 {
+		return nil, fmt.Errorf("maximum number of sessions per user can't be negative, got %d", config.MaxSessionsPerUser)
 	}
 
-	return &Manager{
+	m = &Manager{
 		brokers:      brokers,
 		brokersOrder: brokersOrder,
+		aliases:      aliases,
+
+		usersToBroker: make(map[string]*Broker),
+		sessions:      make(map[string]*session),
+
+		maxSessionsPerBroker: config.MaxSessionsPerBroker,
+		maxSessionsPerUser:   config.MaxSessionsPerUser,
+		sessionCounts: sessionCounts{
+			perBroker: make(map[string]int),
+			perUser:   make(map[string]int),
+		},
 
-		usersToBroker:        make(map[string]*Broker),
-		transactionsToBroker: make(map[string]*Broker),
+		bus: bus,
+
+		sessionTTL: config.SessionTTL,
+		stopReaper: func() {},
+
+		health:       brokersHealth{unreachable: make(map[string]bool)},
+		pingInterval: config.PingInterval,
+		stopPinger:   func() {},
 
 		cleanup: cleanup,
-	}, nil
+	}
+
+	if m.routes, err = m.parseRoutes(ctx, config.Routes); err != nil {
+		return nil, err
+	}
+
+	if config.UniformResponses {
+		m.decoys = make(map[string]*Broker)
+		for id, b := range brokers {
+			if id == LocalBrokerName {
+				continue
+			}
+			m.decoys[id] = newDecoy(b)
+		}
+	}
+
+	if m.sessionTTL > 0 {
+		reaperCtx, cancel := context.WithCancel(context.Background())
+		m.stopReaper = cancel
+		m.reaperDone = make(chan struct{})
+		go m.reapSessions(reaperCtx)
+	}
+
+	if m.pingInterval > 0 {
+		pingerCtx, cancel := context.WithCancel(context.Background())
+		m.stopPinger = cancel
+		m.pingerDone = make(chan struct{})
+		go m.pingBrokers(pingerCtx)
+	}
+
+	return m, nil
+}
+
+// brokerConfigFiles returns the names of the broker configuration files in the directory, in ascii order.
+func brokerConfigFiles(ctx context.Context, brokersConfPath string) (configFiles []string, err error) {
+	log.Debug(ctx, "Auto-detecting brokers")
+
+	entries, err := os.ReadDir(brokersConfPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Noticef(ctx, "Broker configuration directory %q does not exist, so using only the local broker", brokersConfPath)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read brokers directory to detect brokers: %v", err)
+	}
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			log.Noticef(ctx, "Skipping non-regular file %q in brokers configuration directory", e.Name())
+			continue
+		}
+		if !strings.HasSuffix(e.Name(), ".conf") {
+			log.Noticef(ctx, "Skipping file %q in brokers configuration directory, only .conf files are supported", e.Name())
+			continue
+		}
+		configFiles = append(configFiles, e.Name())
+	}
+
+	return configFiles, nil
+}
+
+// CheckConfig checks the configuration files of the brokers without connecting to them, and returns the issues found,
+// such as a missing or invalid brand icon.
+func CheckConfig(ctx context.Context, brokersConfPath string) (issues []error, err error) {
+	defer decorate.OnError(&err, "can't check brokers configuration")
+
+	configFiles, err := brokerConfigFiles(ctx, brokersConfPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cfgFileName := range configFiles {
+		configFile := filepath.Join(brokersConfPath, cfgFileName)
+		cfg, err := readBrokerConfig(configFile)
+		if err != nil {
+			issues = append(issues, fmt.Errorf("broker %q is not correctly configured: %v", cfgFileName, err))
+			continue
+		}
+		if _, err := loadBrandIcon(cfg.brandIcon, configFile); err != nil {
+			issues = append(issues, fmt.Errorf("broker %q: %v", cfgFileName, err))
+		}
+	}
+
+	return issues, nil
 }
 
 // AvailableBrokers returns currently loaded and available brokers in preference order.
@@ -125,6 +323,22 @@ func (m *Manager) AvailableBrokers() (r []*Broker) {
 	return r
 }
 
+// AllowedBrokers returns the available brokers whose access policy allows the given user and PAM service, in
+// preference order. An empty username is not checked against the policy. The service is always checked, like when
+// starting a session, so an empty service excludes the brokers restricted to some services.
+func (m *Manager) AllowedBrokers(username, service string) (r []*Broker) {
+	for _, b := range m.AvailableBrokers() {
+		if username != "" && !b.IsUserAllowed(username) {
+			continue
+		}
+		if !b.IsServiceAllowed(service) {
+			continue
+		}
+		r = append(r, b)
+	}
+	return r
+}
+
 // SetDefaultBrokerForUser memorizes which broker was used for which user.
 func (m *Manager) SetDefaultBrokerForUser(brokerID, username string) error {
 	broker, err := m.brokerFromID(brokerID)
@@ -135,12 +349,25 @@ func (m *Manager) SetDefaultBrokerForUser(brokerID, username string) error {
 	// authd uses lowercase usernames
 	username = strings.ToLower(username)
 
+	if err := m.checkRoute(username, broker); err != nil {
+		return errmessages.NewToDisplayError(err)
+	}
+
 	m.usersToBrokerMu.Lock()
 	defer m.usersToBrokerMu.Unlock()
 	m.usersToBroker[username] = broker
 	return nil
 }
 
+// BrandIcon returns the brand icon of the broker, or nil if it has none.
+func (m *Manager) BrandIcon(brokerID string) (icon *Icon, err error) {
+	broker, err := m.brokerFromID(brokerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker: %v", err)
+	}
+	return broker.BrandIcon, nil
+}
+
 // BrokerForUser returns any previously selected broker for a given user, if any.
 func (m *Manager) BrokerForUser(username string) (broker *Broker) {
 	m.usersToBrokerMu.RLock()
@@ -149,41 +376,338 @@ func (m *Manager) BrokerForUser(username string) (broker *Broker) {
 }
 
 // BrokerFromSessionID returns broker currently in use for a given transaction sessionID.
+// Any call to this function marks the session as active.
 func (m *Manager) BrokerFromSessionID(id string) (broker *Broker, err error) {
-	m.transactionsToBrokerMu.RLock()
-	defer m.transactionsToBrokerMu.RUnlock()
+	// no session ID means local broker
+	if id == "" {
+		return m.brokerFromID(LocalBrokerName)
+	}
+
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
+
+	s, exists := m.sessions[id]
+	if !exists {
+		return nil, errmessages.NewToDisplayErrorf("no broker found for session %q", id)
+	}
+	s.lastActivity = time.Now()
+
+	return s.broker, nil
+}
 
+// SessionBroker returns the broker currently in use for the session id, ensuring that the session is used by the
+// process that started it. Any call to this function marks the session as active.
+func (m *Manager) SessionBroker(id string, peer permissions.Peer) (broker *Broker, err error) {
 	// no session ID means local broker
 	if id == "" {
 		return m.brokerFromID(LocalBrokerName)
 	}
 
-	broker, exists := m.transactionsToBroker[id]
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
+
+	s, exists := m.sessions[id]
 	if !exists {
-		return nil, fmt.Errorf("no broker found for session %q", id)
+		return nil, errmessages.NewToDisplayErrorf("no broker found for session %q", id)
+	}
+	if s.owner != peer {
+		log.Warningf(context.Background(), "%s: Rejecting request from process %d (uid %d), session was started by process %d (uid %d)",
+			id, peer.PID, peer.UID, s.owner.PID, s.owner.UID)
+		return nil, ErrNotSessionOwner
 	}
+	s.lastActivity = time.Now()
 
-	return broker, nil
+	return s.broker, nil
+}
+
+// newFallbackSession starts the session on the first fallback broker of the unavailable broker which allows the user.
+// The fallbacks of the fallback brokers are not used. It returns the error of the unavailable broker if no fallback
+// broker could start the session.
+func (m *Manager) newFallbackSession(unavailable *Broker, unavailableErr error, username, lang, mode string, pamContext PAMContext) (broker *Broker, sessionID, encryptionKey string, err error) {
+	ctx := context.Background()
+
+	for _, id := range unavailable.fallbacks {
+		broker, err := m.brokerFromID(id)
+		if err != nil {
+			log.Warningf(ctx, "Ignoring fallback broker of %q: %v", unavailable.Name, err)
+			continue
+		}
+		if !broker.IsUserAllowed(username) {
+			continue
+		}
+		if !broker.IsServiceAllowed(pamContext.Service) {
+			continue
+		}
+		if err := m.checkRoute(username, broker); err != nil {
+			log.Debugf(ctx, "Skipping fallback broker %q: %v", broker.Name, err)
+			continue
+		}
+		if err := m.reserveSession(broker.ID, ""); err != nil {
+			log.Warningf(ctx, "Could not start session for %q on fallback broker %q: %v", username, broker.Name, err)
+			continue
+		}
+
+		sessionID, encryptionKey, err = broker.newSession(ctx, username, lang, mode, pamContext)
+		if err != nil {
+			m.releaseSession(broker.ID, "")
+			log.Warningf(ctx, "Could not start session for %q on fallback broker %q: %v", username, broker.Name, err)
+			continue
+		}
+
+		log.Noticef(ctx, "Broker %q is unavailable, using fallback broker %q for %q", unavailable.Name, broker.Name, username)
+		return broker, sessionID, encryptionKey, nil
+	}
+
+	return nil, "", "", unavailableErr
+}
+
+// KeepSessionAlive prevents the session from being reaped until the returned function is called.
+// This should be used around broker calls that can take longer than the session TTL, such as IsAuthenticated.
+func (m *Manager) KeepSessionAlive(id string) (release func()) {
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
+
+	s, exists := m.sessions[id]
+	if !exists {
+		return func() {}
+	}
+	s.activeCalls++
+
+	return func() {
+		m.sessionsMu.Lock()
+		defer m.sessionsMu.Unlock()
+		s.activeCalls--
+		s.lastActivity = time.Now()
+	}
+}
+
+// Sessions returns the information about all the ongoing sessions, sorted by start time.
+func (m *Manager) Sessions() (sessions []SessionInfo) {
+	m.sessionsMu.RLock()
+	defer m.sessionsMu.RUnlock()
+
+	for id, s := range m.sessions {
+		sessions = append(sessions, SessionInfo{
+			ID:           id,
+			BrokerID:     s.broker.ID,
+			Username:     s.username,
+			Mode:         s.mode,
+			PAMContext:   s.pamContext,
+			StartTime:    s.startTime,
+			LastActivity: s.lastActivity,
+		})
+	}
+	slices.SortFunc(sessions, func(a, b SessionInfo) int {
+		if c := a.StartTime.Compare(b.StartTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return sessions
 }
 
 // NewSession create a new session for the broker and store the sesssionID on the manager.
-func (m *Manager) NewSession(brokerID, username, lang, mode string) (sessionID string, encryptionKey string, err error) {
+// The PAM context is forwarded to the broker and checked against its access policy.
+// The session is bound to the owner process, which is the only one allowed to use it with SessionBroker.
+// It returns the broker the session was started on, which is a fallback broker if the broker is unavailable.
+func (m *Manager) NewSession(brokerID, username, lang, mode string, pamContext PAMContext, owner permissions.Peer) (broker *Broker, sessionID string, encryptionKey string, err error) {
+	return m.newSession(context.Background(), brokerID, username, lang, mode, pamContext, owner, false)
+}
+
+// NewDecoySession is like NewSession, but the session is answered by the decoy of the broker, which never calls it and
+// always denies access. It is used for the unknown users when uniform responses are enabled, followed by
+// WaitSessionStart like the real sessions.
+func (m *Manager) NewDecoySession(brokerID, username, lang, mode string, pamContext PAMContext, owner permissions.Peer) (broker *Broker, sessionID string, encryptionKey string, err error) {
+	return m.newSession(context.Background(), brokerID, username, lang, mode, pamContext, owner, true)
+}
+
+// WaitSessionStart waits, with uniform responses, until a session request received at start took as long as looking
+// for the user and starting a session on the broker, so that the requests for the existing and unknown users can't be
+// told apart by their duration.
+func (m *Manager) WaitSessionStart(ctx context.Context, brokerID string, start time.Time) {
+	if !m.UniformResponses() || brokerID == LocalBrokerName {
+		return
+	}
 	broker, err := m.brokerFromID(brokerID)
 	if err != nil {
-		return "", "", fmt.Errorf("invalid broker: %v", err)
+		return
 	}
+	waitUntil(ctx, start, m.userPreCheckDelay()+broker.latencies.delay("NewSession"))
+}
 
-	sessionID, encryptionKey, err = broker.newSession(context.Background(), username, lang, mode)
+// newSession starts the session on the broker, or on its decoy if decoy is true.
+func (m *Manager) newSession(ctx context.Context, brokerID, username, lang, mode string, pamContext PAMContext, owner permissions.Peer, decoy bool) (broker *Broker, sessionID string, encryptionKey string, err error) {
+	broker, err = m.brokerFromID(brokerID)
 	if err != nil {
-		return "", "", err
+		return nil, "", "", fmt.Errorf("invalid broker: %v", err)
+	}
+	if decoy {
+		if broker, err = m.decoy(broker); err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	if !broker.IsUserAllowed(username) {
+		return nil, "", "", errmessages.NewToDisplayErrorf("user %q is not allowed to use %s", username, broker.Name)
+	}
+	if !broker.IsServiceAllowed(pamContext.Service) {
+		return nil, "", "", errmessages.NewToDisplayErrorf("%s can't be used from %q", broker.Name, pamContext.Service)
+	}
+	if err := m.checkRoute(username, broker); err != nil {
+		return nil, "", "", errmessages.NewToDisplayError(err)
+	}
+
+	if err := m.reserveSession(broker.ID, username); err != nil {
+		return nil, "", "", err
 	}
 
-	m.transactionsToBrokerMu.Lock()
-	defer m.transactionsToBrokerMu.Unlock()
+	sessionID, encryptionKey, err = broker.newSession(ctx, username, lang, mode, pamContext)
+	if err != nil && IsBrokerUnavailable(err) && len(broker.fallbacks) > 0 {
+		// The session counts against the broker it is started on.
+		m.releaseSession(broker.ID, "")
+		var fallback *Broker
+		fallback, sessionID, encryptionKey, err = m.newFallbackSession(broker, err, username, lang, mode, pamContext)
+		if err != nil {
+			m.releaseSession("", username)
+			return nil, "", "", err
+		}
+		broker = fallback
+	}
+	if err != nil {
+		m.releaseSession(broker.ID, username)
+		return nil, "", "", err
+	}
+
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
 	log.Debugf(context.Background(), "%s: New %s session for %q",
 		sessionID, mode, username)
-	m.transactionsToBroker[sessionID] = broker
-	return sessionID, encryptionKey, nil
+	now := time.Now()
+	m.sessions[sessionID] = &session{
+		broker:       broker,
+		username:     username,
+		mode:         mode,
+		pamContext:   pamContext,
+		owner:        owner,
+		startTime:    now,
+		lastActivity: now,
+	}
+	return broker, sessionID, encryptionKey, nil
+}
+
+// UniformResponses returns whether the unknown users must get the same responses as the existing ones.
+func (m *Manager) UniformResponses() bool {
+	return m.decoys != nil
+}
+
+// decoy returns the decoy of the broker.
+func (m *Manager) decoy(broker *Broker) (*Broker, error) {
+	if !m.UniformResponses() {
+		return nil, errors.New("decoy sessions are only available with uniform responses")
+	}
+	decoy, exists := m.decoys[broker.ID]
+	if !exists {
+		return nil, fmt.Errorf("broker %q has no decoy", broker.Name)
+	}
+	return decoy, nil
+}
+
+// UserPreCheck asks the brokers in preference order whether they know the user, and returns the user information from
+// the first one which does. With uniform responses, the answer for an unknown user is delayed to take as long as the
+// slowest broker takes to find a user.
+func (m *Manager) UserPreCheck(ctx context.Context, username string) (userinfo string, err error) {
+	start := time.Now()
+
+	userinfo, err = m.userPreCheck(ctx, username)
+	if err != nil && m.UniformResponses() {
+		waitUntil(ctx, start, m.userPreCheckDelay())
+	}
+	return userinfo, err
+}
+
+// userPreCheckDelay returns a duration shaped like the time the slowest broker takes to find a user.
+func (m *Manager) userPreCheckDelay() (delay time.Duration) {
+	for _, b := range m.AvailableBrokers() {
+		if b.ID != LocalBrokerName {
+			delay = max(delay, b.latencies.delay("UserPreCheck"))
+		}
+	}
+	return delay
+}
+
+// KnowsUser returns whether any broker knows the user. Unlike UserPreCheck, the answer is never delayed, so it is meant
+// to be followed by a call whose delay is shaped, like WaitSessionStart.
+func (m *Manager) KnowsUser(ctx context.Context, username string) bool {
+	_, err := m.userPreCheck(ctx, username)
+	return err == nil
+}
+
+// userPreCheck returns the user information from the first broker which knows the user.
+func (m *Manager) userPreCheck(ctx context.Context, username string) (userinfo string, err error) {
+	for _, b := range m.AvailableBrokers() {
+		// The local broker is not a real broker, so we skip it.
+		if b.ID == LocalBrokerName {
+			continue
+		}
+
+		userinfo, err = b.UserPreCheck(ctx, username)
+		if err == nil && userinfo != "" {
+			return userinfo, nil
+		}
+	}
+	return "", fmt.Errorf("user %q is not known by any broker", username)
+}
+
+// CheckAccount asks the broker whether the account of the user can still be used.
+func (m *Manager) CheckAccount(ctx context.Context, brokerID, username string) (status, msg string, err error) {
+	broker, err := m.userBroker(brokerID)
+	if err != nil {
+		return "", "", err
+	}
+	return broker.CheckAccount(ctx, username)
+}
+
+// OpenUserSession notifies the broker that a session of the user is opened, returning the environment variables to
+// set in the session.
+func (m *Manager) OpenUserSession(ctx context.Context, brokerID, username string, pamContext PAMContext) (env map[string]string, err error) {
+	broker, err := m.userBroker(brokerID)
+	if err != nil {
+		return nil, err
+	}
+	return broker.OpenUserSession(ctx, username, pamContext)
+}
+
+// CloseUserSession notifies the broker that a session of the user is closed.
+func (m *Manager) CloseUserSession(ctx context.Context, brokerID, username string, pamContext PAMContext) error {
+	broker, err := m.userBroker(brokerID)
+	if err != nil {
+		return err
+	}
+	return broker.CloseUserSession(ctx, username, pamContext)
+}
+
+// SetUserCredentials asks the broker to apply the action to the credentials of the user, returning the environment
+// variables to set in the session.
+func (m *Manager) SetUserCredentials(ctx context.Context, brokerID, username, action string) (env map[string]string, err error) {
+	broker, err := m.userBroker(brokerID)
+	if err != nil {
+		return nil, err
+	}
+	return broker.SetUserCredentials(ctx, username, action)
+}
+
+// userBroker returns the broker a user is assigned to, for the calls made outside of an authentication session.
+// It returns ErrNotSupported for the local broker.
+func (m *Manager) userBroker(brokerID string) (*Broker, error) {
+	broker, err := m.brokerFromID(brokerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker: %v", err)
+	}
+	if broker.ID == LocalBrokerName {
+		return nil, ErrNotSupported
+	}
+	return broker, nil
 }
 
 // EndSession signals the end of the session to the broker associated with the sessionID and then removes the
@@ -198,14 +722,72 @@ func (m *Manager) EndSession(sessionID string) error {
 		return err
 	}
 
-	m.transactionsToBrokerMu.Lock()
-	log.Debugf(context.Background(), "%s: End session %q",
-		sessionID, m.transactionsToBroker[sessionID].Name)
-	delete(m.transactionsToBroker, sessionID)
-	m.transactionsToBrokerMu.Unlock()
+	m.sessionsMu.Lock()
+	log.Debugf(context.Background(), "%s: End session %q", sessionID, b.Name)
+	s, exists := m.sessions[sessionID]
+	delete(m.sessions, sessionID)
+	m.sessionsMu.Unlock()
+
+	if exists {
+		m.releaseSession(s.broker.ID, s.username)
+	}
 	return nil
 }
 
+// reapSessions periodically ends the sessions that had no activity for longer than the session TTL, which can happen
+// if the PAM client crashed or the connection was dropped without ending the session.
+func (m *Manager) reapSessions(ctx context.Context) {
+	defer close(m.reaperDone)
+
+	interval := min(m.sessionTTL/2, time.Minute)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.reapExpiredSessions(ctx)
+		}
+	}
+}
+
+// reapExpiredSessions ends all the sessions that expired and drops their state.
+func (m *Manager) reapExpiredSessions(ctx context.Context) {
+	expired := make(map[string]*session)
+
+	m.sessionsMu.Lock()
+	for id, s := range m.sessions {
+		if s.activeCalls > 0 || time.Since(s.lastActivity) < m.sessionTTL {
+			continue
+		}
+		expired[id] = s
+		delete(m.sessions, id)
+	}
+	m.sessionsMu.Unlock()
+
+	for id, s := range expired {
+		m.releaseSession(s.broker.ID, s.username)
+		log.Infof(ctx, "%s: Ending session on broker %q after %v of inactivity", id, s.broker.Name, m.sessionTTL)
+		if err := s.broker.endSession(ctx, id); err != nil {
+			log.Warningf(ctx, "%s: Could not end expired session: %v", id, err)
+		}
+	}
+}
+
+// stop stops the session reaper and the broker pinger, if running.
+func (m *Manager) stop() {
+	m.stopReaper()
+	if m.reaperDone != nil {
+		<-m.reaperDone
+	}
+	m.stopPinger()
+	if m.pingerDone != nil {
+		<-m.pingerDone
+	}
+}
+
 // BrokerExists returns true if the brokerID is known by the manager. It can
 // happen that a broker which was stored in the database is not available anymore
 // because the user removed the configuration file.
@@ -214,8 +796,16 @@ func (m *Manager) BrokerExists(brokerID string) bool {
 	return exists
 }
 
-// brokerFromID returns the broker matching this brokerID.
+// BrokerIDAliases returns the previous broker IDs mapped to the current ID of their broker.
+func (m *Manager) BrokerIDAliases() map[string]string {
+	return maps.Clone(m.aliases)
+}
+
+// brokerFromID returns the broker matching this brokerID, or one of its aliases.
 func (m *Manager) brokerFromID(id string) (broker *Broker, err error) {
+	if current, isAlias := m.aliases[id]; isAlias {
+		id = current
+	}
 	broker, exists := m.brokers[id]
 	if !exists {
 		return nil, fmt.Errorf("no broker found matching %q", id)
@@ -8,10 +8,16 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/godbus/dbus/v5"
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/services/permissions"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 	"github.com/ubuntu/authd/log"
@@ -26,8 +32,11 @@ func TestNewManager(t *testing.T) {
 		brokerConfigDir   string
 		configuredBrokers []string
 		noBus             bool
+		// startBroker is the name of the broker mock to start, for the brokers of the configuration to be on the bus.
+		startBroker string
 
-		wantErr bool
+		wantSettings map[string]string
+		wantErr      bool
 	}{
 		"Creates_all_brokers_when_config_dir_has_only_valid_brokers":                 {brokerConfigDir: "valid_brokers"},
 		"Creates_without_autodiscovery_when_configuredBrokers_is_set":                {brokerConfigDir: "valid_brokers", configuredBrokers: []string{"valid_2.conf"}},
@@ -37,7 +46,12 @@ func TestNewManager(t *testing.T) {
 		"Creates_manager_even_if_broker_is_not_exported_on_dbus":                     {brokerConfigDir: "not_on_bus"},
 
 		"Ignores_broker_configuration_file_not_ending_with_.conf": {brokerConfigDir: "some_ignored_brokers"},
-		"Ignores_any_unknown_sections_and_fields":                 {brokerConfigDir: "extra_fields"},
+		"Forwards_broker_sections_and_ignores_unknown_fields": {
+			brokerConfigDir: "extra_fields",
+			startBroker:     "Broker",
+			wantSettings:    map[string]string{"broker_section.broker_field": "forwarded_field_in_broker_section"},
+		},
+		"Ignores_brokers_with_an_already_used_id": {brokerConfigDir: "brokers_with_ids"},
 
 		"Error_when_can't_connect_to_system_bus": {brokerConfigDir: "valid_brokers", noBus: true, wantErr: true},
 		"Error_when_broker_config_dir_is_a_file": {brokerConfigDir: "file_config_dir", wantErr: true},
@@ -48,13 +62,28 @@ func TestNewManager(t *testing.T) {
 				t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", "/dev/null")
 			}
 
-			got, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, tc.brokerConfigDir), tc.configuredBrokers)
+			if tc.startBroker != "" {
+				_, cleanup, err := testutils.StartBusBrokerMock(t.TempDir(), tc.startBroker)
+				require.NoError(t, err, "Setup: could not start bus broker mock")
+				t.Cleanup(cleanup)
+			}
+
+			got, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, tc.brokerConfigDir), tc.configuredBrokers, brokers.DefaultConfig)
 			if tc.wantErr {
 				require.Error(t, err, "NewManager should return an error, but did not")
 				return
 			}
 			require.NoError(t, err, "NewManager should not return an error, but did")
 
+			if tc.wantSettings != nil {
+				// The broker is configured before the first call.
+				_, err := got.AvailableBrokers()[1].UserPreCheck(context.Background(), "user-pre-check")
+				require.NoError(t, err, "UserPreCheck should not return an error, but did")
+				settings, _, err := testutils.BrokerMockSettings(tc.startBroker)
+				require.NoError(t, err, "Setup: could not get broker mock settings")
+				require.Equal(t, tc.wantSettings, settings, "Broker should receive the settings from its configuration file")
+			}
+
 			// Grab the list of broker names from the manager to use as golden file.
 			var brokers []string
 			for _, broker := range got.AvailableBrokers() {
@@ -66,25 +95,124 @@ func TestNewManager(t *testing.T) {
 	}
 }
 
+func TestCheckConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		brokerConfigDir string
+
+		wantErr bool
+	}{
+		"No_issues_when_config_dir_does_not_exist":        {brokerConfigDir: "does/not/exist"},
+		"No_issues_when_config_dir_has_no_brokers":        {brokerConfigDir: "no_brokers"},
+		"Reports_invalid_brokers":                         {brokerConfigDir: "invalid_brokers"},
+		"Reports_missing_and_invalid_brand_icons":         {brokerConfigDir: "with_brand_icons"},
+		"Ignores_configuration_files_not_ending_in_.conf": {brokerConfigDir: "some_ignored_brokers"},
+
+		"Error_when_broker_config_dir_is_a_file": {brokerConfigDir: "file_config_dir", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			issues, err := brokers.CheckConfig(context.Background(), filepath.Join(brokerConfFixtures, tc.brokerConfigDir))
+			if tc.wantErr {
+				require.Error(t, err, "CheckConfig should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "CheckConfig should not return an error, but did")
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Error())
+			}
+			golden.CheckOrUpdateYAML(t, got)
+		})
+	}
+}
+
+func TestBrandIcon(t *testing.T) {
+	t.Parallel()
+
+	m, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, "with_brand_icons"), nil, brokers.DefaultConfig)
+	require.NoError(t, err, "Setup: could not create manager")
+	t.Cleanup(m.Stop)
+
+	got := make(map[string]string)
+	for _, b := range m.AvailableBrokers() {
+		icon, err := m.BrandIcon(b.ID)
+		require.NoError(t, err, "BrandIcon should not return an error for an available broker, but did")
+		if icon == nil {
+			got[b.Name] = "no icon"
+			continue
+		}
+		got[b.Name] = fmt.Sprintf("%s %s", icon.ContentType, icon.Hash)
+	}
+	golden.CheckOrUpdateYAML(t, got)
+
+	_, err = m.BrandIcon("does-not-exist")
+	require.Error(t, err, "BrandIcon should return an error for an unknown broker, but did not")
+}
+
+func TestBrokerIDAliases(t *testing.T) {
+	t.Parallel()
+
+	m, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, "brokers_with_ids"), nil, brokers.DefaultConfig)
+	require.NoError(t, err, "Setup: could not create manager")
+	t.Cleanup(m.Stop)
+
+	want := map[string]string{
+		"2512354543":   "broker-a",
+		"old-broker-a": "broker-a",
+		"old-broker-b": "broker-b",
+	}
+	require.Equal(t, want, m.BrokerIDAliases(), "BrokerIDAliases should return the aliases of the loaded brokers")
+
+	// Aliases can be used instead of the broker ID.
+	err = m.SetDefaultBrokerForUser("old-broker-a", "user")
+	require.NoError(t, err, "SetDefaultBrokerForUser should accept a broker alias")
+	require.Equal(t, "broker-a", m.BrokerForUser("user").ID, "The user should be assigned the broker of the alias")
+
+	require.False(t, m.BrokerExists("old-broker-a"), "BrokerExists should only match broker IDs")
+}
+
 func TestSetDefaultBrokerForUser(t *testing.T) {
 	t.Parallel()
 
 	tests := map[string]struct {
 		exists bool
+		routes []brokers.Route
 
 		wantErr bool
 	}{
-		"Successfully_assigns_existent_broker_to_user": {exists: true},
-
-		"Error_when_broker_does_not_exist": {wantErr: true},
+		"Successfully_assigns_existent_broker_to_user":                     {exists: true},
+		"Successfully_assigns_broker_to_user_matching_non_mandatory_route": {exists: true, routes: []brokers.Route{{Username: "user"}}},
+
+		"Error_when_broker_does_not_exist":                {wantErr: true},
+		"Error_when_broker_contradicts_a_mandatory_route": {exists: true, routes: []brokers.Route{{Username: "user", Mandatory: true}}, wantErr: true},
+		"Error_when_user_is_routed_to_an_unavailable_broker": {
+			exists: true, routes: []brokers.Route{{Username: "user", Broker: "not-available", Mandatory: true}}, wantErr: true,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			m, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, "mixed_brokers"), nil)
+			brokersConfPath := filepath.Join(brokerConfFixtures, "mixed_brokers")
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, nil, brokers.DefaultConfig)
 			require.NoError(t, err, "Setup: could not create manager")
 
+			if tc.routes != nil {
+				// Route the user to another broker than the one we are going to assign.
+				for i := range tc.routes {
+					if tc.routes[i].Broker == "" {
+						tc.routes[i].Broker = m.AvailableBrokers()[1].ID
+					}
+				}
+				m, err = brokers.NewManager(context.Background(), brokersConfPath, nil, brokers.Config{Routes: tc.routes})
+				require.NoError(t, err, "Setup: could not create manager with routes")
+			}
+
 			want := m.AvailableBrokers()[0]
 			if !tc.exists {
 				want.ID = "does not exist"
@@ -106,7 +234,7 @@ func TestSetDefaultBrokerForUser(t *testing.T) {
 func TestBrokerForUser(t *testing.T) {
 	t.Parallel()
 
-	m, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, "valid_brokers"), nil)
+	m, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, "valid_brokers"), nil, brokers.DefaultConfig)
 	require.NoError(t, err, "Setup: could not create manager")
 
 	err = m.SetDefaultBrokerForUser(brokers.LocalBrokerName, "user")
@@ -141,7 +269,7 @@ func TestBrokerFromSessionID(t *testing.T) {
 
 			brokersConfPath := t.TempDir()
 			b := newBrokerForTests(t, brokersConfPath, "")
-			m, err := brokers.NewManager(context.Background(), brokersConfPath, nil)
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, nil, brokers.DefaultConfig)
 			require.NoError(t, err, "Setup: could not create manager")
 
 			if tc.sessionID == "success" {
@@ -168,6 +296,78 @@ func TestBrokerFromSessionID(t *testing.T) {
 	}
 }
 
+func TestSessionBroker(t *testing.T) {
+	t.Parallel()
+
+	owner := permissions.Peer{UID: 0, PID: 4242, StartTime: 4343}
+
+	tests := map[string]struct {
+		sessionID string
+		// peer is the owner of the session if nil.
+		peer *permissions.Peer
+
+		wantLocalBroker bool
+		wantErr         bool
+		wantNotOwner    bool
+	}{
+		"Successfully_returns_broker_to_the_owner_of_the_session": {},
+		"Returns_local_broker_if_sessionID_is_empty":              {sessionID: "-", wantLocalBroker: true},
+
+		"Error_if_session_does_not_exist":          {sessionID: "does not exist", wantErr: true},
+		"Error_if_peer_is_another_process":         {peer: &permissions.Peer{UID: 0, PID: 4444, StartTime: 4343}, wantNotOwner: true},
+		"Error_if_peer_reuses_the_pid_of_owner":    {peer: &permissions.Peer{UID: 0, PID: 4242, StartTime: 4444}, wantNotOwner: true},
+		"Error_if_peer_is_run_by_another_user":     {peer: &permissions.Peer{UID: 1000, PID: 4242, StartTime: 4343}, wantNotOwner: true},
+		"Error_if_peer_has_no_process_information": {peer: &permissions.Peer{}, wantNotOwner: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersConfPath := t.TempDir()
+			b := newBrokerForTests(t, brokersConfPath, "")
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, nil, brokers.DefaultConfig)
+			require.NoError(t, err, "Setup: could not create manager")
+
+			// We need to use the ID generated by the mananger.
+			for _, broker := range m.AvailableBrokers() {
+				if broker.Name == b.Name {
+					b.ID = broker.ID
+					break
+				}
+			}
+			_, sessionID, _, err := m.NewSession(b.ID, "user1", "some_lang", "auth", brokers.PAMContext{}, owner)
+			require.NoError(t, err, "Setup: could not start session")
+
+			switch tc.sessionID {
+			case "":
+				tc.sessionID = sessionID
+			case "-":
+				tc.sessionID = ""
+			}
+			peer := owner
+			if tc.peer != nil {
+				peer = *tc.peer
+			}
+
+			got, err := m.SessionBroker(tc.sessionID, peer)
+			if tc.wantNotOwner {
+				require.ErrorIs(t, err, brokers.ErrNotSessionOwner, "SessionBroker should reject the peer")
+				return
+			}
+			if tc.wantErr {
+				require.Error(t, err, "SessionBroker should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SessionBroker should not return an error, but did")
+			if tc.wantLocalBroker {
+				require.Equal(t, brokers.LocalBrokerName, got.ID, "SessionBroker should return the local broker")
+				return
+			}
+			require.Equal(t, b.ID, got.ID, "SessionBroker should return the broker of the session")
+		})
+	}
+}
+
 func TestNewSession(t *testing.T) {
 	t.Parallel()
 
@@ -176,19 +376,50 @@ func TestNewSession(t *testing.T) {
 		username    string
 		sessionMode string
 
+		pamContext        brokers.PAMContext
 		configuredBrokers []string
 		unavailableBroker bool
+		fallbackBroker    bool
+		accessConfig      string
+		mandatoryRoute    bool
 
 		wantErr bool
 	}{
 		"Successfully_start_a_new_auth_session":                    {username: "success"},
 		"Successfully_start_a_new_passwd_session":                  {username: "success", sessionMode: auth.SessionModeChangePassword},
 		"Successfully_start_a_new_session_with_the_correct_broker": {username: "success", configuredBrokers: []string{t.Name() + "_Broker1.conf", t.Name() + "_Broker2.conf"}},
+		"Successfully_forward_the_PAM_context_to_the_broker": {
+			username:   "ns_context",
+			pamContext: brokers.PAMContext{Service: "sshd", TTY: "ssh", RHost: "192.0.2.1", RUser: "remote"},
+		},
+		"Successfully_start_a_new_session_with_a_broker_not_supporting_PAM_context": {username: "ns_no_context_support"},
+		"Successfully_start_a_new_session_from_an_allowed_service": {
+			username: "success", pamContext: brokers.PAMContext{Service: "sshd"}, accessConfig: "allowed_services = sshd",
+		},
+		"Successfully_start_a_new_session_on_the_fallback_broker_when_broker_is_not_available_on_dbus": {
+			username: "success", unavailableBroker: true, fallbackBroker: true,
+		},
 
 		"Error_when_broker_does_not_exist":           {brokerID: "does_not_exist", wantErr: true},
 		"Error_when_broker_does_not_provide_an_ID":   {username: "ns_no_id", wantErr: true},
 		"Error_when_starting_a_new_session":          {username: "ns_error", wantErr: true},
 		"Error_when_broker_is_not_available_on_dbus": {unavailableBroker: true, wantErr: true},
+		"Error_when_user_is_not_allowed_by_broker":   {username: "success", accessConfig: "allowed_usernames = nobody", wantErr: true},
+		"Error_when_fallback_broker_does_not_allow_the_user": {
+			username: "success", unavailableBroker: true, fallbackBroker: true, accessConfig: "allowed_usernames = nobody", wantErr: true,
+		},
+		"Error_when_service_is_not_allowed_by_broker": {
+			username: "success", pamContext: brokers.PAMContext{Service: "login"}, accessConfig: "allowed_services = sshd", wantErr: true,
+		},
+		"Error_when_fallback_broker_is_forbidden_by_a_mandatory_route": {
+			username: "success", unavailableBroker: true, fallbackBroker: true, mandatoryRoute: true, wantErr: true,
+		},
+		"Error_when_service_is_missing_and_broker_restricts_services": {
+			username: "success", accessConfig: "allowed_services = sshd", wantErr: true,
+		},
+		"Error_when_service_is_missing_and_fallback_broker_restricts_services": {
+			username: "success", unavailableBroker: true, fallbackBroker: true, accessConfig: "allowed_services = sshd", wantErr: true,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -206,55 +437,118 @@ func TestNewSession(t *testing.T) {
 				}
 			}
 
+			if tc.accessConfig != "" {
+				appendBrokerConfig(t, filepath.Join(brokersConfPath, tc.configuredBrokers[0]), "\n[access]\n"+tc.accessConfig+"\n")
+			}
+
+			selectedBrokerName := wantBroker.Name
 			if tc.unavailableBroker {
 				// We need to manually configure the broker without exporting it on the bus.
 				content, err := os.ReadFile(filepath.Join(brokerConfFixtures, "not_on_bus", "not_on_bus.conf"))
 				require.NoError(t, err, "Setup: could not read broker configuration file")
+				// Unknown fallback brokers are skipped.
+				fallbacks := "does_not_exist"
+				if tc.fallbackBroker {
+					fallbacks += ", " + wantBroker.ID
+				} else {
+					wantBroker = brokers.Broker{Name: "OfflineBroker"}
+				}
+				content = append(content, []byte("fallback_brokers = "+fallbacks+"\n")...)
 				err = os.WriteFile(filepath.Join(brokersConfPath, "not_on_bus.conf"), content, 0600)
 				require.NoError(t, err, "Setup: could not write broker configuration file")
-				wantBroker = brokers.Broker{Name: "OfflineBroker"}
+				selectedBrokerName = "OfflineBroker"
 				tc.configuredBrokers = nil
 			}
 
-			m, err := brokers.NewManager(context.Background(), brokersConfPath, tc.configuredBrokers)
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, tc.configuredBrokers, brokers.DefaultConfig)
 			require.NoError(t, err, "Setup: could not create manager")
 
 			if tc.brokerID == "" {
 				// We need to use the ID generated by the mananger.
 				var brokerFound bool
 				for _, broker := range m.AvailableBrokers() {
-					if broker.Name != wantBroker.Name {
+					if broker.Name == wantBroker.Name {
+						wantBroker.ID = broker.ID
+					}
+					if broker.Name != selectedBrokerName {
 						continue
 					}
-					wantBroker.ID = broker.ID
+					tc.brokerID = broker.ID
 					brokerFound = true
 				}
 				require.True(t, brokerFound, "Setup: could not find the test broker in the manager")
-				tc.brokerID = wantBroker.ID
+			}
+
+			if tc.mandatoryRoute {
+				// Route the user to the selected broker only, so that it can't use its fallback.
+				config := brokers.DefaultConfig
+				config.Routes = []brokers.Route{{Username: tc.username, Broker: tc.brokerID, Mandatory: true}}
+				m, err = brokers.NewManager(context.Background(), brokersConfPath, tc.configuredBrokers, config)
+				require.NoError(t, err, "Setup: could not create manager with routes")
 			}
 
 			if tc.sessionMode == "" {
 				tc.sessionMode = "auth"
 			}
 
-			gotID, gotEKey, err := m.NewSession(tc.brokerID, tc.username, "some_lang", tc.sessionMode)
+			gotBroker, gotID, gotEKey, err := m.NewSession(tc.brokerID, tc.username, "some_lang", tc.sessionMode, tc.pamContext, permissions.Peer{})
 			if tc.wantErr {
 				require.Error(t, err, "NewSession should return an error, but did not")
 				return
 			}
 			require.NoError(t, err, "NewSession should not return an error, but did")
+			require.Equal(t, wantBroker.ID, gotBroker.ID, "NewSession should return the broker the session was started on")
 
-			// Replaces the autogenerated part of the ID with a placeholder before saving the file.
-			gotStr := fmt.Sprintf("ID: %s\nEncryption Key: %s\n", strings.ReplaceAll(gotID, wantBroker.ID, "BROKER_ID"), gotEKey)
+			// Replaces the autogenerated parts of the ID with a placeholder before saving the file.
+			gotBrokerSessionID := brokers.BrokerSessionID(wantBroker.ID, gotID)
+			require.NotEqual(t, wantBroker.ID+"-"+gotBrokerSessionID, gotID, "NewSession should add a random token to the session ID")
+			gotStr := fmt.Sprintf("ID: BROKER_ID-%s\nEncryption Key: %s\n", gotBrokerSessionID, gotEKey)
 			golden.CheckOrUpdate(t, gotStr)
 
-			gotBroker, err := m.BrokerFromSessionID(gotID)
+			gotBroker, err = m.BrokerFromSessionID(gotID)
 			require.NoError(t, err, "NewSession should have assigned a broker for the session, but did not")
 			require.Equal(t, wantBroker.ID, gotBroker.ID, "BrokerFromSessionID should have assigned the expected broker for the session, but did not")
 		})
 	}
 }
 
+func TestAllowedBrokers(t *testing.T) {
+	t.Parallel()
+
+	brokersConfPath := t.TempDir()
+	sshBroker := newBrokerForTestsWithConfig(t, brokersConfPath, t.Name()+"_SSHBroker.conf",
+		"\n[access]\nallowed_services = sshd\n")
+	companyBroker := newBrokerForTestsWithConfig(t, brokersConfPath, t.Name()+"_CompanyBroker.conf",
+		"\n[access]\nallowed_usernames = .*@example\\.com\n")
+
+	m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{sshBroker.Name + ".conf", companyBroker.Name + ".conf"}, brokers.DefaultConfig)
+	require.NoError(t, err, "Setup: could not create manager")
+
+	tests := map[string]struct {
+		username string
+		service  string
+
+		wantBrokers []string
+	}{
+		"Service_restricted_brokers_not_allowed_without_service": {wantBrokers: []string{brokers.LocalBrokerName, companyBroker.Name}},
+		"Brokers_allowed_for_service":                            {service: "sshd", wantBrokers: []string{brokers.LocalBrokerName, sshBroker.Name, companyBroker.Name}},
+		"Brokers_allowed_for_user":                               {username: "user@example.com", service: "sshd", wantBrokers: []string{brokers.LocalBrokerName, sshBroker.Name, companyBroker.Name}},
+		"Brokers_allowed_for_user_and_service":                   {username: "user@example.com", service: "login", wantBrokers: []string{brokers.LocalBrokerName, companyBroker.Name}},
+		"Only_unrestricted_brokers":                              {username: "user@other.com", service: "login", wantBrokers: []string{brokers.LocalBrokerName}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, b := range m.AllowedBrokers(tc.username, tc.service) {
+				got = append(got, b.Name)
+			}
+			require.Equal(t, tc.wantBrokers, got, "AllowedBrokers should return the expected brokers")
+		})
+	}
+}
+
 func TestEndSession(t *testing.T) {
 	t.Parallel()
 
@@ -288,7 +582,7 @@ func TestEndSession(t *testing.T) {
 				}
 			}
 
-			m, err := brokers.NewManager(context.Background(), brokersConfPath, tc.configuredBrokers)
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, tc.configuredBrokers, brokers.DefaultConfig)
 			require.NoError(t, err, "Setup: could not create manager")
 
 			if tc.brokerID != "does not exist" {
@@ -314,7 +608,7 @@ func TestStartAndEndSession(t *testing.T) {
 	b1 := newBrokerForTests(t, brokersConfPath, t.Name()+"_Broker1.conf")
 	b2 := newBrokerForTests(t, brokersConfPath, t.Name()+"_Broker2.conf")
 
-	m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b1.Name + ".conf", b2.Name + ".conf"})
+	m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b1.Name + ".conf", b2.Name + ".conf"}, brokers.DefaultConfig)
 	require.NoError(t, err, "Setup: could not create manager")
 
 	// Fetches the broker IDs
@@ -333,13 +627,13 @@ func TestStartAndEndSession(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		id, key, err := m.NewSession(b1.ID, "user1", "some_lang", "auth")
+		_, id, key, err := m.NewSession(b1.ID, "user1", "some_lang", "auth", brokers.PAMContext{}, permissions.Peer{})
 		firstID, firstKey, firstErr = &id, &key, &err
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		id, key, err := m.NewSession(b2.ID, "user2", "some_lang", "auth")
+		_, id, key, err := m.NewSession(b2.ID, "user2", "some_lang", "auth", brokers.PAMContext{}, permissions.Peer{})
 		secondID, secondKey, secondErr = &id, &key, &err
 	}()
 	wg.Wait()
@@ -347,12 +641,12 @@ func TestStartAndEndSession(t *testing.T) {
 	require.NoError(t, *firstErr, "First NewSession should not return an error, but did")
 	require.NoError(t, *secondErr, "Second NewSession should not return an error, but did")
 
-	require.Equal(t, b1.ID+"-"+testutils.GenerateSessionID("user1"),
-		*firstID, "First NewSession should return the expected session ID, but did not")
+	require.Equal(t, testutils.GenerateSessionID("user1"),
+		brokers.BrokerSessionID(b1.ID, *firstID), "First NewSession should return the expected session ID, but did not")
 	require.Equal(t, testutils.GenerateEncryptionKey(b1.Name),
 		*firstKey, "First NewSession should return the expected encryption key, but did not")
-	require.Equal(t, b2.ID+"-"+testutils.GenerateSessionID("user2"),
-		*secondID, "Second NewSession should return the expected session ID, but did not")
+	require.Equal(t, testutils.GenerateSessionID("user2"),
+		brokers.BrokerSessionID(b2.ID, *secondID), "Second NewSession should return the expected session ID, but did not")
 	require.Equal(t, testutils.GenerateEncryptionKey(b2.Name),
 		*secondKey, "Second NewSession should return the expected encryption key, but did not")
 
@@ -386,6 +680,579 @@ func TestStartAndEndSession(t *testing.T) {
 	require.Error(t, err, "Second EndSession should have removed the broker for the session, but did not")
 }
 
+func TestNewDecoySession(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		brokerID         string
+		brokerUsername   string
+		uniformResponses bool
+
+		wantErr bool
+	}{
+		"Successfully_start_decoy_session":                    {uniformResponses: true},
+		"Successfully_start_decoy_session_with_X25519_broker": {brokerUsername: "ns_x25519", uniformResponses: true},
+
+		"Error_when_uniform_responses_are_disabled": {wantErr: true},
+		"Error_when_broker_is_local":                {brokerID: brokers.LocalBrokerName, uniformResponses: true, wantErr: true},
+		"Error_when_broker_does_not_exist":          {brokerID: "does not exist", uniformResponses: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersConfPath := t.TempDir()
+			b := newBrokerForTests(t, brokersConfPath, "")
+			config := brokers.Config{UniformResponses: tc.uniformResponses}
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b.Name + ".conf"}, config)
+			require.NoError(t, err, "Setup: could not create manager")
+			require.Equal(t, tc.uniformResponses, m.UniformResponses(), "UniformResponses should match the configuration")
+
+			broker := m.AvailableBrokers()[1]
+			broker.SetLatencies(0)
+			if tc.brokerID == "" {
+				tc.brokerID = broker.ID
+			}
+			if tc.brokerUsername == "" {
+				tc.brokerUsername = "success"
+			}
+
+			_, _, brokerKey, err := m.NewSession(broker.ID, tc.brokerUsername, "C", auth.SessionModeLogin, brokers.PAMContext{}, permissions.Peer{})
+			require.NoError(t, err, "Setup: could not start session")
+
+			_, sessionID, encryptionKey, err := m.NewDecoySession(tc.brokerID, "unknown-user", "C", auth.SessionModeLogin, brokers.PAMContext{}, permissions.Peer{})
+			if tc.wantErr {
+				require.Error(t, err, "NewDecoySession should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "NewDecoySession should not return an error, but did")
+			require.True(t, strings.HasPrefix(sessionID, broker.ID+"-"), "Decoy session ID should look like the ones of the broker")
+
+			decoy, err := m.BrokerFromSessionID(sessionID)
+			require.NoError(t, err, "Decoy session should have a broker")
+			require.Equal(t, broker.ID, decoy.ID, "Decoy broker should have the ID of the broker")
+			require.Equal(t, broker.Name, decoy.Name, "Decoy broker should have the name of the broker")
+
+			// Each decoy session has its own key, of the algorithm of the broker.
+			_, _, otherKey, err := m.NewDecoySession(tc.brokerID, "unknown-user", "C", auth.SessionModeLogin, brokers.PAMContext{}, permissions.Peer{})
+			require.NoError(t, err, "NewDecoySession should not return an error, but did")
+			for _, key := range []string{encryptionKey, otherKey} {
+				require.Equal(t, encryption.AlgorithmOf(brokerKey), encryption.AlgorithmOf(key), "Decoy session key should use the algorithm of the broker")
+				require.NotEqual(t, brokerKey, key, "Decoy session should not reuse the encryption key of the broker")
+			}
+			require.NotEqual(t, encryptionKey, otherKey, "Decoy sessions should not share their encryption key")
+		})
+	}
+}
+
+func TestWaitSessionStart(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		brokerID         string
+		latency          time.Duration
+		requestAge       time.Duration
+		uniformResponses bool
+
+		wantMinDuration time.Duration
+		wantMaxDuration time.Duration
+	}{
+		"Wait_as_long_as_looking_for_user_and_starting_session": {latency: 100 * time.Millisecond, uniformResponses: true, wantMinDuration: 150 * time.Millisecond},
+		"Wait_counts_from_the_request_start":                    {latency: time.Hour, requestAge: 3 * time.Hour, uniformResponses: true, wantMaxDuration: 10 * time.Second},
+
+		"No_wait_when_uniform_responses_are_disabled": {latency: time.Hour, wantMaxDuration: 10 * time.Second},
+		"No_wait_when_broker_is_local":                {brokerID: brokers.LocalBrokerName, latency: time.Hour, uniformResponses: true, wantMaxDuration: 10 * time.Second},
+		"No_wait_when_broker_does_not_exist":          {brokerID: "does not exist", latency: time.Hour, uniformResponses: true, wantMaxDuration: 10 * time.Second},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersConfPath := t.TempDir()
+			b := newBrokerForTests(t, brokersConfPath, "")
+			config := brokers.Config{UniformResponses: tc.uniformResponses}
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b.Name + ".conf"}, config)
+			require.NoError(t, err, "Setup: could not create manager")
+			broker := m.AvailableBrokers()[1]
+			broker.SetLatencies(tc.latency)
+			if tc.brokerID == "" {
+				tc.brokerID = broker.ID
+			}
+
+			// The context only bounds the test if the wait is wrongly shaped.
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			start := time.Now()
+			m.WaitSessionStart(ctx, tc.brokerID, start.Add(-tc.requestAge))
+			require.GreaterOrEqual(t, time.Since(start), tc.wantMinDuration, "WaitSessionStart should wait as long as the broker")
+			if tc.wantMaxDuration > 0 {
+				require.Less(t, time.Since(start), tc.wantMaxDuration, "WaitSessionStart should not wait past the delay since the request start")
+			}
+		})
+	}
+}
+
+func TestDecoySession(t *testing.T) {
+	t.Parallel()
+
+	brokersConfPath := t.TempDir()
+	b := newBrokerForTests(t, brokersConfPath, "")
+	m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b.Name + ".conf"}, brokers.Config{UniformResponses: true})
+	require.NoError(t, err, "Setup: could not create manager")
+	broker := m.AvailableBrokers()[1]
+	broker.SetLatencies(0)
+
+	_, sessionID, _, err := m.NewDecoySession(broker.ID, "unknown-user", "C", auth.SessionModeLogin, brokers.PAMContext{}, permissions.Peer{})
+	require.NoError(t, err, "Setup: could not start decoy session")
+	decoy, err := m.BrokerFromSessionID(sessionID)
+	require.NoError(t, err, "Setup: could not get decoy broker")
+
+	modes, err := decoy.GetAuthenticationModes(context.Background(), sessionID, []map[string]string{supportedLayouts["form"]})
+	require.NoError(t, err, "GetAuthenticationModes should not return an error, but did")
+	require.Len(t, modes, 1, "Decoy session should offer a single authentication mode")
+
+	layout, err := decoy.SelectAuthenticationMode(context.Background(), sessionID, modes[0][layouts.ID])
+	require.NoError(t, err, "SelectAuthenticationMode should not return an error, but did")
+	require.Equal(t, layouts.Form, layout[layouts.Type], "Decoy session should ask for a password")
+	require.Equal(t, entries.CharsPassword, layout[layouts.Entry], "Decoy session should ask for a password")
+
+	for _, want := range []string{auth.Retry, auth.Retry, auth.Denied} {
+		access, data, err := decoy.IsAuthenticated(context.Background(), sessionID, `{"challenge": "some-password"}`)
+		require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+		require.Equal(t, want, access, "Decoy session should deny access after the last attempt")
+		require.Contains(t, data, "message", "Decoy session should explain why it failed")
+	}
+
+	// The decoy sessions mimic the kinds of forms of the broker, but never their content.
+	for _, id := range []string{"sam_success_form", "sam_success_webauthn", "sam_success_choice"} {
+		brokers.GenerateLayoutValidators(broker, prefixID(t, id), []map[string]string{supportedLayouts["form"], supportedLayouts["webauthn"], supportedLayouts["choice"]})
+		_, err = broker.SelectAuthenticationMode(context.Background(), prefixID(t, id), "mode1")
+		require.NoError(t, err, "Setup: could not select authentication mode")
+	}
+	_, _, err = broker.IsAuthenticated(context.Background(), prefixID(t, "ia_retry"), `{"challenge": "some-password"}`)
+	require.NoError(t, err, "Setup: could not authenticate")
+
+	_, sessionID, _, err = m.NewDecoySession(broker.ID, "unknown-user", "C", auth.SessionModeLogin, brokers.PAMContext{}, permissions.Peer{})
+	require.NoError(t, err, "Setup: could not start decoy session")
+	decoy, err = m.BrokerFromSessionID(sessionID)
+	require.NoError(t, err, "Setup: could not get decoy broker")
+	modes, err = decoy.GetAuthenticationModes(context.Background(), sessionID, []map[string]string{supportedLayouts["required-entry"]})
+	require.NoError(t, err, "GetAuthenticationModes should not return an error, but did")
+	require.Equal(t, []map[string]string{{layouts.ID: "password", layouts.Label: "Password authentication"}}, modes,
+		"Decoy session should only offer the forms supported by the client")
+	modes, err = decoy.GetAuthenticationModes(context.Background(), sessionID, []map[string]string{supportedLayouts["form"], supportedLayouts["webauthn"], supportedLayouts["choice"]})
+	require.NoError(t, err, "GetAuthenticationModes should not return an error, but did")
+	require.Equal(t, []map[string]string{{layouts.ID: "code", layouts.Label: "Code authentication"}}, modes,
+		"Decoy session should offer a generic form for each kind of form of the broker")
+
+	layout, err = decoy.SelectAuthenticationMode(context.Background(), sessionID, "code")
+	require.NoError(t, err, "SelectAuthenticationMode should not return an error, but did")
+	require.Equal(t, map[string]string{layouts.Type: layouts.Form, layouts.Label: "Code", layouts.Entry: entries.Chars}, layout,
+		"Decoy session should return a generic form, without the content of the broker one")
+	_, data, err := decoy.IsAuthenticated(context.Background(), sessionID, `{"challenge": "some-password"}`)
+	require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+	require.JSONEq(t, `{"message": "Authentication failure"}`, data, "Decoy session should fail with a generic message")
+
+	// Cancelling the context cancels the ongoing authentication.
+	broker.SetLatencies(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	access, _, err := decoy.IsAuthenticated(ctx, sessionID, `{"challenge": "some-password"}`)
+	require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+	require.Equal(t, auth.Cancelled, access, "Decoy session should cancel the authentication")
+
+	require.NoError(t, m.EndSession(sessionID), "EndSession should not return an error, but did")
+	_, _, err = decoy.IsAuthenticated(context.Background(), sessionID, `{"challenge": "some-password"}`)
+	require.Error(t, err, "IsAuthenticated should return an error once the decoy session ended")
+}
+
+func TestManagerUserPreCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username         string
+		uniformResponses bool
+
+		wantMinDuration time.Duration
+		wantErr         bool
+	}{
+		"Successfully_pre_check_user":                           {username: "user-pre-check"},
+		"Successfully_pre_check_user_with_uniform_responses":    {username: "user-pre-check", uniformResponses: true},
+		"Error_when_user_is_unknown":                            {username: "unknown-user", wantErr: true},
+		"Error_when_user_is_unknown_is_delayed_like_known_user": {username: "unknown-user", uniformResponses: true, wantMinDuration: 150 * time.Millisecond, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersConfPath := t.TempDir()
+			b := newBrokerForTests(t, brokersConfPath, "")
+			config := brokers.Config{UniformResponses: tc.uniformResponses}
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b.Name + ".conf"}, config)
+			require.NoError(t, err, "Setup: could not create manager")
+			m.AvailableBrokers()[1].SetLatencies(200 * time.Millisecond)
+
+			start := time.Now()
+			userinfo, err := m.UserPreCheck(context.Background(), tc.username)
+			require.GreaterOrEqual(t, time.Since(start), tc.wantMinDuration, "UserPreCheck should take as long as for known users")
+			require.Equal(t, tc.wantErr, !m.KnowsUser(context.Background(), tc.username), "KnowsUser should match UserPreCheck")
+			if tc.wantErr {
+				require.Error(t, err, "UserPreCheck should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "UserPreCheck should not return an error, but did")
+			require.Contains(t, userinfo, tc.username, "UserPreCheck should return the user information")
+		})
+	}
+}
+
+func TestSessionLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		maxPerBroker int
+		maxPerUser   int
+
+		secondBroker   int
+		secondUsername string
+
+		wantErr            bool
+		wantLimitsReached  []string
+		wantLimitsReleased []string
+	}{
+		"Zero_limits_disable_limiting": {secondBroker: 0, secondUsername: "user2"},
+		"Sessions_up_to_the_broker_limit": {
+			maxPerBroker: 2, maxPerUser: 1, secondBroker: 0, secondUsername: "user2",
+			wantLimitsReached: []string{"Broker1"}, wantLimitsReleased: []string{"Broker1"},
+		},
+		"Sessions_up_to_the_user_limit": {
+			maxPerBroker: 1, maxPerUser: 2, secondBroker: 1, secondUsername: "user1",
+			wantLimitsReached: []string{"Broker1", "Broker2"}, wantLimitsReleased: []string{"Broker1"},
+		},
+		"Sessions_of_other_users_on_another_broker": {
+			maxPerBroker: 1, maxPerUser: 1, secondBroker: 1, secondUsername: "user2",
+			wantLimitsReached: []string{"Broker1", "Broker2"}, wantLimitsReleased: []string{"Broker1"},
+		},
+
+		"Error_when_broker_has_too_many_sessions": {
+			maxPerBroker: 1, secondBroker: 0, secondUsername: "user2", wantErr: true,
+			wantLimitsReached: []string{"Broker1"}, wantLimitsReleased: []string{"Broker1"},
+		},
+		"Error_when_user_has_too_many_sessions": {maxPerUser: 1, secondBroker: 1, secondUsername: "user1", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersConfPath := t.TempDir()
+			prefix := strings.ReplaceAll(t.Name(), "/", "_")
+			b1 := newBrokerForTests(t, brokersConfPath, prefix+"_Broker1.conf")
+			b2 := newBrokerForTests(t, brokersConfPath, prefix+"_Broker2.conf")
+
+			config := brokers.Config{MaxSessionsPerBroker: tc.maxPerBroker, MaxSessionsPerUser: tc.maxPerUser}
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b1.Name + ".conf", b2.Name + ".conf"}, config)
+			require.NoError(t, err, "Setup: could not create manager")
+
+			gotPerBroker, gotPerUser := m.SessionLimits()
+			require.Equal(t, tc.maxPerBroker, gotPerBroker, "SessionLimits should return the configured limit per broker")
+			require.Equal(t, tc.maxPerUser, gotPerUser, "SessionLimits should return the configured limit per user")
+
+			brokerIDs := make(map[string]string)
+			for _, broker := range m.AvailableBrokers() {
+				brokerIDs[broker.ID] = strings.TrimPrefix(broker.Name, prefix+"_")
+			}
+			var limitsReached, limitsReleased []string
+			m.SetSessionLimitsHandler(func(brokerID string, limitReached bool) {
+				if !limitReached {
+					return
+				}
+				limitsReached = append(limitsReached, brokerIDs[brokerID])
+			})
+			require.Empty(t, limitsReached, "No broker should have reached its limit before any session is started")
+
+			ids := []string{m.AvailableBrokers()[1].ID, m.AvailableBrokers()[2].ID}
+			_, first, _, err := m.NewSession(ids[0], "user1", "some_lang", "auth", brokers.PAMContext{}, permissions.Peer{})
+			require.NoError(t, err, "First NewSession should not return an error, but did")
+
+			_, second, _, err := m.NewSession(ids[tc.secondBroker], tc.secondUsername, "some_lang", "auth", brokers.PAMContext{}, permissions.Peer{})
+			if tc.wantErr {
+				require.ErrorIs(t, err, brokers.ErrTooManySessions, "Second NewSession should return ErrTooManySessions")
+			} else {
+				require.NoError(t, err, "Second NewSession should not return an error, but did")
+			}
+
+			m.SetSessionLimitsHandler(func(brokerID string, limitReached bool) {
+				if limitReached {
+					return
+				}
+				limitsReleased = append(limitsReleased, brokerIDs[brokerID])
+			})
+			limitsReleased = nil
+
+			require.NoError(t, m.EndSession(first), "EndSession should not return an error, but did")
+			require.Equal(t, tc.wantLimitsReached, limitsReached, "Brokers reaching their limit should be notified")
+			require.Equal(t, tc.wantLimitsReleased, limitsReleased, "Brokers going back below their limit should be notified")
+
+			if tc.wantErr {
+				// The limit is not reached anymore after the first session ended.
+				_, second, _, err = m.NewSession(ids[tc.secondBroker], tc.secondUsername, "some_lang", "auth", brokers.PAMContext{}, permissions.Peer{})
+				require.NoError(t, err, "NewSession should not return an error once a session ended, but did")
+			}
+			require.NoError(t, m.EndSession(second), "EndSession should not return an error, but did")
+		})
+	}
+}
+
+func TestBrokerHealth(t *testing.T) {
+	t.Parallel()
+
+	brokersConfPath := t.TempDir()
+	b := newBrokerForTests(t, brokersConfPath, t.Name()+"_Broker.conf")
+	// This broker is not exported on the bus, so it can't be reached.
+	content, err := os.ReadFile(filepath.Join(brokerConfFixtures, "not_on_bus", "not_on_bus.conf"))
+	require.NoError(t, err, "Setup: could not read broker configuration file")
+	err = os.WriteFile(filepath.Join(brokersConfPath, "not_on_bus.conf"), content, 0600)
+	require.NoError(t, err, "Setup: could not write broker configuration file")
+
+	m, err := brokers.NewManager(context.Background(), brokersConfPath, nil, brokers.Config{})
+	require.NoError(t, err, "Setup: could not create manager")
+
+	names := make(map[string]string)
+	for _, broker := range m.AvailableBrokers() {
+		names[broker.ID] = broker.Name
+	}
+	got := make(map[string]bool)
+	m.SetBrokerHealthHandler(func(brokerID string, healthy bool) {
+		got[names[brokerID]] = healthy
+	})
+	require.Equal(t, map[string]bool{brokers.LocalBrokerName: true, b.Name: true, "OfflineBroker": true}, got,
+		"All brokers should be considered healthy before being pinged")
+
+	m.CheckBrokersHealth()
+	require.Equal(t, map[string]bool{brokers.LocalBrokerName: true, b.Name: true, "OfflineBroker": false}, got,
+		"Only the broker that can't be reached should be unhealthy")
+
+	clear(got)
+	m.CheckBrokersHealth()
+	require.Empty(t, got, "The handler should only be called when the health of a broker changes")
+}
+
+func TestNewManagerWithInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config brokers.Config
+	}{
+		"Error_when_session_TTL_is_negative":             {config: brokers.Config{SessionTTL: -time.Second}},
+		"Error_when_ping_interval_is_negative":           {config: brokers.Config{PingInterval: -time.Second}},
+		"Error_when_max_sessions_per_broker_is_negative": {config: brokers.Config{MaxSessionsPerBroker: -1}},
+		"Error_when_max_sessions_per_user_is_negative":   {config: brokers.Config{MaxSessionsPerUser: -1}},
+		"Error_when_route_has_no_username_nor_domain":    {config: brokers.Config{Routes: []brokers.Route{{Broker: "some-broker"}}}},
+		"Error_when_route_has_both_username_and_domain":  {config: brokers.Config{Routes: []brokers.Route{{Username: "user", Domain: "example.com", Broker: "some-broker"}}}},
+		"Error_when_route_has_no_broker":                 {config: brokers.Config{Routes: []brokers.Route{{Domain: "example.com"}}}},
+		"Error_when_route_has_an_invalid_pattern":        {config: brokers.Config{Routes: []brokers.Route{{Username: "[invalid", Broker: "some-broker"}}}},
+		"Error_when_route_uses_the_local_broker":         {config: brokers.Config{Routes: []brokers.Route{{Domain: "example.com", Broker: brokers.LocalBrokerName}}}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := brokers.NewManager(context.Background(), t.TempDir(), nil, tc.config)
+			require.Error(t, err, "NewManager should return an error, but did not")
+		})
+	}
+}
+
+func TestRoutedBroker(t *testing.T) {
+	t.Parallel()
+
+	brokersConfPath := filepath.Join(brokerConfFixtures, "valid_brokers")
+	m, err := brokers.NewManager(context.Background(), brokersConfPath, nil, brokers.DefaultConfig)
+	require.NoError(t, err, "Setup: could not create manager")
+	first, second := m.AvailableBrokers()[1], m.AvailableBrokers()[2]
+
+	m, err = brokers.NewManager(context.Background(), brokersConfPath, nil, brokers.Config{Routes: []brokers.Route{
+		{Domain: "example.com", Broker: first.ID, Mandatory: true},
+		{Username: "locked-.*", Broker: "not-available", Mandatory: true},
+		{Username: "admin-.*", Broker: second.ID},
+		{Username: ".*", Broker: "not-available"},
+		{Username: ".*", Broker: first.ID},
+	}})
+	require.NoError(t, err, "Setup: could not create manager with routes")
+
+	tests := map[string]struct {
+		username string
+
+		wantBroker    string
+		wantMandatory bool
+	}{
+		"Routes_user_by_domain":                {username: "user@example.com", wantBroker: first.ID, wantMandatory: true},
+		"Routes_user_by_domain_ignoring_case":  {username: "User@Example.COM", wantBroker: first.ID, wantMandatory: true},
+		"Routes_user_by_username_pattern":      {username: "admin-user", wantBroker: second.ID},
+		"Skips_routes_to_unavailable_brokers":  {username: "user", wantBroker: first.ID},
+		"Uses_first_route_matching_the_user":   {username: "admin-user@example.com", wantBroker: first.ID, wantMandatory: true},
+		"Does_not_match_domain_as_a_substring": {username: "user@example.com.evil.org", wantBroker: first.ID},
+
+		"Keeps_mandatory_routes_to_unavailable_brokers": {username: "locked-user", wantMandatory: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, mandatory := m.RoutedBroker(tc.username)
+			require.Equal(t, tc.wantMandatory, mandatory, "RoutedBroker should return whether the route is mandatory")
+			if tc.wantBroker == "" {
+				require.Nil(t, got, "RoutedBroker should not return a broker")
+				return
+			}
+			require.NotNil(t, got, "RoutedBroker should return a broker")
+			require.Equal(t, tc.wantBroker, got.ID, "RoutedBroker should return the expected broker")
+		})
+	}
+}
+
+func TestReapSessions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		expire    bool
+		keepAlive bool
+
+		wantReaped bool
+	}{
+		"Reaps_expired_session": {expire: true, wantReaped: true},
+
+		"Keeps_session_with_recent_activity":     {},
+		"Keeps_expired_session_with_active_call": {expire: true, keepAlive: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersConfPath := t.TempDir()
+			b := newBrokerForTests(t, brokersConfPath, strings.ReplaceAll(t.Name(), "/", "_"))
+			m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b.Name + ".conf"}, brokers.DefaultConfig)
+			require.NoError(t, err, "Setup: could not create manager")
+			t.Cleanup(m.Stop)
+
+			for _, broker := range m.AvailableBrokers() {
+				if broker.Name == b.Name {
+					b.ID = broker.ID
+				}
+			}
+
+			_, sessionID, _, err := m.NewSession(b.ID, "user1", "some_lang", "auth", brokers.PAMContext{}, permissions.Peer{})
+			require.NoError(t, err, "Setup: NewSession should not return an error, but did")
+			broker, err := m.BrokerFromSessionID(sessionID)
+			require.NoError(t, err, "Setup: BrokerFromSessionID should not return an error, but did")
+			_, err = broker.GetAuthenticationModes(context.Background(), sessionID, []map[string]string{supportedLayouts["required-entry"]})
+			require.NoError(t, err, "Setup: GetAuthenticationModes should not return an error, but did")
+
+			if tc.keepAlive {
+				release := m.KeepSessionAlive(sessionID)
+				t.Cleanup(release)
+			}
+			if tc.expire {
+				m.ExpireSession(sessionID)
+			}
+
+			m.ReapExpiredSessions()
+
+			_, err = m.BrokerFromSessionID(sessionID)
+			if tc.wantReaped {
+				require.Error(t, err, "Expired session should have been reaped, but was not")
+				require.Empty(t, m.Sessions(), "Reaped session should not be listed")
+				require.False(t, broker.HasLayoutValidators(sessionID), "Reaped session should not have layout validators")
+				return
+			}
+			require.NoError(t, err, "Session should not have been reaped, but was")
+			require.True(t, broker.HasLayoutValidators(sessionID), "Session should still have layout validators")
+
+			sessions := m.Sessions()
+			require.Len(t, sessions, 1, "Session should be listed")
+			require.Equal(t, sessionID, sessions[0].ID, "Listed session should have the expected ID")
+			require.Equal(t, b.ID, sessions[0].BrokerID, "Listed session should have the expected broker ID")
+			require.Equal(t, "user1", sessions[0].Username, "Listed session should have the expected username")
+			require.Equal(t, "auth", sessions[0].Mode, "Listed session should have the expected mode")
+		})
+	}
+}
+
+func TestWatchUsers(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		member        string
+		args          []any
+		fromOtherConn bool
+
+		want *brokers.UserNotification
+	}{
+		"Notifies_changed_user":                  {member: "UserChanged", args: []any{"user1"}, want: &brokers.UserNotification{Username: "user1"}},
+		"Notifies_removed_user":                  {member: "UserRemoved", args: []any{"user1"}, want: &brokers.UserNotification{Username: "user1", Removed: true}},
+		"Notifies_user_with_a_lowercase_name":    {member: "UserChanged", args: []any{"User1"}, want: &brokers.UserNotification{Username: "user1"}},
+		"Ignores_unknown_signals":                {member: "SomethingChanged", args: []any{"user1"}},
+		"Ignores_signal_without_username":        {member: "UserChanged"},
+		"Ignores_signal_with_an_empty_username":  {member: "UserChanged", args: []any{""}},
+		"Ignores_signal_with_an_invalid_type":    {member: "UserChanged", args: []any{uint32(42)}},
+		"Ignores_signal_with_too_many_arguments": {member: "UserRemoved", args: []any{"user1", "user2"}},
+		"Ignores_signal_not_sent_by_the_broker":  {member: "UserRemoved", args: []any{"user1"}, fromOtherConn: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokerName := strings.ReplaceAll(t.Name(), "/", "_")
+			cfgDir := t.TempDir()
+			_, cleanup, err := testutils.StartBusBrokerMock(cfgDir, brokerName)
+			require.NoError(t, err, "Setup: could not start bus broker mock")
+			t.Cleanup(cleanup)
+
+			m, err := brokers.NewManager(context.Background(), cfgDir, nil, brokers.DefaultConfig)
+			require.NoError(t, err, "Setup: could not create manager")
+			t.Cleanup(m.Stop)
+			brokerID := m.AvailableBrokers()[1].ID
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			notifications, err := m.WatchUsers(ctx)
+			require.NoError(t, err, "WatchUsers should not return an error, but did")
+
+			if tc.fromOtherConn {
+				conn, err := testutils.GetSystemBusConnection(t)
+				require.NoError(t, err, "Setup: could not connect to system bus")
+				t.Cleanup(func() { _ = conn.Close() })
+				err = conn.Emit(dbus.ObjectPath("/com/ubuntu/authd/"+brokerName), brokers.DbusInterface+"."+tc.member, tc.args...)
+				require.NoError(t, err, "Setup: could not emit signal")
+			} else {
+				err = testutils.EmitBrokerSignal(brokerName, tc.member, tc.args...)
+				require.NoError(t, err, "Setup: could not emit signal")
+			}
+
+			want := tc.want
+			if want == nil {
+				// Signals are delivered in order, so the next notification is this one if the first signal was ignored.
+				want = &brokers.UserNotification{Username: "sentinel"}
+				err = testutils.EmitBrokerSignal(brokerName, "UserChanged", "sentinel")
+				require.NoError(t, err, "Setup: could not emit signal")
+			}
+			want.BrokerID = brokerID
+
+			select {
+			case got := <-notifications:
+				require.Equal(t, *want, got, "WatchUsers should send the expected notification")
+			case <-time.After(5 * time.Second):
+				t.Fatal("WatchUsers should have sent a notification, but did not")
+			}
+
+			cancel()
+			for range notifications {
+			}
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	log.SetLevel(log.DebugLevel)
 
@@ -0,0 +1,250 @@
+package brokers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// userChangedSignal is emitted by a broker when the information or the groups of a user changed.
+	userChangedSignal = "UserChanged"
+	// userRemovedSignal is emitted by a broker when a user was removed or disabled.
+	userRemovedSignal = "UserRemoved"
+	// authenticationProgressSignal is emitted by a broker with a status message for an ongoing IsAuthenticated call.
+	authenticationProgressSignal = "AuthenticationProgress"
+)
+
+// UserNotification is a change of a user pushed by its broker.
+type UserNotification struct {
+	BrokerID string
+	Username string
+	// Removed is true if the user was removed or disabled, and false if its information changed.
+	Removed bool
+}
+
+// signalSource identifies the D-Bus object of a broker.
+type signalSource struct {
+	brokerID string
+	name     string
+	path     dbus.ObjectPath
+}
+
+// WatchUsers subscribes to the user change signals of all the D-Bus brokers. The notifications are sent on the returned
+// channel, which is closed once the context is done.
+func (m *Manager) WatchUsers(ctx context.Context) (notifications <-chan UserNotification, err error) {
+	defer decorate.OnError(&err, "can't watch user changes from brokers")
+
+	var sources []signalSource
+	for _, b := range m.AvailableBrokers() {
+		db, ok := b.brokerer.(dbusBroker)
+		if !ok {
+			continue
+		}
+		sources = append(sources, signalSource{
+			brokerID: b.ID,
+			name:     db.dbusObject.Destination(),
+			path:     db.dbusObject.Path(),
+		})
+	}
+
+	var matches [][]dbus.MatchOption
+	for _, src := range sources {
+		for _, member := range []string{userChangedSignal, userRemovedSignal} {
+			match := []dbus.MatchOption{
+				dbus.WithMatchSender(src.name),
+				dbus.WithMatchObjectPath(src.path),
+				dbus.WithMatchInterface(DbusInterface),
+				dbus.WithMatchMember(member),
+			}
+			if err := m.bus.AddMatchSignal(match...); err != nil {
+				for _, match := range matches {
+					_ = m.bus.RemoveMatchSignal(match...)
+				}
+				return nil, err
+			}
+			matches = append(matches, match)
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	m.bus.Signal(signals)
+
+	ch := make(chan UserNotification)
+	go func() {
+		defer close(ch)
+		defer func() {
+			m.bus.RemoveSignal(signals)
+			for _, match := range matches {
+				_ = m.bus.RemoveMatchSignal(match...)
+			}
+		}()
+
+		for {
+			var s *dbus.Signal
+			select {
+			case <-ctx.Done():
+				return
+			case s = <-signals:
+			}
+			if s == nil {
+				// The connection was closed.
+				return
+			}
+
+			n, err := m.userNotificationFromSignal(s, sources)
+			if err != nil {
+				log.Warningf(ctx, "Ignoring signal %q from %q: %v", s.Name, s.Sender, err)
+				continue
+			}
+			if n == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- *n:
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// userNotificationFromSignal returns the notification matching the signal, or nil if the signal isn't a user change
+// signal. The signal must be emitted by the current owner of the D-Bus name of a broker.
+func (m *Manager) userNotificationFromSignal(s *dbus.Signal, sources []signalSource) (*UserNotification, error) {
+	var removed bool
+	switch s.Name {
+	case DbusInterface + "." + userChangedSignal:
+	case DbusInterface + "." + userRemovedSignal:
+		removed = true
+	default:
+		return nil, nil
+	}
+
+	for _, src := range sources {
+		if s.Path != src.path {
+			continue
+		}
+
+		var owner string
+		if err := m.bus.BusObject().Call("org.freedesktop.DBus.GetNameOwner", 0, src.name).Store(&owner); err != nil {
+			continue
+		}
+		if owner != s.Sender {
+			continue
+		}
+
+		if len(s.Body) != 1 {
+			return nil, fmt.Errorf("expected a single username argument, got %d arguments", len(s.Body))
+		}
+		username, ok := s.Body[0].(string)
+		if !ok || username == "" {
+			return nil, fmt.Errorf("invalid username %v", s.Body[0])
+		}
+
+		return &UserNotification{
+			BrokerID: src.brokerID,
+			// authd uses lowercase usernames
+			Username: strings.ToLower(username),
+			Removed:  removed,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("sender is not a broker")
+}
+
+// watchAuthenticationProgress subscribes to the authentication progress signals of the broker for the session and calls
+// onProgress with their messages, until the returned stop function is called.
+func (b dbusBroker) watchAuthenticationProgress(ctx context.Context, sessionID string, onProgress func(message string)) (stop func(), err error) {
+	defer decorate.OnError(&err, "can't watch authentication progress of session %q", sessionID)
+
+	// The broker is running at this point, as the session was created by it.
+	owner := b.nameOwner(ctx)
+	if owner == "" {
+		return nil, fmt.Errorf("broker %q is not running", b.name)
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchSender(b.dbusObject.Destination()),
+		dbus.WithMatchObjectPath(b.dbusObject.Path()),
+		dbus.WithMatchInterface(DbusInterface),
+		dbus.WithMatchMember(authenticationProgressSignal),
+		dbus.WithMatchArg(0, sessionID),
+	}
+	if err := b.bus.AddMatchSignal(match...); err != nil {
+		return nil, err
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	b.bus.Signal(signals)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			var s *dbus.Signal
+			select {
+			case <-done:
+				return
+			case s = <-signals:
+			}
+			if s == nil {
+				// The connection was closed.
+				return
+			}
+
+			message, err := b.progressMessageFromSignal(s, owner, sessionID)
+			if err != nil {
+				log.Warningf(ctx, "Ignoring signal %q from %q: %v", s.Name, s.Sender, err)
+				continue
+			}
+			if message == "" {
+				continue
+			}
+			onProgress(message)
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+		b.bus.RemoveSignal(signals)
+		_ = b.bus.RemoveMatchSignal(match...)
+	}, nil
+}
+
+// progressMessageFromSignal returns the message of the signal, or an empty string if the signal isn't an authentication
+// progress signal of the broker for the session. The signal must be emitted by the broker instance owning the session.
+func (b dbusBroker) progressMessageFromSignal(s *dbus.Signal, owner, sessionID string) (string, error) {
+	if s.Name != DbusInterface+"."+authenticationProgressSignal || s.Path != b.dbusObject.Path() || s.Sender != owner {
+		return "", nil
+	}
+
+	if len(s.Body) != 2 {
+		return "", fmt.Errorf("expected a session ID and a message arguments, got %d arguments", len(s.Body))
+	}
+	if id, ok := s.Body[0].(string); !ok || id != sessionID {
+		return "", nil
+	}
+	message, ok := s.Body[1].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid message %v", s.Body[1])
+	}
+	if strings.ContainsFunc(message, unicode.IsControl) {
+		return "", errors.New("message contains control characters")
+	}
+
+	return message, nil
+}
@@ -0,0 +1,96 @@
+package brokers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/ubuntu/authd/log"
+)
+
+// Route maps the users matching a username pattern or a domain to a broker.
+type Route struct {
+	// Username is a regular expression that must fully match the username, ignoring case.
+	Username string `mapstructure:"username" yaml:"username,omitempty"`
+	// Domain matches the usernames ending with @domain, ignoring case.
+	Domain string `mapstructure:"domain" yaml:"domain,omitempty"`
+	// Broker is the ID of the broker to use for the matching users.
+	Broker string `mapstructure:"broker" yaml:"broker"`
+	// Mandatory prevents the matching users from using any other broker.
+	Mandatory bool `mapstructure:"mandatory" yaml:"mandatory,omitempty"`
+}
+
+// route is a parsed [Route]. The broker of a mandatory route is nil if it's not available.
+type route struct {
+	pattern   *regexp.Regexp
+	broker    *Broker
+	mandatory bool
+}
+
+// parseRoutes validates the routes configuration, skipping the routes to brokers that are not available unless they
+// are mandatory, in which case the matching users are denied.
+func (m *Manager) parseRoutes(ctx context.Context, routes []Route) (r []route, err error) {
+	for i, rt := range routes {
+		var pattern string
+		switch {
+		case rt.Username != "" && rt.Domain != "":
+			return nil, fmt.Errorf("broker route %d can't have both a username and a domain", i)
+		case rt.Username != "":
+			pattern = rt.Username
+		case rt.Domain != "":
+			pattern = ".*@" + regexp.QuoteMeta(rt.Domain)
+		default:
+			return nil, fmt.Errorf("broker route %d needs either a username or a domain", i)
+		}
+		if rt.Broker == "" {
+			return nil, fmt.Errorf("broker route %d has no broker", i)
+		}
+
+		re, err := regexp.Compile("(?i)^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("broker route %d has an invalid username pattern: %v", i, err)
+		}
+
+		b, err := m.brokerFromID(rt.Broker)
+		if err != nil && !rt.Mandatory {
+			log.Warningf(ctx, "Ignoring broker route %d: %v", i, err)
+			continue
+		}
+		if err != nil {
+			log.Warningf(ctx, "Denying the users matching mandatory broker route %d: %v", i, err)
+		}
+		if b != nil && b.ID == LocalBrokerName {
+			return nil, fmt.Errorf("broker route %d can't use the local broker", i)
+		}
+
+		r = append(r, route{pattern: re, broker: b, mandatory: rt.Mandatory})
+	}
+
+	return r, nil
+}
+
+// RoutedBroker returns the broker the user is routed to by the first matching route, if any, and whether the route
+// is mandatory. The broker is nil if the user is routed to a broker which is not available.
+func (m *Manager) RoutedBroker(username string) (broker *Broker, mandatory bool) {
+	for _, r := range m.routes {
+		if r.pattern.MatchString(username) {
+			return r.broker, r.mandatory
+		}
+	}
+	return nil, false
+}
+
+// checkRoute returns an error if a mandatory route assigns the user to a different broker.
+func (m *Manager) checkRoute(username string, broker *Broker) error {
+	routed, mandatory := m.RoutedBroker(username)
+	if !mandatory {
+		return nil
+	}
+	if routed == nil {
+		return fmt.Errorf("user %q must use a broker which is not available", username)
+	}
+	if routed.ID != broker.ID {
+		return fmt.Errorf("user %q must use %s", username, routed.Name)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package brokers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ubuntu/authd/internal/services/errmessages"
+)
+
+// ErrTooManySessions is returned when a new session would exceed the configured session limits.
+var ErrTooManySessions = errors.New("too many ongoing authentication sessions")
+
+// SessionLimitsHandler is called whenever a broker reaches its session limit or goes back below it.
+type SessionLimitsHandler func(brokerID string, limitReached bool)
+
+// sessionCounts tracks the number of ongoing sessions per broker and per user, including the sessions that are being
+// started. It is protected by the sessionsMu mutex of the manager.
+type sessionCounts struct {
+	perBroker map[string]int
+	perUser   map[string]int
+}
+
+// SessionLimits returns the configured maximum number of concurrent sessions per broker and per user. Zero means no
+// limit.
+func (m *Manager) SessionLimits() (perBroker, perUser int) {
+	return m.maxSessionsPerBroker, m.maxSessionsPerUser
+}
+
+// SetSessionLimitsHandler registers the function called when a broker reaches its session limit or goes back below
+// it. It is called right away with the current state of each broker. It must not call the manager back.
+func (m *Manager) SetSessionLimitsHandler(f SessionLimitsHandler) {
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
+
+	m.sessionLimitsHandler = f
+	if f == nil || m.maxSessionsPerBroker == 0 {
+		return
+	}
+	for _, id := range m.brokersOrder {
+		f(id, m.sessionCounts.perBroker[id] >= m.maxSessionsPerBroker)
+	}
+}
+
+// reserveSession counts a new session for the broker and the user, failing if this exceeds the session limits.
+// An empty broker ID or username is not counted.
+func (m *Manager) reserveSession(brokerID, username string) error {
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
+
+	if brokerID != "" && m.maxSessionsPerBroker > 0 && m.sessionCounts.perBroker[brokerID] >= m.maxSessionsPerBroker {
+		return fmt.Errorf("%w: %w", ErrTooManySessions, errmessages.NewToDisplayErrorf("the broker can't handle more sessions, try again later"))
+	}
+	if username != "" && m.maxSessionsPerUser > 0 && m.sessionCounts.perUser[username] >= m.maxSessionsPerUser {
+		return fmt.Errorf("%w: %w", ErrTooManySessions, errmessages.NewToDisplayErrorf("%q has too many ongoing sessions, try again later", username))
+	}
+
+	if brokerID != "" {
+		m.sessionCounts.perBroker[brokerID]++
+		if m.maxSessionsPerBroker > 0 && m.sessionCounts.perBroker[brokerID] == m.maxSessionsPerBroker {
+			m.notifySessionLimits(brokerID, true)
+		}
+	}
+	if username != "" {
+		m.sessionCounts.perUser[username]++
+	}
+	return nil
+}
+
+// releaseSession stops counting a session for the broker and the user. An empty broker ID or username is ignored.
+func (m *Manager) releaseSession(brokerID, username string) {
+	m.sessionsMu.Lock()
+	defer m.sessionsMu.Unlock()
+
+	if brokerID != "" && m.sessionCounts.perBroker[brokerID] > 0 {
+		m.sessionCounts.perBroker[brokerID]--
+		if m.maxSessionsPerBroker > 0 && m.sessionCounts.perBroker[brokerID] == m.maxSessionsPerBroker-1 {
+			m.notifySessionLimits(brokerID, false)
+		}
+		if m.sessionCounts.perBroker[brokerID] == 0 {
+			delete(m.sessionCounts.perBroker, brokerID)
+		}
+	}
+	if username != "" && m.sessionCounts.perUser[username] > 0 {
+		m.sessionCounts.perUser[username]--
+		if m.sessionCounts.perUser[username] == 0 {
+			delete(m.sessionCounts.perUser, username)
+		}
+	}
+}
+
+// notifySessionLimits calls the session limits handler, if any. It must be called with sessionsMu held.
+func (m *Manager) notifySessionLimits(brokerID string, limitReached bool) {
+	if m.sessionLimitsHandler == nil {
+		return
+	}
+	m.sessionLimitsHandler(brokerID, limitReached)
+}
@@ -44,7 +44,8 @@ func useExampleBrokers() (string, func(), error) {
 	}, nil
 }
 
-// Stop calls the function responsible for cleaning up the examplebrokers.
+// Stop stops the manager background tasks and calls the function responsible for cleaning up the examplebrokers.
 func (m *Manager) Stop() {
+	m.stop()
 	m.cleanup()
 }
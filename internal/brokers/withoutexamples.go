@@ -7,5 +7,7 @@ func useExampleBrokers() (string, func(), error) {
 	return "", nil, nil
 }
 
-// Stop is a no-op in production code.
-func (m *Manager) Stop() {}
+// Stop stops the manager background tasks.
+func (m *Manager) Stop() {
+	m.stop()
+}
@@ -0,0 +1,9 @@
+package i18n
+
+// ParsePO exposes parsePO for tests.
+var ParsePO = parsePO
+
+// ReadCatalog returns the content of the embedded catalog of the language.
+func ReadCatalog(lang string) ([]byte, error) {
+	return poFiles.ReadFile("po/" + lang + ".po")
+}
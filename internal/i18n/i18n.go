@@ -0,0 +1,110 @@
+// Package i18n translates the user-facing messages of authd with gettext-style catalogs.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ubuntu/authd/log"
+)
+
+// defaultLang is the language of the messages in the sources, which is never translated.
+const defaultLang = "C"
+
+//go:embed po/*.po
+var poFiles embed.FS
+
+var (
+	catalogs     map[string]map[string]string
+	catalogsOnce sync.Once
+)
+
+// loadCatalogs parses the embedded catalogs, indexed by their language.
+func loadCatalogs() {
+	catalogs = make(map[string]map[string]string)
+
+	files, err := poFiles.ReadDir("po")
+	if err != nil {
+		log.Warningf(context.Background(), "Can't read translation catalogs: %v", err)
+		return
+	}
+	for _, f := range files {
+		path := filepath.Join("po", f.Name())
+		content, err := poFiles.ReadFile(path)
+		if err != nil {
+			log.Warningf(context.Background(), "Can't read translation catalog %q: %v", path, err)
+			continue
+		}
+		catalog, err := parsePO(content)
+		if err != nil {
+			log.Warningf(context.Background(), "Ignoring invalid translation catalog %q: %v", path, err)
+			continue
+		}
+		catalogs[strings.TrimSuffix(f.Name(), ".po")] = catalog
+	}
+}
+
+// Lang returns the language of the messages of the current process, as set in its environment.
+func Lang() string {
+	if l := LangFromEnv(os.Getenv); l != "" {
+		return l
+	}
+	return defaultLang
+}
+
+// LangFromEnv returns the language of the messages set in the environment read with getenv, or an empty string if it
+// sets none.
+func LangFromEnv(getenv func(string) string) string {
+	for _, e := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := getenv(e); l != "" {
+			return strings.TrimSuffix(l, ".UTF-8")
+		}
+	}
+	return ""
+}
+
+// Translate returns the translation of msgid in lang, or msgid if there is none.
+//
+// The encoding and modifier of lang are ignored, and the language alone is used if there is no catalog for its
+// territory (for instance, "fr" for "fr_CA.UTF-8").
+func Translate(lang, msgid string) string {
+	catalogsOnce.Do(loadCatalogs)
+
+	for _, l := range candidateLanguages(lang) {
+		if msgstr, ok := catalogs[l][msgid]; ok {
+			return msgstr
+		}
+	}
+	return msgid
+}
+
+// Sprintf formats the translation of format in lang with args, as fmt.Sprintf does.
+func Sprintf(lang, format string, args ...any) string {
+	return fmt.Sprintf(Translate(lang, format), args...)
+}
+
+// G returns the translation of msgid in the language of the current process.
+func G(msgid string) string {
+	return Translate(Lang(), msgid)
+}
+
+// candidateLanguages returns the catalogs to look up for lang, from the most to the least specific.
+func candidateLanguages(lang string) []string {
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "" || lang == defaultLang || lang == "POSIX" {
+		return nil
+	}
+
+	candidates := []string{lang}
+	if language, _, found := strings.Cut(lang, "_"); found {
+		candidates = append(candidates, language)
+	}
+	return candidates
+}
@@ -0,0 +1,195 @@
+package i18n_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/i18n"
+)
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		lang  string
+		msgid string
+
+		want string
+	}{
+		"Translate_with_language":                        {lang: "fr", msgid: "Username", want: "Nom d'utilisateur"},
+		"Translate_with_territory_of_language":           {lang: "fr_CA", msgid: "Username", want: "Nom d'utilisateur"},
+		"Translate_ignoring_encoding_and_modifier":       {lang: "fr_FR.UTF-8@euro", msgid: "Username", want: "Nom d'utilisateur"},
+		"Untranslated_message_is_returned_as_is":         {lang: "fr", msgid: "Not a translated message", want: "Not a translated message"},
+		"Unknown_language_returns_message_as_is":         {lang: "xx_XX", msgid: "Username", want: "Username"},
+		"C_language_returns_message_as_is":               {lang: "C", msgid: "Username", want: "Username"},
+		"C_language_with_encoding_returns_message_as_is": {lang: "C.UTF-8", msgid: "Username", want: "Username"},
+		"POSIX_language_returns_message_as_is":           {lang: "POSIX", msgid: "Username", want: "Username"},
+		"Empty_language_returns_message_as_is":           {lang: "", msgid: "Username", want: "Username"},
+		"Header_of_catalog_is_not_a_translation":         {lang: "fr", msgid: "", want: ""},
+		"Language_prefix_is_not_matched":                 {lang: "fra", msgid: "Username", want: "Username"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := i18n.Translate(tc.lang, tc.msgid)
+			require.Equal(t, tc.want, got, "Translate returned an unexpected message")
+		})
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	t.Parallel()
+
+	got := i18n.Sprintf("fr_FR", "user %q is not allowed to use %s", "user1", "broker1")
+	require.Equal(t, `l'utilisateur "user1" n'est pas autorisé à utiliser broker1`, got, "Sprintf returned an unexpected message")
+
+	got = i18n.Sprintf("C", "user %q is not allowed to use %s", "user1", "broker1")
+	require.Equal(t, `user "user1" is not allowed to use broker1`, got, "Sprintf returned an unexpected message")
+}
+
+//nolint:tparallel // Subtests can't be parallel as they change the environment.
+func TestLang(t *testing.T) {
+	tests := map[string]struct {
+		lcAll      string
+		lcMessages string
+		lang       string
+
+		want string
+	}{
+		"LC_ALL_takes_precedence":              {lcAll: "fr_FR.UTF-8", lcMessages: "de_DE", lang: "it_IT", want: "fr_FR"},
+		"LC_MESSAGES_takes_precedence_on_LANG": {lcMessages: "de_DE.UTF-8", lang: "it_IT", want: "de_DE"},
+		"LANG_is_used_as_a_fallback":           {lang: "it_IT.UTF-8", want: "it_IT"},
+		"Other_encodings_are_kept":             {lang: "fr_FR.ISO-8859-1", want: "fr_FR.ISO-8859-1"},
+		"Default_to_C":                         {want: "C"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tc.lcAll)
+			t.Setenv("LC_MESSAGES", tc.lcMessages)
+			t.Setenv("LANG", tc.lang)
+
+			require.Equal(t, tc.want, i18n.Lang(), "Lang returned an unexpected language")
+		})
+	}
+}
+
+func TestLangFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env map[string]string
+
+		want string
+	}{
+		"LC_ALL_takes_precedence":    {env: map[string]string{"LC_ALL": "fr_FR.UTF-8", "LANG": "it_IT"}, want: "fr_FR"},
+		"LANG_is_used_as_a_fallback": {env: map[string]string{"LANG": "it_IT.UTF-8"}, want: "it_IT"},
+		"Empty_without_language":     {env: map[string]string{"PATH": "/usr/bin"}, want: ""},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := i18n.LangFromEnv(func(name string) string { return tc.env[name] })
+			require.Equal(t, tc.want, got, "LangFromEnv returned an unexpected language")
+		})
+	}
+}
+
+func TestParsePO(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+
+		want    map[string]string
+		wantErr bool
+	}{
+		"Parse_messages": {
+			content: `msgid "Hello"
+msgstr "Bonjour"
+
+msgid "Goodbye"
+msgstr "Au revoir"
+`,
+			want: map[string]string{"Hello": "Bonjour", "Goodbye": "Au revoir"},
+		},
+		"Parse_messages_without_blank_lines_between_them": {
+			content: "msgid \"Hello\"\nmsgstr \"Bonjour\"\nmsgid \"Goodbye\"\nmsgstr \"Au revoir\"\n",
+			want:    map[string]string{"Hello": "Bonjour", "Goodbye": "Au revoir"},
+		},
+		"Parse_multi-line_and_escaped_strings": {
+			content: `msgid ""
+"Say \"hello\"\n"
+"to everyone"
+msgstr ""
+"Dites \"bonjour\"\n"
+"à tout le monde"
+`,
+			want: map[string]string{"Say \"hello\"\nto everyone": "Dites \"bonjour\"\nà tout le monde"},
+		},
+		"Skip_header_comments_fuzzy_and_untranslated_messages": {
+			content: `# Translator comment
+msgid ""
+msgstr ""
+"Language: fr\n"
+
+#: file.go
+msgid "Hello"
+msgstr "Bonjour"
+
+#, fuzzy
+msgid "Goodbye"
+msgstr "Au revoir"
+
+#, c-format
+msgid "Hello %s"
+msgstr "Bonjour %s"
+
+msgid "Untranslated"
+msgstr ""
+`,
+			want: map[string]string{"Hello": "Bonjour", "Hello %s": "Bonjour %s"},
+		},
+		"Empty_catalog": {want: map[string]string{}},
+
+		// Error cases
+		"Error_on_msgstr_without_msgid":        {content: `msgstr "Bonjour"`, wantErr: true},
+		"Error_on_string_without_keyword":      {content: `"Bonjour"`, wantErr: true},
+		"Error_on_unsupported_keyword":         {content: "msgid \"Hello\"\nmsgid_plural \"Hellos\"\n", wantErr: true},
+		"Error_on_invalid_string":              {content: `msgid "Hello`, wantErr: true},
+		"Error_on_invalid_continuation_string": {content: "msgid \"Hello\"\n\"world\n", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := i18n.ParsePO([]byte(tc.content))
+			if tc.wantErr {
+				require.Error(t, err, "ParsePO should return an error")
+				return
+			}
+			require.NoError(t, err, "ParsePO should not return an error")
+			require.Equal(t, tc.want, got, "ParsePO returned unexpected messages")
+		})
+	}
+}
+
+func TestCatalogsKeepFormatVerbs(t *testing.T) {
+	t.Parallel()
+
+	verbs := regexp.MustCompile(`%(\[\d+\])?[a-z]`)
+	for _, lang := range []string{"fr"} {
+		content, err := i18n.ReadCatalog(lang)
+		require.NoError(t, err, "Setup: catalog should be readable")
+		catalog, err := i18n.ParsePO(content)
+		require.NoError(t, err, "Catalog %q should be valid", lang)
+		require.NotEmpty(t, catalog, "Catalog %q should have translations", lang)
+
+		for msgid, msgstr := range catalog {
+			require.ElementsMatch(t, verbs.FindAllString(msgid, -1), verbs.FindAllString(msgstr, -1),
+				"Translation of %q in %q should use the same format verbs", msgid, lang)
+		}
+	}
+}
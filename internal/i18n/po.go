@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// poEntry is a message of a PO catalog being parsed.
+type poEntry struct {
+	msgid  strings.Builder
+	msgstr strings.Builder
+	fuzzy  bool
+	// current is the string that the continuation lines are appended to.
+	current *strings.Builder
+}
+
+// parsePO returns the translations of the PO catalog, indexed by their message ID.
+//
+// Only the singular messages are supported. The header, the fuzzy and the untranslated entries are skipped.
+func parsePO(content []byte) (map[string]string, error) {
+	catalog := make(map[string]string)
+
+	var entry *poEntry
+	addEntry := func() {
+		if entry == nil {
+			return
+		}
+		if entry.msgid.Len() > 0 && entry.msgstr.Len() > 0 && !entry.fuzzy {
+			catalog[entry.msgid.String()] = entry.msgstr.String()
+		}
+		entry = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			addEntry()
+
+		case strings.HasPrefix(line, "#,"):
+			addEntry()
+			entry = &poEntry{fuzzy: strings.Contains(line, "fuzzy")}
+
+		case strings.HasPrefix(line, "#"):
+			// Other comments are not relevant to the translations.
+
+		case strings.HasPrefix(line, "msgid "):
+			// An entry which already has a message ID is complete: this starts a new one.
+			if entry != nil && entry.current != nil {
+				addEntry()
+			}
+			if entry == nil {
+				entry = &poEntry{}
+			}
+			entry.current = &entry.msgid
+			if err := appendPOString(entry.current, strings.TrimPrefix(line, "msgid ")); err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+
+		case strings.HasPrefix(line, "msgstr "):
+			if entry == nil || entry.current != &entry.msgid {
+				return nil, fmt.Errorf("line %d: msgstr without msgid", n)
+			}
+			entry.current = &entry.msgstr
+			if err := appendPOString(entry.current, strings.TrimPrefix(line, "msgstr ")); err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+
+		case strings.HasPrefix(line, `"`):
+			if entry == nil || entry.current == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", n)
+			}
+			if err := appendPOString(entry.current, line); err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+
+		default:
+			return nil, fmt.Errorf("line %d: unsupported keyword in %q", n, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	addEntry()
+
+	return catalog, nil
+}
+
+// appendPOString appends the unquoted PO string s to b.
+func appendPOString(b *strings.Builder, s string) error {
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return fmt.Errorf("invalid string %s: %v", s, err)
+	}
+	b.WriteString(unquoted)
+	return nil
+}
@@ -21,26 +21,41 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	PAM_AvailableBrokers_FullMethodName         = "/authd.PAM/AvailableBrokers"
 	PAM_GetPreviousBroker_FullMethodName        = "/authd.PAM/GetPreviousBroker"
+	PAM_GetBrokerIcon_FullMethodName            = "/authd.PAM/GetBrokerIcon"
 	PAM_SelectBroker_FullMethodName             = "/authd.PAM/SelectBroker"
 	PAM_GetAuthenticationModes_FullMethodName   = "/authd.PAM/GetAuthenticationModes"
 	PAM_SelectAuthenticationMode_FullMethodName = "/authd.PAM/SelectAuthenticationMode"
 	PAM_IsAuthenticated_FullMethodName          = "/authd.PAM/IsAuthenticated"
+	PAM_IsAuthenticatedStream_FullMethodName    = "/authd.PAM/IsAuthenticatedStream"
 	PAM_EndSession_FullMethodName               = "/authd.PAM/EndSession"
 	PAM_SetDefaultBrokerForUser_FullMethodName  = "/authd.PAM/SetDefaultBrokerForUser"
+	PAM_CheckAccount_FullMethodName             = "/authd.PAM/CheckAccount"
+	PAM_OpenUserSession_FullMethodName          = "/authd.PAM/OpenUserSession"
+	PAM_CloseUserSession_FullMethodName         = "/authd.PAM/CloseUserSession"
+	PAM_SetUserCredentials_FullMethodName       = "/authd.PAM/SetUserCredentials"
+	PAM_ListSessions_FullMethodName             = "/authd.PAM/ListSessions"
 )
 
 // PAMClient is the client API for PAM service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PAMClient interface {
-	AvailableBrokers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ABResponse, error)
+	AvailableBrokers(ctx context.Context, in *ABRequest, opts ...grpc.CallOption) (*ABResponse, error)
 	GetPreviousBroker(ctx context.Context, in *GPBRequest, opts ...grpc.CallOption) (*GPBResponse, error)
+	GetBrokerIcon(ctx context.Context, in *GBIRequest, opts ...grpc.CallOption) (*GBIResponse, error)
 	SelectBroker(ctx context.Context, in *SBRequest, opts ...grpc.CallOption) (*SBResponse, error)
 	GetAuthenticationModes(ctx context.Context, in *GAMRequest, opts ...grpc.CallOption) (*GAMResponse, error)
 	SelectAuthenticationMode(ctx context.Context, in *SAMRequest, opts ...grpc.CallOption) (*SAMResponse, error)
 	IsAuthenticated(ctx context.Context, in *IARequest, opts ...grpc.CallOption) (*IAResponse, error)
+	// IsAuthenticatedStream is like IsAuthenticated, but also streams the progress messages of the broker before the result.
+	IsAuthenticatedStream(ctx context.Context, in *IARequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IAStreamResponse], error)
 	EndSession(ctx context.Context, in *ESRequest, opts ...grpc.CallOption) (*Empty, error)
 	SetDefaultBrokerForUser(ctx context.Context, in *SDBFURequest, opts ...grpc.CallOption) (*Empty, error)
+	CheckAccount(ctx context.Context, in *CARequest, opts ...grpc.CallOption) (*CAResponse, error)
+	OpenUserSession(ctx context.Context, in *USRequest, opts ...grpc.CallOption) (*USResponse, error)
+	CloseUserSession(ctx context.Context, in *USRequest, opts ...grpc.CallOption) (*USResponse, error)
+	SetUserCredentials(ctx context.Context, in *SUCRequest, opts ...grpc.CallOption) (*USResponse, error)
+	ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LSResponse, error)
 }
 
 type pAMClient struct {
@@ -51,7 +66,7 @@ func NewPAMClient(cc grpc.ClientConnInterface) PAMClient {
 	return &pAMClient{cc}
 }
 
-func (c *pAMClient) AvailableBrokers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ABResponse, error) {
+func (c *pAMClient) AvailableBrokers(ctx context.Context, in *ABRequest, opts ...grpc.CallOption) (*ABResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ABResponse)
 	err := c.cc.Invoke(ctx, PAM_AvailableBrokers_FullMethodName, in, out, cOpts...)
@@ -71,6 +86,16 @@ func (c *pAMClient) GetPreviousBroker(ctx context.Context, in *GPBRequest, opts
 	return out, nil
 }
 
+func (c *pAMClient) GetBrokerIcon(ctx context.Context, in *GBIRequest, opts ...grpc.CallOption) (*GBIResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GBIResponse)
+	err := c.cc.Invoke(ctx, PAM_GetBrokerIcon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *pAMClient) SelectBroker(ctx context.Context, in *SBRequest, opts ...grpc.CallOption) (*SBResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SBResponse)
@@ -111,6 +136,25 @@ func (c *pAMClient) IsAuthenticated(ctx context.Context, in *IARequest, opts ...
 	return out, nil
 }
 
+func (c *pAMClient) IsAuthenticatedStream(ctx context.Context, in *IARequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IAStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PAM_ServiceDesc.Streams[0], PAM_IsAuthenticatedStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IARequest, IAStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PAM_IsAuthenticatedStreamClient = grpc.ServerStreamingClient[IAStreamResponse]
+
 func (c *pAMClient) EndSession(ctx context.Context, in *ESRequest, opts ...grpc.CallOption) (*Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Empty)
@@ -131,18 +175,76 @@ func (c *pAMClient) SetDefaultBrokerForUser(ctx context.Context, in *SDBFUReques
 	return out, nil
 }
 
+func (c *pAMClient) CheckAccount(ctx context.Context, in *CARequest, opts ...grpc.CallOption) (*CAResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CAResponse)
+	err := c.cc.Invoke(ctx, PAM_CheckAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) OpenUserSession(ctx context.Context, in *USRequest, opts ...grpc.CallOption) (*USResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(USResponse)
+	err := c.cc.Invoke(ctx, PAM_OpenUserSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) CloseUserSession(ctx context.Context, in *USRequest, opts ...grpc.CallOption) (*USResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(USResponse)
+	err := c.cc.Invoke(ctx, PAM_CloseUserSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetUserCredentials(ctx context.Context, in *SUCRequest, opts ...grpc.CallOption) (*USResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(USResponse)
+	err := c.cc.Invoke(ctx, PAM_SetUserCredentials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LSResponse)
+	err := c.cc.Invoke(ctx, PAM_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PAMServer is the server API for PAM service.
 // All implementations must embed UnimplementedPAMServer
 // for forward compatibility.
 type PAMServer interface {
-	AvailableBrokers(context.Context, *Empty) (*ABResponse, error)
+	AvailableBrokers(context.Context, *ABRequest) (*ABResponse, error)
 	GetPreviousBroker(context.Context, *GPBRequest) (*GPBResponse, error)
+	GetBrokerIcon(context.Context, *GBIRequest) (*GBIResponse, error)
 	SelectBroker(context.Context, *SBRequest) (*SBResponse, error)
 	GetAuthenticationModes(context.Context, *GAMRequest) (*GAMResponse, error)
 	SelectAuthenticationMode(context.Context, *SAMRequest) (*SAMResponse, error)
 	IsAuthenticated(context.Context, *IARequest) (*IAResponse, error)
+	// IsAuthenticatedStream is like IsAuthenticated, but also streams the progress messages of the broker before the result.
+	IsAuthenticatedStream(*IARequest, grpc.ServerStreamingServer[IAStreamResponse]) error
 	EndSession(context.Context, *ESRequest) (*Empty, error)
 	SetDefaultBrokerForUser(context.Context, *SDBFURequest) (*Empty, error)
+	CheckAccount(context.Context, *CARequest) (*CAResponse, error)
+	OpenUserSession(context.Context, *USRequest) (*USResponse, error)
+	CloseUserSession(context.Context, *USRequest) (*USResponse, error)
+	SetUserCredentials(context.Context, *SUCRequest) (*USResponse, error)
+	ListSessions(context.Context, *Empty) (*LSResponse, error)
 	mustEmbedUnimplementedPAMServer()
 }
 
@@ -153,12 +255,15 @@ type PAMServer interface {
 // pointer dereference when methods are called.
 type UnimplementedPAMServer struct{}
 
-func (UnimplementedPAMServer) AvailableBrokers(context.Context, *Empty) (*ABResponse, error) {
+func (UnimplementedPAMServer) AvailableBrokers(context.Context, *ABRequest) (*ABResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AvailableBrokers not implemented")
 }
 func (UnimplementedPAMServer) GetPreviousBroker(context.Context, *GPBRequest) (*GPBResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPreviousBroker not implemented")
 }
+func (UnimplementedPAMServer) GetBrokerIcon(context.Context, *GBIRequest) (*GBIResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBrokerIcon not implemented")
+}
 func (UnimplementedPAMServer) SelectBroker(context.Context, *SBRequest) (*SBResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SelectBroker not implemented")
 }
@@ -171,12 +276,30 @@ func (UnimplementedPAMServer) SelectAuthenticationMode(context.Context, *SAMRequ
 func (UnimplementedPAMServer) IsAuthenticated(context.Context, *IARequest) (*IAResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IsAuthenticated not implemented")
 }
+func (UnimplementedPAMServer) IsAuthenticatedStream(*IARequest, grpc.ServerStreamingServer[IAStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method IsAuthenticatedStream not implemented")
+}
 func (UnimplementedPAMServer) EndSession(context.Context, *ESRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EndSession not implemented")
 }
 func (UnimplementedPAMServer) SetDefaultBrokerForUser(context.Context, *SDBFURequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultBrokerForUser not implemented")
 }
+func (UnimplementedPAMServer) CheckAccount(context.Context, *CARequest) (*CAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckAccount not implemented")
+}
+func (UnimplementedPAMServer) OpenUserSession(context.Context, *USRequest) (*USResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenUserSession not implemented")
+}
+func (UnimplementedPAMServer) CloseUserSession(context.Context, *USRequest) (*USResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseUserSession not implemented")
+}
+func (UnimplementedPAMServer) SetUserCredentials(context.Context, *SUCRequest) (*USResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserCredentials not implemented")
+}
+func (UnimplementedPAMServer) ListSessions(context.Context, *Empty) (*LSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
 func (UnimplementedPAMServer) mustEmbedUnimplementedPAMServer() {}
 func (UnimplementedPAMServer) testEmbeddedByValue()             {}
 
@@ -199,7 +322,7 @@ func RegisterPAMServer(s grpc.ServiceRegistrar, srv PAMServer) {
 }
 
 func _PAM_AvailableBrokers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(ABRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -211,7 +334,7 @@ func _PAM_AvailableBrokers_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: PAM_AvailableBrokers_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).AvailableBrokers(ctx, req.(*Empty))
+		return srv.(PAMServer).AvailableBrokers(ctx, req.(*ABRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -234,6 +357,24 @@ func _PAM_GetPreviousBroker_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PAM_GetBrokerIcon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GBIRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetBrokerIcon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetBrokerIcon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetBrokerIcon(ctx, req.(*GBIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PAM_SelectBroker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SBRequest)
 	if err := dec(in); err != nil {
@@ -306,6 +447,17 @@ func _PAM_IsAuthenticated_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PAM_IsAuthenticatedStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IARequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PAMServer).IsAuthenticatedStream(m, &grpc.GenericServerStream[IARequest, IAStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PAM_IsAuthenticatedStreamServer = grpc.ServerStreamingServer[IAStreamResponse]
+
 func _PAM_EndSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ESRequest)
 	if err := dec(in); err != nil {
@@ -342,6 +494,96 @@ func _PAM_SetDefaultBrokerForUser_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PAM_CheckAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).CheckAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_CheckAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).CheckAccount(ctx, req.(*CARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_OpenUserSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(USRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).OpenUserSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_OpenUserSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).OpenUserSession(ctx, req.(*USRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_CloseUserSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(USRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).CloseUserSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_CloseUserSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).CloseUserSession(ctx, req.(*USRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SetUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SUCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SetUserCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetUserCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetUserCredentials(ctx, req.(*SUCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).ListSessions(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PAM_ServiceDesc is the grpc.ServiceDesc for PAM service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -357,6 +599,10 @@ var PAM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPreviousBroker",
 			Handler:    _PAM_GetPreviousBroker_Handler,
 		},
+		{
+			MethodName: "GetBrokerIcon",
+			Handler:    _PAM_GetBrokerIcon_Handler,
+		},
 		{
 			MethodName: "SelectBroker",
 			Handler:    _PAM_SelectBroker_Handler,
@@ -381,8 +627,34 @@ var PAM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetDefaultBrokerForUser",
 			Handler:    _PAM_SetDefaultBrokerForUser_Handler,
 		},
+		{
+			MethodName: "CheckAccount",
+			Handler:    _PAM_CheckAccount_Handler,
+		},
+		{
+			MethodName: "OpenUserSession",
+			Handler:    _PAM_OpenUserSession_Handler,
+		},
+		{
+			MethodName: "CloseUserSession",
+			Handler:    _PAM_CloseUserSession_Handler,
+		},
+		{
+			MethodName: "SetUserCredentials",
+			Handler:    _PAM_SetUserCredentials_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _PAM_ListSessions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IsAuthenticatedStream",
+			Handler:       _PAM_IsAuthenticatedStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "authd.proto",
 }
 
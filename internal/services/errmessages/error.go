@@ -1,5 +1,15 @@
 package errmessages
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
 // ToDisplayError defines an error that needs to be sent unaltered to the client.
 type ToDisplayError struct {
 	error
@@ -9,3 +19,98 @@ type ToDisplayError struct {
 func NewToDisplayError(err error) error {
 	return ToDisplayError{err}
 }
+
+// NewToDisplayErrorf returns a new ErrorToDisplay formatted as fmt.Errorf does, whose message is translated by the
+// client in the language of the user.
+//
+// The arguments are sent to the client as strings, so only the %s, %q, %v and %w verbs must be used in format.
+func NewToDisplayErrorf(format string, args ...any) error {
+	strArgs := make([]string, 0, len(args))
+	for _, a := range args {
+		strArgs = append(strArgs, fmt.Sprint(a))
+	}
+	return ToDisplayError{localizedError{
+		error: fmt.Errorf(format, args...),
+		msgID: strings.ReplaceAll(format, "%w", "%v"),
+		args:  strArgs,
+	}}
+}
+
+// Unwrap returns the error to display.
+func (e ToDisplayError) Unwrap() error {
+	return e.error
+}
+
+// localizedError is an error whose message can be translated by the client.
+type localizedError struct {
+	error
+	msgID string
+	args  []string
+}
+
+// Unwrap returns the formatted error.
+func (e localizedError) Unwrap() error {
+	return e.error
+}
+
+// WithCode returns an error which is sent to the client with the gRPC code, while keeping err untouched.
+func WithCode(code codes.Code, err error) error {
+	return codeError{error: err, code: code}
+}
+
+// codeError is an error sent to the client with a gRPC code.
+type codeError struct {
+	error
+	code codes.Code
+}
+
+// Unwrap returns the original error.
+func (e codeError) Unwrap() error {
+	return e.error
+}
+
+// GRPCStatus returns the gRPC status of the error.
+func (e codeError) GRPCStatus() *status.Status {
+	return status.New(e.code, e.Error())
+}
+
+// WithErrorCode returns an error which is reported to the client with the authd error code, while keeping err
+// untouched.
+func WithErrorCode(code authd.ErrorCode, err error) error {
+	return errorCodeError{error: err, code: code}
+}
+
+// ErrorCode returns the authd error code of err, as set by WithErrorCode or as attached by the daemon to the gRPC
+// status of err. It returns ERROR_UNSPECIFIED if err has no error code.
+func ErrorCode(err error) authd.ErrorCode {
+	if err == nil {
+		return authd.ErrorCode_ERROR_UNSPECIFIED
+	}
+
+	var withErrorCode errorCodeError
+	if errors.As(err, &withErrorCode) {
+		return withErrorCode.code
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return authd.ErrorCode_ERROR_UNSPECIFIED
+	}
+	for _, d := range st.Details() {
+		if details, ok := d.(*authd.ErrorDetails); ok {
+			return details.GetCode()
+		}
+	}
+	return authd.ErrorCode_ERROR_UNSPECIFIED
+}
+
+// errorCodeError is an error reported to the client with an authd error code.
+type errorCodeError struct {
+	error
+	code authd.ErrorCode
+}
+
+// Unwrap returns the original error.
+func (e errorCodeError) Unwrap() error {
+	return e.error
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/proto/authd"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,16 +20,69 @@ func TestRedactErrorInterceptor(t *testing.T) {
 	tests := map[string]struct {
 		inputError error
 
-		wantMessage string
+		wantCode      codes.Code
+		wantMessage   string
+		wantMessageID string
+		wantArgs      []string
+		wantErrorCode authd.ErrorCode
 	}{
 		"Trim_input_down_to_ErrToDisplay": {
 			inputError:  fmt.Errorf("Error to be redacted: %w", ToDisplayError{errors.New("Error to be shown")}),
 			wantMessage: "Error to be shown",
 		},
-		"Return_original_error": {
-			inputError:  errors.New("Not a redacted error"),
+		"Keep_gRPC_status_of_ErrToDisplay": {
+			inputError:  fmt.Errorf("Error to be redacted: %w", ToDisplayError{status.Error(codes.ResourceExhausted, "Error to be shown")}),
+			wantCode:    codes.ResourceExhausted,
+			wantMessage: "Error to be shown",
+		},
+		"Return_original_error_as_internal_error": {
+			inputError:    errors.New("Not a redacted error"),
+			wantCode:      codes.Unknown,
+			wantMessage:   "Not a redacted error",
+			wantErrorCode: authd.ErrorCode_ERROR_INTERNAL,
+		},
+		"Return_original_gRPC_error": {
+			inputError:  status.Error(codes.InvalidArgument, "Not a redacted error"),
+			wantCode:    codes.InvalidArgument,
 			wantMessage: "Not a redacted error",
 		},
+		"Return_original_cancellation_error": {
+			inputError:  fmt.Errorf("Not a redacted error: %w", context.Canceled),
+			wantMessage: "Not a redacted error: context canceled",
+		},
+		"Attach_error_code_of_original_error": {
+			inputError:    fmt.Errorf("Not a redacted error: %w", WithErrorCode(authd.ErrorCode_ERROR_USER_CONFLICT, errors.New("user exists"))),
+			wantCode:      codes.Unknown,
+			wantMessage:   "Not a redacted error: user exists",
+			wantErrorCode: authd.ErrorCode_ERROR_USER_CONFLICT,
+		},
+		"Attach_error_code_of_ErrToDisplay": {
+			inputError:    fmt.Errorf("Error to be redacted: %w", WithErrorCode(authd.ErrorCode_ERROR_BROKER_UNAVAILABLE, ToDisplayError{errors.New("Error to be shown")})),
+			wantCode:      codes.Unknown,
+			wantMessage:   "Error to be shown",
+			wantErrorCode: authd.ErrorCode_ERROR_BROKER_UNAVAILABLE,
+		},
+		"Attach_message_ID_of_localized_ErrToDisplay": {
+			inputError:    fmt.Errorf("Error to be redacted: %w", NewToDisplayErrorf("user %q is not allowed to use %s", "user1", "broker1")),
+			wantCode:      codes.Unknown,
+			wantMessage:   `user "user1" is not allowed to use broker1`,
+			wantMessageID: "user %q is not allowed to use %s",
+			wantArgs:      []string{"user1", "broker1"},
+		},
+		"Attach_message_ID_of_localized_ErrToDisplay_wrapping_an_error": {
+			inputError:    NewToDisplayErrorf("couldn't connect to broker: %w", errors.New("broker1 not found")),
+			wantCode:      codes.Unknown,
+			wantMessage:   "couldn't connect to broker: broker1 not found",
+			wantMessageID: "couldn't connect to broker: %v",
+			wantArgs:      []string{"broker1 not found"},
+		},
+		"Keep_gRPC_code_of_error_with_code": {
+			inputError:    WithCode(codes.ResourceExhausted, fmt.Errorf("%w: %w", errors.New("Limit reached"), NewToDisplayErrorf("%q has too many sessions", "user1"))),
+			wantCode:      codes.ResourceExhausted,
+			wantMessage:   `"user1" has too many sessions`,
+			wantMessageID: "%q has too many sessions",
+			wantArgs:      []string{"user1"},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -35,7 +90,35 @@ func TestRedactErrorInterceptor(t *testing.T) {
 
 			_, err := RedactErrorInterceptor(context.TODO(), testRequest{tc.inputError}, nil, testHandler)
 			require.Error(t, err, "RedactErrorInterceptor should return an error")
+			require.Equal(t, tc.wantErrorCode, ErrorCode(err), "RedactErrorInterceptor attached unexpected error code")
+			formatted := FormatErrorMessage(context.TODO(), "", testRequest{err}, nil, nil, testInvoker)
+			require.Equal(t, tc.wantErrorCode, ErrorCode(formatted), "FormatErrorMessage should keep the error code")
+			if tc.wantMessageID != "" {
+				var localized *authd.LocalizedError
+				for _, d := range status.Convert(err).Details() {
+					if l, ok := d.(*authd.LocalizedError); ok {
+						localized = l
+					}
+				}
+				require.NotNil(t, localized, "RedactErrorInterceptor should attach the localized error")
+				require.Equal(t, tc.wantMessageID, localized.GetMessageId(), "RedactErrorInterceptor attached unexpected message ID")
+				require.Equal(t, tc.wantArgs, localized.GetArgs(), "RedactErrorInterceptor attached unexpected arguments")
+
+				require.Equal(t, tc.wantMessage, formatted.Error(), "FormatErrorMessage should render the localized error")
+			}
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err), "RedactErrorInterceptor returned unexpected error code")
+				err = errors.New(status.Convert(err).Message())
+			}
 			require.Equal(t, tc.wantMessage, err.Error(), "RedactErrorInterceptor returned unexpected error message")
+
+			err = RedactErrorStreamInterceptor(testRequest{tc.inputError}, nil, nil, testStreamHandler)
+			require.Error(t, err, "RedactErrorStreamInterceptor should return an error")
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err), "RedactErrorStreamInterceptor returned unexpected error code")
+				err = errors.New(status.Convert(err).Message())
+			}
+			require.Equal(t, tc.wantMessage, err.Error(), "RedactErrorStreamInterceptor returned unexpected error message")
 		})
 	}
 }
@@ -69,6 +152,10 @@ func TestFormatErrorMessage(t *testing.T) {
 			inputError:  status.Error(codes.DeadlineExceeded, "DeadlineExceeded error"),
 			wantMessage: "service took too long to respond. Disconnecting client",
 		},
+		"Parse_code_ResourceExhausted": {
+			inputError:  status.Error(codes.ResourceExhausted, "ResourceExhausted error"),
+			wantMessage: "ResourceExhausted error",
+		},
 		"Parse_code_Unknown": {
 			inputError:  status.Error(codes.Unknown, "Unknown error"),
 			wantMessage: "Unknown error",
@@ -81,10 +168,43 @@ func TestFormatErrorMessage(t *testing.T) {
 			err := FormatErrorMessage(context.TODO(), "", testRequest{tc.inputError}, nil, nil, testInvoker)
 			require.Error(t, err, "FormatErrorMessage should return an error")
 			require.Equal(t, tc.wantMessage, err.Error(), "FormatErrorMessage returned unexpected error message")
+
+			_, err = FormatErrorMessageStream(context.TODO(), nil, nil, "", testStreamer(tc.inputError, nil))
+			require.Error(t, err, "FormatErrorMessageStream should return an error when opening the stream")
+			require.Equal(t, tc.wantMessage, err.Error(), "FormatErrorMessageStream returned unexpected error message")
+
+			cs, err := FormatErrorMessageStream(context.TODO(), nil, nil, "", testStreamer(nil, tc.inputError))
+			require.NoError(t, err, "FormatErrorMessageStream should not return an error when opening the stream")
+			err = cs.RecvMsg(nil)
+			require.Error(t, err, "RecvMsg should return an error")
+			require.Equal(t, tc.wantMessage, err.Error(), "RecvMsg returned unexpected error message")
 		})
 	}
 }
 
+func TestFormatErrorMessageStreamKeepsEndOfStream(t *testing.T) {
+	t.Parallel()
+
+	cs, err := FormatErrorMessageStream(context.TODO(), nil, nil, "", testStreamer(nil, io.EOF))
+	require.NoError(t, err, "FormatErrorMessageStream should not return an error when opening the stream")
+	require.ErrorIs(t, cs.RecvMsg(nil), io.EOF, "RecvMsg should return the end of stream as is")
+}
+
+func TestFormatErrorMessageIn(t *testing.T) {
+	t.Parallel()
+
+	_, err := RedactErrorInterceptor(context.TODO(), testRequest{NewToDisplayErrorf("user %q is not allowed to use %s", "user1", "broker1")}, nil, testHandler)
+	require.Error(t, err, "Setup: RedactErrorInterceptor should return an error")
+	want := `l'utilisateur "user1" n'est pas autorisé à utiliser broker1`
+
+	formatted := FormatErrorMessageIn("fr_FR.UTF-8")(context.TODO(), "", testRequest{err}, nil, nil, testInvoker)
+	require.Equal(t, want, formatted.Error(), "FormatErrorMessageIn should translate the error in the requested language")
+
+	cs, err := FormatErrorMessageStreamIn("fr_FR.UTF-8")(context.TODO(), nil, nil, "", testStreamer(nil, err))
+	require.NoError(t, err, "FormatErrorMessageStreamIn should not return an error when opening the stream")
+	require.Equal(t, want, cs.RecvMsg(nil).Error(), "RecvMsg should translate the error in the requested language")
+}
+
 type testRequest struct {
 	err error
 }
@@ -94,6 +214,30 @@ func testHandler(ctx context.Context, req any) (any, error) {
 	return nil, req.(testRequest).err
 }
 
+func testStreamHandler(srv any, _ grpc.ServerStream) error {
+	//nolint:forcetypeassert // This is only used in the tests and we know the type.
+	return srv.(testRequest).err
+}
+
+// testClientStream is a client stream whose messages can't be received.
+type testClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s testClientStream) RecvMsg(any) error {
+	return s.err
+}
+
+func testStreamer(openErr, recvErr error) grpc.Streamer {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if openErr != nil {
+			return nil, openErr
+		}
+		return testClientStream{err: recvErr}, nil
+	}
+}
+
 func testInvoker(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
 	//nolint:forcetypeassert // This is only used in the tests and we know the type.
 	return req.(testRequest).err
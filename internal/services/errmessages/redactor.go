@@ -5,34 +5,146 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
 )
 
 // RedactErrorInterceptor redacts some of the attached errors before sending it to the client.
 //
-// It unwraps the error up to the first ErrToDisplay and sends it to the client. If none is found, it sends the original error.
+// It unwraps the error up to the first ErrToDisplay and sends the error it holds to the client, keeping its gRPC status
+// if any. If none is found, it sends the original error.
 func RedactErrorInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	m, err := handler(ctx, req)
-	if err != nil {
-		log.Warning(context.TODO(), err.Error())
-		var redactedError ToDisplayError
-		if !errors.As(err, &redactedError) {
-			return m, err
+	return m, redactError(err)
+}
+
+// RedactErrorStreamInterceptor is the [RedactErrorInterceptor] of the streaming calls.
+func RedactErrorStreamInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return redactError(handler(srv, ss))
+}
+
+// redactError returns the error held by the first ErrToDisplay of err, or err if there is none.
+//
+// The gRPC code set with WithCode is kept, and the message ID and arguments of the localized errors are attached to
+// the status, so that the client can translate the message. The authd error code of err is attached to the status too,
+// the errors which are neither to display nor gRPC errors being internal errors.
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	log.Warning(context.TODO(), err.Error())
+	errorCode := ErrorCode(err)
+
+	var redactedError ToDisplayError
+	if !errors.As(err, &redactedError) {
+		if errorCode == authd.ErrorCode_ERROR_UNSPECIFIED && isInternalError(err) {
+			errorCode = authd.ErrorCode_ERROR_INTERNAL
+		}
+		if errorCode == authd.ErrorCode_ERROR_UNSPECIFIED {
+			return err
 		}
-		return m, redactedError
+		return withDetails(status.Convert(err), &authd.ErrorDetails{Code: errorCode}).Err()
+	}
+	displayed := redactedError.Unwrap()
+
+	var withCode codeError
+	hasCode := errors.As(err, &withCode)
+	var localized localizedError
+	isLocalized := errors.As(displayed, &localized)
+	if !hasCode && !isLocalized && errorCode == authd.ErrorCode_ERROR_UNSPECIFIED {
+		return displayed
+	}
+
+	st := status.Convert(displayed)
+	if hasCode {
+		st = status.New(withCode.code, st.Message())
+	}
+	var details []protoadapt.MessageV1
+	if isLocalized {
+		details = append(details, &authd.LocalizedError{MessageId: localized.msgID, Args: localized.args})
+	}
+	if errorCode != authd.ErrorCode_ERROR_UNSPECIFIED {
+		details = append(details, &authd.ErrorDetails{Code: errorCode})
+	}
+	return withDetails(st, details...).Err()
+}
+
+// isInternalError returns true if err is an error of the daemon which is not a gRPC error nor a cancellation.
+func isInternalError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return status.Code(err) == codes.Unknown
+}
+
+// withDetails returns st with the details attached, or st as is if they can't be attached.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		log.Warningf(context.TODO(), "Can't attach details to error: %v", err)
+		return st
 	}
-	return m, nil
+	return withDetails
 }
 
 // FormatErrorMessage formats the error message received by the client to avoid printing useless information.
 //
-// It converts the gRPC error to a more human-readable error with a better message.
+// It converts the gRPC error to a more human-readable error with a better message, in the language of the process.
 func FormatErrorMessage(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-	err := invoker(ctx, method, req, reply, cc, opts...)
+	return FormatErrorMessageIn(i18n.Lang())(ctx, method, req, reply, cc, invoker, opts...)
+}
+
+// FormatErrorMessageStream is the [FormatErrorMessage] of the streaming calls, formatting the errors returned when
+// opening the stream and when receiving its messages.
+func FormatErrorMessageStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return FormatErrorMessageStreamIn(i18n.Lang())(ctx, desc, cc, method, streamer, opts...)
+}
+
+// FormatErrorMessageIn returns the [FormatErrorMessage] interceptor translating the messages in lang, which is the
+// language of the user rather than of the process for the PAM module.
+func FormatErrorMessageIn(lang string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return formatError(invoker(ctx, method, req, reply, cc, opts...), lang)
+	}
+}
+
+// FormatErrorMessageStreamIn is the [FormatErrorMessageIn] of the streaming calls.
+func FormatErrorMessageStreamIn(lang string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, formatError(err, lang)
+		}
+		return formattedErrorClientStream{ClientStream: cs, lang: lang}, nil
+	}
+}
+
+// formattedErrorClientStream is a client stream formatting the errors of the received messages.
+type formattedErrorClientStream struct {
+	grpc.ClientStream
+	lang string
+}
+
+// RecvMsg receives a message from the stream, formatting the error if any. The end of the stream is not an error.
+func (s formattedErrorClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if errors.Is(err, io.EOF) {
+		return err
+	}
+	return formatError(err, s.lang)
+}
+
+// formatError converts the gRPC error to a more human-readable error with a better message. The authd error code
+// attached to the gRPC status is kept, so that it can be read with ErrorCode. The messages are translated in lang.
+func formatError(err error, lang string) error {
 	if err == nil {
 		return nil
 	}
@@ -40,23 +152,44 @@ func FormatErrorMessage(ctx context.Context, method string, req, reply any, cc *
 	if !grpcErr {
 		return err
 	}
+	errorCode := ErrorCode(err)
 
 	switch st.Code() {
 	// no daemon
 	case codes.Unavailable:
-		err = fmt.Errorf("couldn't connect to authd daemon: %v", st.Message())
+		err = errors.New(i18n.Sprintf(lang, "couldn't connect to authd daemon: %v", st.Message()))
 	// timeout
 	case codes.DeadlineExceeded:
-		err = errors.New("service took too long to respond. Disconnecting client")
-	// regular error without annotation
-	case codes.Unknown:
-		err = errors.New(st.Message())
+		err = errors.New(i18n.Translate(lang, "service took too long to respond. Disconnecting client"))
+	// regular error without annotation, or a limit that was reached and whose message is for the user
+	case codes.Unknown, codes.ResourceExhausted:
+		err = errors.New(localizedMessage(st, lang))
 	// likely means that IsAuthenticated got cancelled, so we need to keep the error intact
 	case codes.Canceled:
 		break
 	// grpc error, just format it
 	default:
-		err = fmt.Errorf("error %s from server: %v", st.Code(), st.Message())
+		err = fmt.Errorf("error %s from server: %v", st.Code(), localizedMessage(st, lang))
+	}
+
+	if errorCode != authd.ErrorCode_ERROR_UNSPECIFIED {
+		return WithErrorCode(errorCode, err)
 	}
 	return err
 }
+
+// localizedMessage returns the message of the status, translated in lang if the server attached its message ID.
+func localizedMessage(st *status.Status, lang string) string {
+	for _, d := range st.Details() {
+		localized, ok := d.(*authd.LocalizedError)
+		if !ok {
+			continue
+		}
+		args := make([]any, 0, len(localized.GetArgs()))
+		for _, a := range localized.GetArgs() {
+			args = append(args, a)
+		}
+		return i18n.Sprintf(lang, localized.GetMessageId(), args...)
+	}
+	return st.Message()
+}
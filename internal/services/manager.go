@@ -10,6 +10,7 @@ import (
 	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/services/pam"
 	"github.com/ubuntu/authd/internal/services/permissions"
+	"github.com/ubuntu/authd/internal/services/refresh"
 	"github.com/ubuntu/authd/internal/services/user"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/log"
@@ -23,23 +24,47 @@ import (
 type Manager struct {
 	userManager   *users.Manager
 	brokerManager *brokers.Manager
+	refresher     *refresh.Refresher
 	pamService    pam.Service
 	userService   user.Service
 }
 
 // NewManager returns a new manager after creating all necessary items for our business logic.
-func NewManager(ctx context.Context, dbDir, brokersConfPath string, configuredBrokers []string, usersConfig users.Config) (m Manager, err error) {
+func NewManager(ctx context.Context, dbDir, brokersConfPath string, configuredBrokers []string, brokersConfig brokers.Config, usersConfig users.Config, refreshConfig refresh.Config) (m Manager, err error) {
 	log.Debug(ctx, "Building authd object")
 
-	brokerManager, err := brokers.NewManager(ctx, brokersConfPath, configuredBrokers)
+	brokerManager, err := brokers.NewManager(ctx, brokersConfPath, configuredBrokers, brokersConfig)
 	if err != nil {
 		return m, err
 	}
+	// The broker manager runs background tasks, which must not outlive a manager that failed to build.
+	defer func() {
+		if err != nil {
+			brokerManager.Stop()
+		}
+	}()
 
 	userManager, err := users.NewManager(usersConfig, dbDir)
 	if err != nil {
 		return m, err
 	}
+	defer func() {
+		if err != nil {
+			if stopErr := userManager.Stop(); stopErr != nil {
+				log.Warningf(ctx, "Could not close the database: %v", stopErr)
+			}
+		}
+	}()
+
+	// Migrate the users assigned to a broker whose ID has changed.
+	if err := userManager.RenameBrokerIDs(brokerManager.BrokerIDAliases()); err != nil {
+		log.Warningf(ctx, "Could not migrate the previous broker IDs: %v", err)
+	}
+
+	refresher, err := refresh.New(ctx, userManager, brokerManager, refreshConfig)
+	if err != nil {
+		return m, err
+	}
 
 	permissionManager := permissions.New()
 
@@ -49,26 +74,61 @@ func NewManager(ctx context.Context, dbDir, brokersConfPath string, configuredBr
 	return Manager{
 		userManager:   userManager,
 		brokerManager: brokerManager,
+		refresher:     refresher,
 		userService:   userService,
 		pamService:    pamService,
 	}, nil
 }
 
+// BrokerHealthServiceName returns the name under which the health service reports whether the broker is reachable.
+func BrokerHealthServiceName(brokerID string) string {
+	return consts.ServiceName + ".broker." + brokerID
+}
+
+// SessionsHealthServiceName returns the name under which the health service reports whether the broker can accept new
+// authentication sessions.
+func SessionsHealthServiceName(brokerID string) string {
+	return consts.ServiceName + ".sessions." + brokerID
+}
+
 // RegisterGRPCServices returns a new grpc Server after registering both NSS and PAM services.
 func (m Manager) RegisterGRPCServices(ctx context.Context) *grpc.Server {
 	log.Debug(ctx, "Registering gRPC services")
 
-	opts := []grpc.ServerOption{permissions.WithUnixPeerCreds(), grpc.ChainUnaryInterceptor(m.globalPermissions, errmessages.RedactErrorInterceptor)}
+	opts := []grpc.ServerOption{
+		permissions.WithUnixPeerCreds(),
+		grpc.ChainUnaryInterceptor(m.globalPermissions, errmessages.RedactErrorInterceptor),
+		grpc.ChainStreamInterceptor(m.globalStreamPermissions, errmessages.RedactErrorStreamInterceptor),
+	}
 	grpcServer := grpc.NewServer(opts...)
 
 	healthCheck := health.NewServer()
 	healthgrpc.RegisterHealthServer(grpcServer, healthCheck)
 
-	// We may provide status per each service, but for now we only care about the global state.
-	// Also, we're serving by default because all the brokers have been initialized at this
-	// point, so no need to start in NOT_SERVING mode and then update it accordingly.
+	// We're serving by default because all the brokers have been initialized at this point, so no need to start in
+	// NOT_SERVING mode and then update it accordingly.
 	defer healthCheck.SetServingStatus(consts.ServiceName, healthpb.HealthCheckResponse_SERVING)
 
+	// Each broker is reported as not serving while it doesn't reply to pings.
+	m.brokerManager.SetBrokerHealthHandler(func(brokerID string, healthy bool) {
+		status := healthpb.HealthCheckResponse_SERVING
+		if !healthy {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		healthCheck.SetServingStatus(BrokerHealthServiceName(brokerID), status)
+	})
+
+	// Each broker with a session limit is reported as not serving while the limit is reached.
+	if perBroker, _ := m.brokerManager.SessionLimits(); perBroker > 0 {
+		m.brokerManager.SetSessionLimitsHandler(func(brokerID string, limitReached bool) {
+			status := healthpb.HealthCheckResponse_SERVING
+			if limitReached {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+			healthCheck.SetServingStatus(SessionsHealthServiceName(brokerID), status)
+		})
+	}
+
 	authd.RegisterUserServiceServer(grpcServer, m.userService)
 	authd.RegisterPAMServer(grpcServer, m.pamService)
 
@@ -79,5 +139,6 @@ func (m Manager) RegisterGRPCServices(ctx context.Context) *grpc.Server {
 func (m *Manager) stop() error {
 	log.Debug(context.TODO(), "Closing gRPC manager and database")
 
+	m.refresher.Stop()
 	return m.userManager.Stop()
 }
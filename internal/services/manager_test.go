@@ -11,14 +11,19 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/internal/services"
 	"github.com/ubuntu/authd/internal/services/errmessages"
+	"github.com/ubuntu/authd/internal/services/refresh"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 	"github.com/ubuntu/authd/internal/users"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestNewManager(t *testing.T) {
@@ -43,7 +48,7 @@ func TestNewManager(t *testing.T) {
 				t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", tc.systemBusSocket)
 			}
 
-			m, err := services.NewManager(context.Background(), tc.dbDir, t.TempDir(), nil, users.DefaultConfig)
+			m, err := services.NewManager(context.Background(), tc.dbDir, t.TempDir(), nil, brokers.DefaultConfig, users.DefaultConfig, refresh.DefaultConfig)
 			if tc.wantErr {
 				require.Error(t, err, "NewManager should have returned an error, but did not")
 				return
@@ -58,7 +63,7 @@ func TestNewManager(t *testing.T) {
 func TestRegisterGRPCServices(t *testing.T) {
 	t.Parallel()
 
-	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, users.DefaultConfig)
+	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, brokers.DefaultConfig, users.DefaultConfig, refresh.DefaultConfig)
 	require.NoError(t, err, "Setup: could not create manager for the test")
 	defer require.NoError(t, m.Stop(), "Teardown: Stop should not have returned an error, but did")
 
@@ -75,7 +80,7 @@ func TestRegisterGRPCServices(t *testing.T) {
 func TestAccessAuthorization(t *testing.T) {
 	t.Parallel()
 
-	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, users.DefaultConfig)
+	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, brokers.DefaultConfig, users.DefaultConfig, refresh.DefaultConfig)
 	require.NoError(t, err, "Setup: could not create manager for the test")
 	defer require.NoError(t, m.Stop(), "Teardown: Stop should not have returned an error, but did")
 
@@ -102,7 +107,7 @@ func TestAccessAuthorization(t *testing.T) {
 
 	// Global authorization for PAM is always denied for non root user.
 	pamClient := authd.NewPAMClient(conn)
-	_, err = pamClient.AvailableBrokers(context.Background(), &authd.Empty{})
+	_, err = pamClient.AvailableBrokers(context.Background(), &authd.ABRequest{})
 	require.Error(t, err, "PAM calls are not allowed to any random user")
 
 	// Global authorization for the user service is always granted for non root user.
@@ -114,6 +119,68 @@ func TestAccessAuthorization(t *testing.T) {
 	require.NoError(t, err, "Teardown: could not close the client connection")
 }
 
+func TestHealthStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		service              string
+		maxSessionsPerBroker int
+
+		wantStatus healthpb.HealthCheckResponse_ServingStatus
+		wantErr    bool
+	}{
+		"Global_status_is_serving":                  {service: consts.ServiceName, wantStatus: healthpb.HealthCheckResponse_SERVING},
+		"Reachable_broker_is_serving":               {service: services.BrokerHealthServiceName(brokers.LocalBrokerName), wantStatus: healthpb.HealthCheckResponse_SERVING},
+		"Broker_below_its_session_limit_is_serving": {maxSessionsPerBroker: 1, wantStatus: healthpb.HealthCheckResponse_SERVING},
+
+		"Error_when_broker_does_not_exist":    {service: services.BrokerHealthServiceName("does-not-exist"), wantErr: true},
+		"Error_when_sessions_are_not_limited": {wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersConfig := brokers.Config{MaxSessionsPerBroker: tc.maxSessionsPerBroker}
+			m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, brokersConfig, users.DefaultConfig, refresh.DefaultConfig)
+			require.NoError(t, err, "Setup: could not create manager for the test")
+			defer require.NoError(t, m.Stop(), "Teardown: Stop should not have returned an error, but did")
+
+			grpcServer := m.RegisterGRPCServices(context.Background())
+
+			// socket path is limited in length.
+			tmpDir, err := os.MkdirTemp("", "authd-socket-dir")
+			require.NoError(t, err, "Setup: could not setup temporary socket dir path")
+			defer os.RemoveAll(tmpDir)
+			socketPath := filepath.Join(tmpDir, "authd.sock")
+			lis, err := net.Listen("unix", socketPath)
+			require.NoError(t, err, "Setup: could not create unix socket")
+			defer lis.Close()
+
+			serverDone := make(chan (error))
+			go func() { serverDone <- grpcServer.Serve(lis) }()
+			defer func() {
+				grpcServer.Stop()
+				require.NoError(t, <-serverDone, "gRPC server should not return an error from serving")
+			}()
+
+			conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			require.NoError(t, err, "Setup: could not dial the server")
+			defer conn.Close()
+
+			if tc.service == "" {
+				tc.service = services.SessionsHealthServiceName(brokers.LocalBrokerName)
+			}
+			resp, err := healthgrpc.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: tc.service})
+			if tc.wantErr {
+				require.Error(t, err, "Check should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Check should not return an error, but did")
+			require.Equal(t, tc.wantStatus, resp.GetStatus(), "Check should return the expected status")
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	// Start system bus mock.
 	cleanup, err := testutils.StartSystemBusMock()
@@ -7,12 +7,16 @@ import (
 	"errors"
 	"fmt"
 	"os/user"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/services/permissions"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/internal/users/types"
@@ -45,11 +49,11 @@ func NewService(ctx context.Context, userManager *users.Manager, brokerManager *
 	}
 }
 
-// AvailableBrokers returns the list of all brokers with their details.
-func (s Service) AvailableBrokers(ctx context.Context, _ *authd.Empty) (*authd.ABResponse, error) {
+// AvailableBrokers returns the list of all brokers allowed for the requested user and service with their details.
+func (s Service) AvailableBrokers(ctx context.Context, req *authd.ABRequest) (*authd.ABResponse, error) {
 	var r authd.ABResponse
 
-	for _, b := range s.brokerManager.AvailableBrokers() {
+	for _, b := range s.brokerManager.AllowedBrokers(req.GetUsername(), req.GetService()) {
 		r.BrokersInfos = append(r.BrokersInfos, &authd.ABResponse_BrokerInfo{
 			Id:        b.ID,
 			Name:      b.Name,
@@ -60,9 +64,51 @@ func (s Service) AvailableBrokers(ctx context.Context, _ *authd.Empty) (*authd.A
 	return &r, nil
 }
 
+// GetBrokerIcon returns the brand icon of the broker, loaded by the daemon so that clients don't need to access the
+// broker configuration.
+func (s Service) GetBrokerIcon(ctx context.Context, req *authd.GBIRequest) (resp *authd.GBIResponse, err error) {
+	defer decorate.OnError(&err, "can't get icon of broker %q", req.GetBrokerId())
+
+	if req.GetBrokerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no broker ID given")
+	}
+
+	icon, err := s.brokerManager.BrandIcon(req.GetBrokerId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if icon == nil {
+		return nil, status.Error(codes.NotFound, "broker has no valid brand icon")
+	}
+
+	return &authd.GBIResponse{
+		Data:        icon.Data,
+		ContentType: icon.ContentType,
+		Hash:        icon.Hash,
+	}, nil
+}
+
 // GetPreviousBroker returns the previous broker set for a given user, if any.
 // If the user is not in our cache/database, it will try to check if it’s on the system, and return then "local".
+// Mandatory routing rules take precedence over the previous broker, while the other ones are only used when there
+// is no previous broker.
 func (s Service) GetPreviousBroker(ctx context.Context, req *authd.GPBRequest) (*authd.GPBResponse, error) {
+	routedBroker, mandatory := s.brokerManager.RoutedBroker(req.GetUsername())
+	if mandatory && routedBroker == nil {
+		// The user will be denied by any broker.
+		log.Noticef(ctx, "User %q is routed to a broker which is not available", req.GetUsername())
+		return &authd.GPBResponse{}, nil
+	}
+	if mandatory {
+		log.Debugf(ctx, "User %q is routed to broker %q", req.GetUsername(), routedBroker.Name)
+		return &authd.GPBResponse{PreviousBroker: routedBroker.ID}, nil
+	}
+	// noPreviousBroker is the response when there is no previous broker for the user.
+	noPreviousBroker := &authd.GPBResponse{}
+	if routedBroker != nil {
+		noPreviousBroker.PreviousBroker = routedBroker.ID
+	}
+
 	// Use in memory cache first
 	if b := s.brokerManager.BrokerForUser(req.GetUsername()); b != nil {
 		return &authd.GPBResponse{PreviousBroker: b.ID}, nil
@@ -83,7 +129,10 @@ func (s Service) GetPreviousBroker(ctx context.Context, req *authd.GPBRequest) (
 		// User not accessible through NSS, first time login or no valid user. Anyway, no broker selected.
 		if _, err := user.Lookup(req.GetUsername()); err != nil {
 			log.Debugf(ctx, "User %q is unknown", req.GetUsername())
-			return &authd.GPBResponse{}, nil
+			if s.brokerManager.UniformResponses() {
+				return s.decoyPreviousBroker(req.GetUsername(), noPreviousBroker), nil
+			}
+			return noPreviousBroker, nil
 		}
 
 		// We could resolve the user through NSS, which means then that another non authd service
@@ -97,12 +146,12 @@ func (s Service) GetPreviousBroker(ctx context.Context, req *authd.GPBRequest) (
 	// No error but the brokerID is empty (broker in database but default broker not stored yet due no successful login)
 	if brokerID == "" {
 		log.Infof(ctx, "No assigned broker for user %q from database", req.GetUsername())
-		return &authd.GPBResponse{}, nil
+		return noPreviousBroker, nil
 	}
 
 	if !s.brokerManager.BrokerExists(brokerID) {
 		log.Warningf(ctx, "Last used broker %q is not available for user %q, letting the user select a new one", brokerID, req.GetUsername())
-		return &authd.GPBResponse{}, nil
+		return noPreviousBroker, nil
 	}
 
 	// Database the broker which should be used for the user, so that we don't have to query the database again next time -
@@ -121,10 +170,35 @@ func (s Service) GetPreviousBroker(ctx context.Context, req *authd.GPBRequest) (
 	}, nil
 }
 
+// decoyPreviousBroker returns a previous broker for a user which is not in the database, so that the response can't be
+// told apart from the one for a user who already logged in: the routed broker if any, or else the first broker which
+// allows the user.
+func (s Service) decoyPreviousBroker(username string, noPreviousBroker *authd.GPBResponse) *authd.GPBResponse {
+	if noPreviousBroker.GetPreviousBroker() != "" {
+		return noPreviousBroker
+	}
+	for _, b := range s.brokerManager.AllowedBrokers(username, "") {
+		if b.ID != brokers.LocalBrokerName {
+			return &authd.GPBResponse{PreviousBroker: b.ID}
+		}
+	}
+	return noPreviousBroker
+}
+
+// isUnknownUser returns whether the user is neither in the database nor known by any broker.
+func (s Service) isUnknownUser(ctx context.Context, username string) bool {
+	if _, err := s.userManager.UserByName(username); !errors.Is(err, users.NoDataFoundError{}) {
+		return false
+	}
+	return !s.brokerManager.KnowsUser(ctx, username)
+}
+
 // SelectBroker starts a new session and selects the requested broker for the user.
 func (s Service) SelectBroker(ctx context.Context, req *authd.SBRequest) (resp *authd.SBResponse, err error) {
 	defer decorate.OnError(&err, "can't start authentication transaction")
 
+	start := time.Now()
+
 	username := req.GetUsername()
 	brokerID := req.GetBrokerId()
 	lang := req.GetLang()
@@ -152,15 +226,54 @@ func (s Service) SelectBroker(ctx context.Context, req *authd.SBRequest) (resp *
 		return nil, status.Error(codes.InvalidArgument, "invalid session mode")
 	}
 
+	pamContext, err := s.pamContextFromRequest(ctx, req.GetPamContext())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	peer, err := s.permissionManager.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a session and Memorize selected broker for it.
-	sessionID, encryptionKey, err := s.brokerManager.NewSession(brokerID, username, lang, mode)
+	// The session can be started on a fallback broker.
+	// With uniform responses, the unknown users get a decoy session, which looks like a real one but always denies
+	// access. Both kinds of sessions take as long to start, counting from the start of the request.
+	var broker *brokers.Broker
+	var sessionID, encryptionKey string
+	if s.brokerManager.UniformResponses() && brokerID != brokers.LocalBrokerName && s.isUnknownUser(ctx, username) {
+		log.Debugf(ctx, "User %q is unknown, starting a decoy session", username)
+		broker, sessionID, encryptionKey, err = s.brokerManager.NewDecoySession(brokerID, username, lang, mode, pamContext, peer)
+	} else {
+		broker, sessionID, encryptionKey, err = s.brokerManager.NewSession(brokerID, username, lang, mode, pamContext, peer)
+	}
+	if errors.Is(err, brokers.ErrTooManySessions) {
+		return nil, errmessages.WithCode(codes.ResourceExhausted, err)
+	}
 	if err != nil {
 		return nil, err
 	}
+	s.brokerManager.WaitSessionStart(ctx, brokerID, start)
+
+	algorithm := encryption.AlgorithmOf(encryptionKey)
+	supportedAlgorithms := req.GetSupportedEncryptionAlgorithms()
+	if len(supportedAlgorithms) == 0 {
+		// Clients which don't report their algorithms only support RSA-OAEP.
+		supportedAlgorithms = []authd.EncryptionAlgorithm{authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP}
+	}
+	if !slices.Contains(supportedAlgorithms, algorithm) {
+		if err := s.brokerManager.EndSession(sessionID); err != nil {
+			log.Warningf(ctx, "Could not end session %q: %v", sessionID, err)
+		}
+		return nil, errmessages.NewToDisplayErrorf("%s requires an encryption algorithm which is not supported by the client", broker.Name)
+	}
 
 	return &authd.SBResponse{
-		SessionId:     sessionID,
-		EncryptionKey: encryptionKey,
+		SessionId:           sessionID,
+		EncryptionKey:       encryptionKey,
+		BrokerId:            broker.ID,
+		EncryptionAlgorithm: algorithm,
 	}, err
 }
 
@@ -173,7 +286,7 @@ func (s Service) GetAuthenticationModes(ctx context.Context, req *authd.GAMReque
 		return nil, status.Error(codes.InvalidArgument, "no session ID provided")
 	}
 
-	broker, err := s.brokerManager.BrokerFromSessionID(sessionID)
+	broker, err := s.sessionBroker(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +332,7 @@ func (s Service) SelectAuthenticationMode(ctx context.Context, req *authd.SAMReq
 		return nil, status.Error(codes.InvalidArgument, "no authentication mode provided")
 	}
 
-	broker, err := s.brokerManager.BrokerFromSessionID(sessionID)
+	broker, err := s.sessionBroker(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -236,6 +349,34 @@ func (s Service) SelectAuthenticationMode(ctx context.Context, req *authd.SAMReq
 
 // IsAuthenticated returns broker answer to authentication request.
 func (s Service) IsAuthenticated(ctx context.Context, req *authd.IARequest) (resp *authd.IAResponse, err error) {
+	return s.isAuthenticated(ctx, req, nil)
+}
+
+// IsAuthenticatedStream streams the progress messages of the broker for the authentication request, followed by its
+// answer.
+func (s Service) IsAuthenticatedStream(req *authd.IARequest, stream authd.PAM_IsAuthenticatedStreamServer) error {
+	ctx := stream.Context()
+
+	resp, err := s.isAuthenticated(ctx, req, func(message string) {
+		err := stream.Send(&authd.IAStreamResponse{
+			Response: &authd.IAStreamResponse_Progress{Progress: message},
+		})
+		if err != nil {
+			log.Warningf(ctx, "%s: Could not send authentication progress: %v", req.GetSessionId(), err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&authd.IAStreamResponse{
+		Response: &authd.IAStreamResponse_Result{Result: resp},
+	})
+}
+
+// isAuthenticated returns broker answer to authentication request, calling onProgress with the progress messages of
+// the broker, if any, until it does.
+func (s Service) isAuthenticated(ctx context.Context, req *authd.IARequest, onProgress func(message string)) (resp *authd.IAResponse, err error) {
 	defer decorate.OnError(&err, "can't check authentication")
 
 	sessionID := req.GetSessionId()
@@ -243,17 +384,19 @@ func (s Service) IsAuthenticated(ctx context.Context, req *authd.IARequest) (res
 		return nil, status.Error(codes.InvalidArgument, "no session ID provided")
 	}
 
-	broker, err := s.brokerManager.BrokerFromSessionID(sessionID)
+	broker, err := s.sessionBroker(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
+	// The broker may take a while to answer, ensure the session is not reaped in the meantime.
+	defer s.brokerManager.KeepSessionAlive(sessionID)()
 
 	authenticationDataJSON, err := protojson.Marshal(req.GetAuthenticationData())
 	if err != nil {
 		return nil, err
 	}
 
-	access, data, err := broker.IsAuthenticated(ctx, sessionID, string(authenticationDataJSON))
+	access, data, err := broker.IsAuthenticatedWithProgress(ctx, sessionID, string(authenticationDataJSON), onProgress)
 	if err != nil {
 		return nil, err
 	}
@@ -272,9 +415,23 @@ func (s Service) IsAuthenticated(ctx context.Context, req *authd.IARequest) (res
 		return nil, fmt.Errorf("user data from broker invalid: %v", err)
 	}
 
+	// Users removed by their broker stay locked, even if the broker can still authenticate them with offline
+	// credentials, until the broker knows them again.
+	locked, err := s.userManager.IsUserLocked(uInfo.Name)
+	if err != nil && !errors.Is(err, users.NoDataFoundError{}) {
+		return nil, err
+	}
+	if locked {
+		log.Noticef(ctx, "%s: Denying access to locked user %q", sessionID, uInfo.Name)
+		return &authd.IAResponse{
+			Access: auth.Denied,
+			Msg:    `{"message": "This user was removed by its provider"}`,
+		}, nil
+	}
+
 	// Update database and local groups on granted auth.
 	if err := s.userManager.UpdateUser(uInfo); err != nil {
-		return nil, err
+		return nil, withUserErrorCode(err)
 	}
 
 	return &authd.IAResponse{
@@ -308,6 +465,179 @@ func (s Service) SetDefaultBrokerForUser(ctx context.Context, req *authd.SDBFURe
 	return &authd.Empty{}, nil
 }
 
+// CheckAccount asks the broker of the user whether their account can still be used. Users that are not handled by a
+// broker, or whose broker does not check accounts, are not checked.
+func (s Service) CheckAccount(ctx context.Context, req *authd.CARequest) (resp *authd.CAResponse, err error) {
+	defer decorate.OnError(&err, "can't check account of user %q", req.GetUsername())
+
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no user name given")
+	}
+
+	notChecked := &authd.CAResponse{Status: authd.AccountStatus_ACCOUNT_NOT_CHECKED}
+
+	brokerID, err := s.userBrokerID(ctx, req.GetUsername(), "checking account")
+	if err != nil {
+		return nil, err
+	}
+	if brokerID == "" {
+		return notChecked, nil
+	}
+
+	accountStatus, msg, err := s.brokerManager.CheckAccount(ctx, brokerID, req.GetUsername())
+	if errors.Is(err, brokers.ErrNotSupported) {
+		log.Debugf(ctx, "Not checking account of user %q: %v", req.GetUsername(), err)
+		return notChecked, nil
+	}
+	if brokers.IsBrokerUnavailable(err) {
+		log.Warningf(ctx, "Can't check account of user %q: %v", req.GetUsername(), err)
+		return &authd.CAResponse{Status: authd.AccountStatus_ACCOUNT_BROKER_UNAVAILABLE}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var st authd.AccountStatus
+	switch accountStatus {
+	case auth.AccountValid:
+		st = authd.AccountStatus_ACCOUNT_VALID
+	case auth.AccountExpired:
+		st = authd.AccountStatus_ACCOUNT_EXPIRED
+	case auth.AccountPasswordChangeRequired:
+		st = authd.AccountStatus_ACCOUNT_PASSWORD_CHANGE_REQUIRED
+	case auth.AccountDenied:
+		st = authd.AccountStatus_ACCOUNT_DENIED
+	}
+	log.Debugf(ctx, "Account of user %q is %s", req.GetUsername(), accountStatus)
+
+	return &authd.CAResponse{Status: st, Msg: msg}, nil
+}
+
+// OpenUserSession notifies the broker of the user that a session is opened, returning the environment variables to set
+// in the session.
+func (s Service) OpenUserSession(ctx context.Context, req *authd.USRequest) (resp *authd.USResponse, err error) {
+	defer decorate.OnError(&err, "can't open session of user %q", req.GetUsername())
+
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no user name given")
+	}
+
+	pamContext, err := s.pamContextFromRequest(ctx, req.GetPamContext())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	brokerID, err := s.userBrokerID(ctx, req.GetUsername(), "opening session")
+	if err != nil {
+		return nil, err
+	}
+	if brokerID == "" {
+		return &authd.USResponse{}, nil
+	}
+
+	env, err := s.brokerManager.OpenUserSession(ctx, brokerID, req.GetUsername(), pamContext)
+	return userSessionResponse(ctx, req.GetUsername(), "opening session", env, err)
+}
+
+// CloseUserSession notifies the broker of the user that a session is closed.
+func (s Service) CloseUserSession(ctx context.Context, req *authd.USRequest) (resp *authd.USResponse, err error) {
+	defer decorate.OnError(&err, "can't close session of user %q", req.GetUsername())
+
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no user name given")
+	}
+
+	pamContext, err := s.pamContextFromRequest(ctx, req.GetPamContext())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	brokerID, err := s.userBrokerID(ctx, req.GetUsername(), "closing session")
+	if err != nil {
+		return nil, err
+	}
+	if brokerID == "" {
+		return &authd.USResponse{}, nil
+	}
+
+	err = s.brokerManager.CloseUserSession(ctx, brokerID, req.GetUsername(), pamContext)
+	return userSessionResponse(ctx, req.GetUsername(), "closing session", nil, err)
+}
+
+// SetUserCredentials asks the broker of the user to establish, delete, reinitialize or refresh its credentials,
+// returning the environment variables to set in the session.
+func (s Service) SetUserCredentials(ctx context.Context, req *authd.SUCRequest) (resp *authd.USResponse, err error) {
+	defer decorate.OnError(&err, "can't set credentials of user %q", req.GetUsername())
+
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no user name given")
+	}
+
+	var action string
+	switch req.GetAction() {
+	case authd.CredentialsAction_CREDENTIALS_ESTABLISH:
+		action = auth.CredentialsEstablish
+	case authd.CredentialsAction_CREDENTIALS_DELETE:
+		action = auth.CredentialsDelete
+	case authd.CredentialsAction_CREDENTIALS_REINITIALIZE:
+		action = auth.CredentialsReinitialize
+	case authd.CredentialsAction_CREDENTIALS_REFRESH:
+		action = auth.CredentialsRefresh
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credentials action %v", req.GetAction())
+	}
+
+	brokerID, err := s.userBrokerID(ctx, req.GetUsername(), "setting credentials")
+	if err != nil {
+		return nil, err
+	}
+	if brokerID == "" {
+		return &authd.USResponse{}, nil
+	}
+
+	env, err := s.brokerManager.SetUserCredentials(ctx, brokerID, req.GetUsername(), action)
+	return userSessionResponse(ctx, req.GetUsername(), "setting credentials", env, err)
+}
+
+// userBrokerID returns the ID of the broker the user is assigned to, or an empty ID if the user is not handled by an
+// available broker. action describes the request in the logs.
+func (s Service) userBrokerID(ctx context.Context, username, action string) (brokerID string, err error) {
+	brokerID, err = s.userManager.BrokerForUser(username)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		log.Debugf(ctx, "Not %s of user %q: the user is not handled by authd", action, username)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if brokerID == "" || brokerID == brokers.LocalBrokerName {
+		log.Debugf(ctx, "Not %s of user %q: the user has no broker", action, username)
+		return "", nil
+	}
+	if !s.brokerManager.BrokerExists(brokerID) {
+		log.Warningf(ctx, "Not %s of user %q: broker %q is not available", action, username, brokerID)
+		return "", nil
+	}
+	return brokerID, nil
+}
+
+// userSessionResponse returns the response to a session or credentials request, which is not handled if the broker
+// does not support it or can't be reached.
+func userSessionResponse(ctx context.Context, username, action string, env map[string]string, err error) (*authd.USResponse, error) {
+	if errors.Is(err, brokers.ErrNotSupported) {
+		log.Debugf(ctx, "Not %s of user %q: %v", action, username, err)
+		return &authd.USResponse{}, nil
+	}
+	if brokers.IsBrokerUnavailable(err) {
+		log.Warningf(ctx, "Not %s of user %q: %v", action, username, err)
+		return &authd.USResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &authd.USResponse{Handled: true, Env: env}, nil
+}
+
 // EndSession asks the broker associated with the sessionID to end the session.
 func (s Service) EndSession(ctx context.Context, req *authd.ESRequest) (empty *authd.Empty, err error) {
 	defer decorate.OnError(&err, "could not abort session")
@@ -317,12 +647,66 @@ func (s Service) EndSession(ctx context.Context, req *authd.ESRequest) (empty *a
 		return nil, status.Error(codes.InvalidArgument, "no session id given")
 	}
 
+	if _, err := s.sessionBroker(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
 	return &authd.Empty{}, s.brokerManager.EndSession(sessionID)
 }
 
+// sessionBroker returns the broker of the session, ensuring that the session is used by the process that started it.
+func (s Service) sessionBroker(ctx context.Context, sessionID string) (*brokers.Broker, error) {
+	peer, err := s.permissionManager.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	broker, err := s.brokerManager.SessionBroker(sessionID, peer)
+	if errors.Is(err, brokers.ErrNotSessionOwner) {
+		return nil, errmessages.WithCode(codes.PermissionDenied, err)
+	}
+	return broker, err
+}
+
+// ListSessions returns the sessions that are currently ongoing.
+func (s Service) ListSessions(ctx context.Context, _ *authd.Empty) (*authd.LSResponse, error) {
+	var sessions []*authd.LSResponse_SessionInfo
+	for _, session := range s.brokerManager.Sessions() {
+		sessions = append(sessions, &authd.LSResponse_SessionInfo{
+			SessionId:    session.ID,
+			BrokerId:     session.BrokerID,
+			Username:     session.Username,
+			Mode:         session.Mode,
+			StartTime:    session.StartTime.Unix(),
+			LastActivity: session.LastActivity.Unix(),
+			PamContext: &authd.PAMContext{
+				Service: session.PAMContext.Service,
+				Tty:     session.PAMContext.TTY,
+				Rhost:   session.PAMContext.RHost,
+				Ruser:   session.PAMContext.RUser,
+			},
+		})
+	}
+
+	return &authd.LSResponse{Sessions: sessions}, nil
+}
+
+// withUserErrorCode attaches to err the error code matching the error of the user manager, if any.
+func withUserErrorCode(err error) error {
+	switch {
+	case errors.Is(err, users.ConflictError{}):
+		return errmessages.WithErrorCode(authd.ErrorCode_ERROR_USER_CONFLICT, err)
+	case errors.Is(err, users.LockedError{}):
+		return errmessages.WithErrorCode(authd.ErrorCode_ERROR_LOCKED, err)
+	default:
+		return err
+	}
+}
+
 func uiLayoutToMap(layout *authd.UILayout) (mapLayout map[string]string, err error) {
 	if layout.GetType() == "" {
-		return nil, fmt.Errorf("invalid layout option: type is required, got: %v", layout)
+		return nil, errmessages.WithErrorCode(authd.ErrorCode_ERROR_INVALID_LAYOUT,
+			fmt.Errorf("invalid layout option: type is required, got: %v", layout))
 	}
 	r := map[string]string{layouts.Type: layout.GetType()}
 	if l := layout.GetLabel(); l != "" {
@@ -343,6 +727,24 @@ func uiLayoutToMap(layout *authd.UILayout) (mapLayout map[string]string, err err
 	if c := layout.GetCode(); c != "" {
 		r[layouts.Code] = c
 	}
+	if c := layout.GetChallenge(); c != "" {
+		r[layouts.Challenge] = c
+	}
+	if rp := layout.GetRpId(); rp != "" {
+		r[layouts.RPID] = rp
+	}
+	if c := layout.GetCredentials(); c != "" {
+		r[layouts.Credentials] = c
+	}
+
+	// The choices are provided by the broker, the client only needs to support the layout.
+	if layout.GetType() == layouts.Choice {
+		r[layouts.Choices] = layouts.Required
+	}
+	// The fields of a multi-field form are provided by the broker, if the client can render them.
+	if layout.GetType() == layouts.Form && layout.GetSupportsFields() {
+		r[layouts.Fields] = layouts.Optional
+	}
 
 	if layout.GetType() != layouts.QrCode {
 		return r, nil
@@ -366,6 +768,23 @@ func mapToUILayout(layout map[string]string) (r *authd.UILayout) {
 	wait := layout[layouts.Wait]
 	content := layout[layouts.Content]
 	code := layout[layouts.Code]
+	challenge := layout[layouts.Challenge]
+	rpID := layout[layouts.RPID]
+	credentials := layout[layouts.Credentials]
+
+	// The choices were validated along with the layout.
+	var choices []*authd.UILayout_Choice
+	items, _ := layouts.ParseChoices(layout[layouts.Choices])
+	for _, item := range items {
+		choices = append(choices, &authd.UILayout_Choice{Id: item.ID, Label: item.Label})
+	}
+
+	// The fields were validated along with the layout too.
+	var fields []*authd.UILayout_Field
+	fieldItems, _ := layouts.ParseFields(layout[layouts.Fields])
+	for _, item := range fieldItems {
+		fields = append(fields, &authd.UILayout_Field{Id: item.ID, Label: item.Label, Entry: item.Entry})
+	}
 
 	// We don't return whether the qrcode rendering is enabled back to the
 	// client on purpose, since it's something it mandates.
@@ -378,5 +797,11 @@ func mapToUILayout(layout map[string]string) (r *authd.UILayout) {
 		Wait:    &wait,
 		Content: &content,
 		Code:    &code,
+		Choices: choices,
+		Fields:  fields,
+
+		Challenge:   &challenge,
+		RpId:        &rpID,
+		Credentials: &credentials,
 	}
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/services/pam"
@@ -36,6 +37,7 @@ import (
 
 var (
 	globalBrokerManager   *brokers.Manager
+	globalBrokersConfPath string
 	mockBrokerGeneratedID string
 )
 
@@ -44,8 +46,10 @@ var (
 	requiredEntries = layouts.RequiredItems("entry_type", "other_entry_type")
 	optionalEntries = layouts.OptionalItems("entry_type", "other_entry_type")
 	optional        = layouts.Optional
+	required        = layouts.Required
 
-	rendersQrCode = true
+	rendersQrCode  = true
+	supportsFields = true
 
 	requiredEntry = &authd.UILayout{
 		Type:          "required-entry",
@@ -65,6 +69,29 @@ var (
 		Type:  "",
 		Entry: &requiredEntries,
 	}
+	choice = &authd.UILayout{
+		Type:  layouts.Choice,
+		Label: &optional,
+	}
+	formEntries    = layouts.OptionalItems(entries.Chars, entries.CharsPassword)
+	formWithFields = &authd.UILayout{
+		Type:           layouts.Form,
+		Label:          &optional,
+		Entry:          &formEntries,
+		SupportsFields: &supportsFields,
+	}
+	webauthn = &authd.UILayout{
+		Type:        layouts.WebAuthn,
+		Label:       &optional,
+		Challenge:   &required,
+		RpId:        &required,
+		Credentials: &optional,
+	}
+	formWithoutFields = &authd.UILayout{
+		Type:  layouts.Form,
+		Label: &optional,
+		Entry: &formEntries,
+	}
 )
 
 func TestNewService(t *testing.T) {
@@ -76,7 +103,7 @@ func TestNewService(t *testing.T) {
 	pm := permissions.New()
 	service := pam.NewService(context.Background(), m, globalBrokerManager, &pm)
 
-	brokers, err := service.AvailableBrokers(context.Background(), &authd.Empty{})
+	brokers, err := service.AvailableBrokers(context.Background(), &authd.ABRequest{})
 	require.NoError(t, err, "can’t create the service directly")
 	require.NotEmpty(t, brokers.BrokersInfos, "Service is created and can query the broker manager")
 }
@@ -100,7 +127,7 @@ func TestAvailableBrokers(t *testing.T) {
 			pm := newPermissionManager(t, tc.currentUserNotRoot)
 			client := newPamClient(t, nil, globalBrokerManager, &pm)
 
-			abResp, err := client.AvailableBrokers(context.Background(), &authd.Empty{})
+			abResp, err := client.AvailableBrokers(context.Background(), &authd.ABRequest{})
 
 			if tc.wantErr {
 				require.Error(t, err, "AvailableBrokers should return an error, but did not")
@@ -117,6 +144,45 @@ func TestAvailableBrokers(t *testing.T) {
 	}
 }
 
+func TestGetBrokerIcon(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		brokerID           string
+		currentUserNotRoot bool
+
+		wantErr bool
+	}{
+		"Successfully_get_broker_icon": {brokerID: mockBrokerGeneratedID},
+
+		"Error_when_broker_has_no_icon":    {brokerID: brokers.LocalBrokerName, wantErr: true},
+		"Error_when_broker_does_not_exist": {brokerID: "does-not-exist", wantErr: true},
+		"Error_when_broker_ID_is_empty":    {wantErr: true},
+		"Error_when_not_root":              {brokerID: mockBrokerGeneratedID, currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
+
+			resp, err := client.GetBrokerIcon(context.Background(), &authd.GBIRequest{BrokerId: tc.brokerID})
+			if tc.wantErr {
+				require.Error(t, err, "GetBrokerIcon should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "GetBrokerIcon should not return an error, but did")
+
+			golden.CheckOrUpdateYAML(t, map[string]string{
+				"data":         string(resp.GetData()),
+				"content_type": resp.GetContentType(),
+				"hash":         resp.GetHash(),
+			})
+		})
+	}
+}
+
 func TestGetPreviousBroker(t *testing.T) {
 	t.Parallel()
 
@@ -126,7 +192,9 @@ func TestGetPreviousBroker(t *testing.T) {
 	currentUsername := u.Username
 
 	tests := map[string]struct {
-		user string
+		user             string
+		routes           []brokers.Route
+		uniformResponses bool
 
 		currentUserNotRoot bool
 		onlyLocalBroker    bool
@@ -142,6 +210,20 @@ func TestGetPreviousBroker(t *testing.T) {
 		"Returns_empty_when_user_does_not_have_a_broker": {user: "userwithoutbroker", wantBroker: ""},
 		"Returns_empty_when_broker_is_not_available":     {user: "userwithinactivebroker", wantBroker: ""},
 
+		"For_unknown_user,_get_routed_broker":                  {user: "nonexistent@example.com", routes: []brokers.Route{{Domain: "example.com"}}, wantBroker: mockBrokerGeneratedID},
+		"For_user_without_broker,_get_routed_broker":           {user: "userwithoutbroker", routes: []brokers.Route{{Username: "userwithout.*"}}, wantBroker: mockBrokerGeneratedID},
+		"For_user_with_inactive_broker,_get_routed_broker":     {user: "userwithinactivebroker", routes: []brokers.Route{{Username: ".*"}}, wantBroker: mockBrokerGeneratedID},
+		"For_local_user,_get_broker_from_mandatory_route":      {user: currentUsername, routes: []brokers.Route{{Username: ".*", Mandatory: true}}, wantBroker: mockBrokerGeneratedID},
+		"For_local_user,_ignore_non_mandatory_route":           {user: currentUsername, routes: []brokers.Route{{Username: ".*"}}, wantBroker: brokers.LocalBrokerName},
+		"Returns_empty_when_user_does_not_match_routing_rules": {user: "nonexistent@example.org", routes: []brokers.Route{{Domain: "example.com"}}, wantBroker: ""},
+		"Returns_empty_when_mandatory_route_broker_is_not_available": {
+			user: "userwithbroker", routes: []brokers.Route{{Username: ".*", Broker: "not-available", Mandatory: true}}, wantBroker: "",
+		},
+
+		"For_unknown_user_with_uniform_responses,_get_first_broker":     {user: "nonexistent", uniformResponses: true, wantBroker: mockBrokerGeneratedID},
+		"For_user_without_broker_with_uniform_responses,_returns_empty": {user: "userwithoutbroker", uniformResponses: true, wantBroker: ""},
+		"For_local_user_with_uniform_responses,_get_local_broker":       {user: currentUsername, uniformResponses: true, wantBroker: brokers.LocalBrokerName},
+
 		"Error_when_not_root": {user: "userwithbroker", currentUserNotRoot: true, wantErr: true},
 	}
 	for name, tc := range tests {
@@ -167,9 +249,19 @@ func TestGetPreviousBroker(t *testing.T) {
 
 			brokerManager := globalBrokerManager
 			if tc.onlyLocalBroker {
-				brokerManager, err = brokers.NewManager(context.Background(), "", nil)
+				brokerManager, err = brokers.NewManager(context.Background(), "", nil, brokers.DefaultConfig)
 				require.NoError(t, err, "Setup: could not create broker manager with only local broker")
 			}
+			if tc.routes != nil || tc.uniformResponses {
+				for i := range tc.routes {
+					if tc.routes[i].Broker == "" {
+						tc.routes[i].Broker = mockBrokerGeneratedID
+					}
+				}
+				config := brokers.Config{Routes: tc.routes, UniformResponses: tc.uniformResponses}
+				brokerManager, err = brokers.NewManager(context.Background(), globalBrokersConfPath, nil, config)
+				require.NoError(t, err, "Setup: could not create broker manager with routes")
+			}
 			client := newPamClient(t, m, brokerManager, &pm)
 
 			// Get existing entry
@@ -193,22 +285,49 @@ func TestSelectBroker(t *testing.T) {
 		brokerID    string
 		username    string
 		sessionMode string
+		pamContext  *authd.PAMContext
 
-		currentUserNotRoot bool
+		supportedEncryptionAlgorithms []authd.EncryptionAlgorithm
+		currentUserNotRoot            bool
 
-		wantErr bool
+		wantEncryptionAlgorithm authd.EncryptionAlgorithm
+		wantErr                 bool
 	}{
 		"Successfully_select_a_broker_and_creates_auth_session":   {username: "success", sessionMode: auth.SessionModeLogin},
 		"Successfully_select_a_broker_and_creates_passwd_session": {username: "success", sessionMode: auth.SessionModeChangePassword},
-
-		"Error_when_not_root":                             {username: "success", currentUserNotRoot: true, wantErr: true},
-		"Error_when_username_is_empty":                    {wantErr: true},
-		"Error_when_mode_is_empty":                        {sessionMode: "-", wantErr: true},
-		"Error_when_mode_does_not_exist":                  {sessionMode: "does not exist", wantErr: true},
-		"Error_when_brokerID_is_empty":                    {username: "empty broker", brokerID: "-", wantErr: true},
-		"Error_when_broker_does_not_exist":                {username: "no broker", brokerID: "does not exist", wantErr: true},
-		"Error_when_broker_does_not_provide_a_session_ID": {username: "ns_no_id", wantErr: true},
-		"Error_when_starting_the_session":                 {username: "ns_error", wantErr: true},
+		"Successfully_select_a_broker_and_forward_the_PAM_context": {
+			username:   "ns_context",
+			pamContext: &authd.PAMContext{Service: "sshd", Tty: "ssh", Rhost: "192.0.2.1", Ruser: "remote"},
+		},
+		"Successfully_select_a_broker_with_a_display_as_tty": {username: "ns_context", pamContext: &authd.PAMContext{Service: "gdm-password", Tty: ":0"}},
+		"Successfully_select_a_broker_using_RSA_encryption_with_a_client_supporting_X25519": {
+			username: "success",
+			supportedEncryptionAlgorithms: []authd.EncryptionAlgorithm{
+				authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+				authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			},
+		},
+		"Successfully_select_a_broker_using_X25519_encryption": {
+			username: "ns_x25519",
+			supportedEncryptionAlgorithms: []authd.EncryptionAlgorithm{
+				authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+				authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			},
+			wantEncryptionAlgorithm: authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+		},
+
+		"Error_when_not_root":                                             {username: "success", currentUserNotRoot: true, wantErr: true},
+		"Error_when_username_is_empty":                                    {wantErr: true},
+		"Error_when_mode_is_empty":                                        {sessionMode: "-", wantErr: true},
+		"Error_when_mode_does_not_exist":                                  {sessionMode: "does not exist", wantErr: true},
+		"Error_when_brokerID_is_empty":                                    {username: "empty broker", brokerID: "-", wantErr: true},
+		"Error_when_broker_does_not_exist":                                {username: "no broker", brokerID: "does not exist", wantErr: true},
+		"Error_when_broker_does_not_provide_a_session_ID":                 {username: "ns_no_id", wantErr: true},
+		"Error_when_starting_the_session":                                 {username: "ns_error", wantErr: true},
+		"Error_when_PAM_context_has_control_characters":                   {username: "success", pamContext: &authd.PAMContext{Rhost: "host\nfake log line"}, wantErr: true},
+		"Error_when_PAM_context_field_is_too_long":                        {username: "success", pamContext: &authd.PAMContext{Ruser: strings.Repeat("a", 257)}, wantErr: true},
+		"Error_when_PAM_context_tty_is_not_a_terminal":                    {username: "success", pamContext: &authd.PAMContext{Tty: "/dev/does-not-exist"}, wantErr: true},
+		"Error_when_client_does_not_support_the_encryption_of_the_broker": {username: "ns_x25519", wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -239,9 +358,12 @@ func TestSelectBroker(t *testing.T) {
 			}
 
 			sbRequest := &authd.SBRequest{
-				BrokerId: tc.brokerID,
-				Username: tc.username,
-				Mode:     sessionMode,
+				BrokerId:   tc.brokerID,
+				Username:   tc.username,
+				Mode:       sessionMode,
+				PamContext: tc.pamContext,
+
+				SupportedEncryptionAlgorithms: tc.supportedEncryptionAlgorithms,
 			}
 			sbResp, err := client.SelectBroker(context.Background(), sbRequest)
 			if tc.wantErr {
@@ -249,15 +371,124 @@ func TestSelectBroker(t *testing.T) {
 				return
 			}
 			require.NoError(t, err, "SelectBroker should not return an error, but did")
-
-			got := fmt.Sprintf("ID: %s\nEncryption Key: %s\n",
-				strings.ReplaceAll(sbResp.GetSessionId(), tc.brokerID, "BROKER_ID"),
-				sbResp.GetEncryptionKey())
+			require.Equal(t, tc.brokerID, sbResp.GetBrokerId(), "SelectBroker should return the broker the session was started on")
+			require.Equal(t, tc.wantEncryptionAlgorithm, sbResp.GetEncryptionAlgorithm(),
+				"SelectBroker should return the encryption algorithm of the broker")
+
+			brokerSessionID := brokers.BrokerSessionID(tc.brokerID, sbResp.GetSessionId())
+			require.NotEqual(t, tc.brokerID+"-"+brokerSessionID, sbResp.GetSessionId(),
+				"SelectBroker should add a random token to the session ID")
+			got := fmt.Sprintf("ID: BROKER_ID-%s\nEncryption Key: %s\n", brokerSessionID, sbResp.GetEncryptionKey())
 			golden.CheckOrUpdate(t, got)
 		})
 	}
 }
 
+func TestSelectBrokerWithTooManySessions(t *testing.T) {
+	t.Parallel()
+
+	brokerManager, err := brokers.NewManager(context.Background(), globalBrokersConfPath, nil, brokers.Config{MaxSessionsPerUser: 1})
+	require.NoError(t, err, "Setup: could not create broker manager")
+	pm := newPermissionManager(t, false)
+	client := newPamClient(t, nil, brokerManager, &pm)
+
+	sbRequest := &authd.SBRequest{
+		BrokerId: mockBrokerGeneratedID,
+		Username: t.Name() + testutils.IDSeparator + "success",
+		Mode:     authd.SessionMode_LOGIN,
+	}
+	_, err = client.SelectBroker(context.Background(), sbRequest)
+	require.NoError(t, err, "First SelectBroker should not return an error, but did")
+
+	_, err = client.SelectBroker(context.Background(), sbRequest)
+	// The client formats the ResourceExhausted status to only keep the message for the user.
+	require.ErrorContains(t, err, "has too many ongoing sessions, try again later", "Second SelectBroker should tell the user why the session was refused")
+}
+
+func TestSelectBrokerWithUniformResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username           string
+		noUniformResponses bool
+
+		wantDecoy bool
+	}{
+		"Unknown_user_gets_a_decoy_session":                          {username: "unknown-user", wantDecoy: true},
+		"User_known_by_broker_gets_a_real_session":                   {username: "user-pre-check"},
+		"Unknown_user_gets_a_real_session_without_uniform_responses": {username: "unknown-user", noUniformResponses: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config := brokers.Config{UniformResponses: !tc.noUniformResponses}
+			brokerManager, err := brokers.NewManager(context.Background(), globalBrokersConfPath, nil, config)
+			require.NoError(t, err, "Setup: could not create broker manager")
+			pm := newPermissionManager(t, false)
+			client := newPamClient(t, nil, brokerManager, &pm)
+
+			sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+				BrokerId: mockBrokerGeneratedID,
+				Username: tc.username,
+				Mode:     authd.SessionMode_LOGIN,
+			})
+			require.NoError(t, err, "SelectBroker should not return an error, but did")
+			require.Equal(t, mockBrokerGeneratedID, sbResp.GetBrokerId(), "SelectBroker should return the requested broker")
+			require.NotEmpty(t, sbResp.GetEncryptionKey(), "SelectBroker should return an encryption key")
+
+			brokerSessionID := brokers.BrokerSessionID(mockBrokerGeneratedID, sbResp.GetSessionId())
+			if !tc.wantDecoy {
+				require.Equal(t, testutils.GenerateSessionID(tc.username), brokerSessionID, "Session should be started on the broker")
+				return
+			}
+			require.NotEqual(t, testutils.GenerateSessionID(tc.username), brokerSessionID, "Session should not be started on the broker")
+
+			gamResp, err := client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{
+				SessionId:          sbResp.GetSessionId(),
+				SupportedUiLayouts: []*authd.UILayout{formWithoutFields},
+			})
+			require.NoError(t, err, "GetAuthenticationModes should not return an error, but did")
+			require.Len(t, gamResp.GetAuthenticationModes(), 1, "Decoy session should offer a single authentication mode")
+
+			iaResp, err := client.IsAuthenticated(context.Background(), &authd.IARequest{
+				SessionId:          sbResp.GetSessionId(),
+				AuthenticationData: &authd.IARequest_AuthenticationData{},
+			})
+			require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+			require.Equal(t, auth.Retry, iaResp.GetAccess(), "Decoy session should ask to retry")
+		})
+	}
+}
+
+func TestSessionIsBoundToItsOwner(t *testing.T) {
+	t.Parallel()
+
+	pm := newPermissionManager(t, false)
+	client := newPamClient(t, nil, globalBrokerManager, &pm)
+
+	// The session is started by another process than the client.
+	// The client only gets the message of the PermissionDenied error.
+	username := t.Name() + testutils.IDSeparator + "success"
+	_, sessionID, _, err := globalBrokerManager.NewSession(mockBrokerGeneratedID, username, "C", auth.SessionModeLogin,
+		brokers.PAMContext{}, permissions.Peer{UID: 0, PID: 1, StartTime: 1})
+	require.NoError(t, err, "Setup: could not start session")
+
+	_, err = client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{SessionId: sessionID})
+	require.ErrorContains(t, err, brokers.ErrNotSessionOwner.Error(), "GetAuthenticationModes should reject another process")
+	_, err = client.SelectAuthenticationMode(context.Background(), &authd.SAMRequest{SessionId: sessionID, AuthenticationModeId: "password"})
+	require.ErrorContains(t, err, brokers.ErrNotSessionOwner.Error(), "SelectAuthenticationMode should reject another process")
+	_, err = client.IsAuthenticated(context.Background(), &authd.IARequest{SessionId: sessionID})
+	require.ErrorContains(t, err, brokers.ErrNotSessionOwner.Error(), "IsAuthenticated should reject another process")
+	_, err = client.EndSession(context.Background(), &authd.ESRequest{SessionId: sessionID})
+	require.ErrorContains(t, err, brokers.ErrNotSessionOwner.Error(), "EndSession should reject another process")
+
+	// The session is still usable by its owner.
+	_, err = globalBrokerManager.BrokerFromSessionID(sessionID)
+	require.NoError(t, err, "Session should not have been ended by another process")
+	require.NoError(t, globalBrokerManager.EndSession(sessionID), "Teardown: could not end session")
+}
+
 func TestGetAuthenticationModes(t *testing.T) {
 	t.Parallel()
 
@@ -338,6 +569,9 @@ func TestSelectAuthenticationMode(t *testing.T) {
 	}{
 		"Successfully_select_mode_with_required_value":         {username: "sam_success_required_entry", supportedUILayouts: []*authd.UILayout{requiredEntry}},
 		"Successfully_select_mode_with_missing_optional_value": {username: "sam_missing_optional_entry", supportedUILayouts: []*authd.UILayout{optionalEntry}},
+		"Successfully_select_mode_with_choices":                {username: "sam_success_choice", supportedUILayouts: []*authd.UILayout{choice}},
+		"Successfully_select_mode_with_fields":                 {username: "sam_success_fields", supportedUILayouts: []*authd.UILayout{formWithFields}},
+		"Successfully_select_mode_with_webauthn":               {username: "sam_success_webauthn", supportedUILayouts: []*authd.UILayout{webauthn}},
 
 		// service errors
 		"Error_when_not_root":                {username: "sam_success_required_entry", currentUserNotRoot: true, wantErr: true},
@@ -350,10 +584,12 @@ func TestSelectAuthenticationMode(t *testing.T) {
 		"Error_when_broker_does_not_have_validators_for_the_session": {username: "does not matter", noValidators: true, wantErr: true},
 
 		/* Layout errors */
-		"Error_when_returns_no_layout":                     {username: "sam_no_layout", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
-		"Error_when_returns_layout_with_no_type":           {username: "sam_no_layout_type", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
-		"Error_when_returns_layout_without_required_value": {username: "sam_missing_required_entry", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
-		"Error_when_returns_layout_with_unknown_field":     {username: "sam_unknown_field", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
+		"Error_when_returns_no_layout":                          {username: "sam_no_layout", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
+		"Error_when_returns_layout_with_no_type":                {username: "sam_no_layout_type", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
+		"Error_when_returns_layout_without_required_value":      {username: "sam_missing_required_entry", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
+		"Error_when_returns_layout_with_unknown_field":          {username: "sam_unknown_field", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
+		"Error_when_returns_choice_layout_without_choices":      {username: "sam_missing_choices", supportedUILayouts: []*authd.UILayout{choice}, wantErr: true},
+		"Error_when_returns_fields_the_client_does_not_support": {username: "sam_success_fields", supportedUILayouts: []*authd.UILayout{formWithoutFields}, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -429,6 +665,7 @@ func TestIsAuthenticated(t *testing.T) {
 		"Denies_authentication_when_broker_times_out":         {username: "ia_timeout"},
 		"Update_existing_DB_on_success":                       {username: "success", existingDB: "cache-with-user.db"},
 		"Update_local_groups":                                 {username: "success_with_local_groups", localGroupsFile: "valid.group"},
+		"Denies_authentication_of_locked_user":                {username: "success", existingDB: "cache-with-locked-user.db"},
 
 		// service errors
 		"Error_when_not_root":           {username: "success", currentUserNotRoot: true},
@@ -544,6 +781,74 @@ func TestIsAuthenticated(t *testing.T) {
 	}
 }
 
+func TestIsAuthenticatedStream(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		sessionID string
+
+		username           string
+		currentUserNotRoot bool
+
+		// There is no wantErr as it's stored in the golden file.
+	}{
+		"Successfully_authenticate":                        {username: "success"},
+		"Successfully_authenticate_with_progress_messages": {username: "ia_progress"},
+		"Denies_authentication_when_broker_times_out":      {username: "ia_timeout"},
+		"Error_when_not_root":                              {username: "success", currentUserNotRoot: true},
+		"Error_when_sessionID_is_empty":                    {sessionID: "-"},
+		"Error_when_authenticating":                        {username: "ia_error"},
+		"Error_when_broker_returns_invalid_access":         {username: "ia_invalid_access"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := users.NewManager(users.DefaultConfig, t.TempDir())
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			switch tc.sessionID {
+			case "-":
+				tc.sessionID = ""
+			default:
+				tc.sessionID = startSession(t, client, tc.username)
+			}
+
+			// Now, set tests permissions for this use case
+			permissions.Z_ForTests_SetCurrentUserAsRoot(&pm, !tc.currentUserNotRoot)
+
+			stream, err := client.IsAuthenticatedStream(context.Background(), &authd.IARequest{
+				SessionId:          tc.sessionID,
+				AuthenticationData: &authd.IARequest_AuthenticationData{},
+			})
+			require.NoError(t, err, "Setup: could not start the authentication stream")
+
+			var got string
+			for {
+				resp, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					got += fmt.Sprintf("err: %v\n", err)
+					break
+				}
+				if progress, ok := resp.GetResponse().(*authd.IAStreamResponse_Progress); ok {
+					got += fmt.Sprintf("progress: %s\n", progress.Progress)
+					continue
+				}
+				got += fmt.Sprintf("access: %s\nmsg: %s\n", resp.GetResult().GetAccess(), resp.GetResult().GetMsg())
+			}
+
+			got = permissions.Z_ForTests_IdempotentPermissionError(got)
+			golden.CheckOrUpdate(t, got)
+		})
+	}
+}
+
 func TestIDGeneration(t *testing.T) {
 	t.Parallel()
 	usernamePrefix := t.Name()
@@ -648,6 +953,149 @@ func TestSetDefaultBrokerForUser(t *testing.T) {
 	}
 }
 
+func TestCheckAccount(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username           string
+		currentUserNotRoot bool
+
+		wantStatus authd.AccountStatus
+		wantMsg    string
+		wantErr    bool
+	}{
+		"Account_is_valid":                   {username: "user-account-valid", wantStatus: authd.AccountStatus_ACCOUNT_VALID},
+		"Account_expired":                    {username: "user-account-expired", wantStatus: authd.AccountStatus_ACCOUNT_EXPIRED, wantMsg: "Your account expired"},
+		"Account_requires_a_password_change": {username: "user-account-password-change", wantStatus: authd.AccountStatus_ACCOUNT_PASSWORD_CHANGE_REQUIRED},
+		"Account_is_denied":                  {username: "user-account-denied", wantStatus: authd.AccountStatus_ACCOUNT_DENIED, wantMsg: "Your account was disabled"},
+
+		"Account_is_not_checked_when_broker_does_not_support_it": {username: "user-without-account-check", wantStatus: authd.AccountStatus_ACCOUNT_NOT_CHECKED},
+		"Account_is_not_checked_when_broker_is_not_available":    {username: "userwithinactivebroker", wantStatus: authd.AccountStatus_ACCOUNT_NOT_CHECKED},
+		"Account_is_not_checked_when_user_has_local_broker":      {username: "userwithlocalbroker", wantStatus: authd.AccountStatus_ACCOUNT_NOT_CHECKED},
+		"Account_is_not_checked_when_user_has_no_broker":         {username: "userwithoutbroker", wantStatus: authd.AccountStatus_ACCOUNT_NOT_CHECKED},
+		"Account_is_not_checked_when_user_does_not_exist":        {username: "doesnotexist", wantStatus: authd.AccountStatus_ACCOUNT_NOT_CHECKED},
+
+		"Error_when_broker_errors_out": {username: "user-account-error", wantErr: true},
+		"Error_when_username_is_empty": {wantErr: true},
+		"Error_when_not_root":          {username: "user-account-valid", currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbContent, err := os.ReadFile(filepath.Join(testutils.TestFamilyPath(t), "check-account.db"))
+			require.NoError(t, err, "Setup: could not read fixture database file")
+			dbContent = bytes.ReplaceAll(dbContent, []byte("MOCKBROKERID"), []byte(mockBrokerGeneratedID))
+			dbDir := t.TempDir()
+			err = db.Z_ForTests_CreateDBFromYAMLReader(bytes.NewBuffer(dbContent), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(users.DefaultConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			resp, err := client.CheckAccount(context.Background(), &authd.CARequest{Username: tc.username})
+			if tc.wantErr {
+				require.Error(t, err, "CheckAccount should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "CheckAccount should not return an error, but did")
+			require.Equal(t, tc.wantStatus, resp.GetStatus(), "CheckAccount should return the expected status")
+			require.Equal(t, tc.wantMsg, resp.GetMsg(), "CheckAccount should return the expected message")
+		})
+	}
+}
+
+func TestUserSession(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		method             string
+		username           string
+		action             authd.CredentialsAction
+		pamContext         *authd.PAMContext
+		currentUserNotRoot bool
+
+		wantHandled bool
+		wantEnv     map[string]string
+		wantErr     bool
+	}{
+		"OpenUserSession_is_handled":                   {method: "OpenUserSession", username: "user-session", wantHandled: true},
+		"OpenUserSession_returns_the_environment":      {method: "OpenUserSession", username: "user-session-env", wantHandled: true, wantEnv: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_user-session-env", "AUTHD_BROKER": "BrokerMock"}},
+		"CloseUserSession_is_handled":                  {method: "CloseUserSession", username: "user-session", wantHandled: true},
+		"SetUserCredentials_is_handled":                {method: "SetUserCredentials", username: "user-session", action: authd.CredentialsAction_CREDENTIALS_DELETE, wantHandled: true},
+		"SetUserCredentials_returns_the_environment":   {method: "SetUserCredentials", username: "user-session-env", action: authd.CredentialsAction_CREDENTIALS_ESTABLISH, wantHandled: true, wantEnv: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_user-session-env", "AUTHD_BROKER": "BrokerMock"}},
+		"SetUserCredentials_refreshing_is_handled":     {method: "SetUserCredentials", username: "user-session", action: authd.CredentialsAction_CREDENTIALS_REFRESH, wantHandled: true},
+		"SetUserCredentials_reinitializing_is_handled": {method: "SetUserCredentials", username: "user-session", action: authd.CredentialsAction_CREDENTIALS_REINITIALIZE, wantHandled: true},
+
+		"OpenUserSession_is_not_handled_when_broker_does_not_support_it":    {method: "OpenUserSession", username: "user-without-session-hooks"},
+		"CloseUserSession_is_not_handled_when_broker_does_not_support_it":   {method: "CloseUserSession", username: "user-without-session-hooks"},
+		"SetUserCredentials_is_not_handled_when_broker_does_not_support_it": {method: "SetUserCredentials", username: "user-without-session-hooks", action: authd.CredentialsAction_CREDENTIALS_ESTABLISH},
+		"OpenUserSession_is_not_handled_when_broker_is_not_available":       {method: "OpenUserSession", username: "userwithinactivebroker"},
+		"OpenUserSession_is_not_handled_when_user_has_local_broker":         {method: "OpenUserSession", username: "userwithlocalbroker"},
+		"OpenUserSession_is_not_handled_when_user_has_no_broker":            {method: "OpenUserSession", username: "userwithoutbroker"},
+		"OpenUserSession_is_not_handled_when_user_does_not_exist":           {method: "OpenUserSession", username: "doesnotexist"},
+
+		"Error_when_OpenUserSession_returns_an_invalid_env":    {method: "OpenUserSession", username: "user-session-invalid-env", wantErr: true},
+		"Error_when_SetUserCredentials_returns_an_invalid_env": {method: "SetUserCredentials", username: "user-session-invalid-env", action: authd.CredentialsAction_CREDENTIALS_ESTABLISH, wantErr: true},
+		"Error_when_OpenUserSession_errors_out":                {method: "OpenUserSession", username: "user-session-error", wantErr: true},
+		"Error_when_CloseUserSession_errors_out":               {method: "CloseUserSession", username: "user-session-error", wantErr: true},
+		"Error_when_SetUserCredentials_errors_out":             {method: "SetUserCredentials", username: "user-session-error", action: authd.CredentialsAction_CREDENTIALS_ESTABLISH, wantErr: true},
+		"Error_when_SetUserCredentials_action_is_unspecified":  {method: "SetUserCredentials", username: "user-session", wantErr: true},
+		"Error_when_PAM_context_is_invalid":                    {method: "OpenUserSession", username: "user-session", pamContext: &authd.PAMContext{Service: "sshd\n"}, wantErr: true},
+		"Error_when_username_is_empty":                         {method: "OpenUserSession", wantErr: true},
+		"Error_when_not_root":                                  {method: "OpenUserSession", username: "user-session", currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbContent, err := os.ReadFile(filepath.Join(testutils.TestFamilyPath(t), "user-session.db"))
+			require.NoError(t, err, "Setup: could not read fixture database file")
+			dbContent = bytes.ReplaceAll(dbContent, []byte("MOCKBROKERID"), []byte(mockBrokerGeneratedID))
+			dbDir := t.TempDir()
+			err = db.Z_ForTests_CreateDBFromYAMLReader(bytes.NewBuffer(dbContent), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(users.DefaultConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			if tc.pamContext == nil {
+				tc.pamContext = &authd.PAMContext{Service: "sshd"}
+			}
+
+			var resp *authd.USResponse
+			switch tc.method {
+			case "OpenUserSession":
+				resp, err = client.OpenUserSession(context.Background(), &authd.USRequest{Username: tc.username, PamContext: tc.pamContext})
+			case "CloseUserSession":
+				resp, err = client.CloseUserSession(context.Background(), &authd.USRequest{Username: tc.username, PamContext: tc.pamContext})
+			case "SetUserCredentials":
+				resp, err = client.SetUserCredentials(context.Background(), &authd.SUCRequest{Username: tc.username, Action: tc.action})
+			}
+			if tc.wantErr {
+				require.Error(t, err, "%s should return an error, but did not", tc.method)
+				return
+			}
+			require.NoError(t, err, "%s should not return an error, but did", tc.method)
+			require.Equal(t, tc.wantHandled, resp.GetHandled(), "%s should only be handled by brokers supporting it", tc.method)
+			if tc.wantEnv == nil {
+				tc.wantEnv = map[string]string{}
+			}
+			env := resp.GetEnv()
+			if env == nil {
+				env = map[string]string{}
+			}
+			require.Equal(t, tc.wantEnv, env, "%s should return the expected environment", tc.method)
+		})
+	}
+}
+
 func TestEndSession(t *testing.T) {
 	t.Parallel()
 
@@ -700,6 +1148,64 @@ func TestEndSession(t *testing.T) {
 	}
 }
 
+func TestListSessions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		endSession         bool
+		currentUserNotRoot bool
+
+		wantListed bool
+		wantErr    bool
+	}{
+		"Successfully_list_ongoing_session":        {wantListed: true},
+		"Successfully_omit_session_that_has_ended": {endSession: true},
+
+		"Error_when_not_root": {currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
+
+			sessionID := startSession(t, client, "success")
+			if tc.endSession {
+				_, err := client.EndSession(context.Background(), &authd.ESRequest{SessionId: sessionID})
+				require.NoError(t, err, "Setup: EndSession should not return an error, but did")
+			}
+
+			// Now, set tests permissions for this use case
+			permissions.Z_ForTests_SetCurrentUserAsRoot(&pm, !tc.currentUserNotRoot)
+
+			lsResp, err := client.ListSessions(context.Background(), &authd.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "ListSessions should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ListSessions should not return an error, but did")
+
+			// Other tests use the same broker manager, so only look for our session.
+			var got *authd.LSResponse_SessionInfo
+			for _, s := range lsResp.GetSessions() {
+				if s.GetSessionId() == sessionID {
+					got = s
+				}
+			}
+			if !tc.wantListed {
+				require.Nil(t, got, "ListSessions should not list the session, but did")
+				return
+			}
+			require.NotNil(t, got, "ListSessions should list the session, but did not")
+			require.Equal(t, mockBrokerGeneratedID, got.GetBrokerId(), "ListSessions should return the expected broker ID")
+			require.Equal(t, strings.ToLower(t.Name()+testutils.IDSeparator+"success"), got.GetUsername(), "ListSessions should return the expected username")
+			require.NotZero(t, got.GetStartTime(), "ListSessions should return the session start time")
+			require.GreaterOrEqual(t, got.GetLastActivity(), got.GetStartTime(), "Last activity should not be before the session start")
+		})
+	}
+}
+
 func TestMockgpasswd(t *testing.T) {
 	localgroupstestutils.Mockgpasswd(t)
 }
@@ -751,7 +1257,9 @@ func newPamClient(t *testing.T, m *users.Manager, brokerManager *brokers.Manager
 
 	service := pam.NewService(context.Background(), m, brokerManager, pm)
 
-	grpcServer := grpc.NewServer(permissions.WithUnixPeerCreds(), grpc.ChainUnaryInterceptor(enableCheckGlobalAccess(service), errmessages.RedactErrorInterceptor))
+	grpcServer := grpc.NewServer(permissions.WithUnixPeerCreds(),
+		grpc.ChainUnaryInterceptor(enableCheckGlobalAccess(service), errmessages.RedactErrorInterceptor),
+		grpc.ChainStreamInterceptor(enableStreamCheckGlobalAccess(service), errmessages.RedactErrorStreamInterceptor))
 	authd.RegisterPAMServer(grpcServer, service)
 	done := make(chan struct{})
 	go func() {
@@ -763,7 +1271,8 @@ func newPamClient(t *testing.T, m *users.Manager, brokerManager *brokers.Manager
 		<-done
 	})
 
-	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage))
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage), grpc.WithStreamInterceptor(errmessages.FormatErrorMessageStream))
 	require.NoError(t, err, "Setup: Could not connect to gRPC server")
 
 	t.Cleanup(func() { _ = conn.Close() }) // We don't care about the error on cleanup
@@ -794,6 +1303,17 @@ func enableCheckGlobalAccess(s pam.Service) grpc.UnaryServerInterceptor {
 	}
 }
 
+// enableStreamCheckGlobalAccess returns the streaming middleware hooking up in CheckGlobalAccess for the given service.
+func enableStreamCheckGlobalAccess(s pam.Service) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := s.CheckGlobalAccess(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
 // getMockBrokerGeneratedID returns the generated ID for the mock broker.
 func getMockBrokerGeneratedID(brokerManager *brokers.Manager) (string, error) {
 	for _, b := range brokerManager.AvailableBrokers() {
@@ -833,7 +1353,8 @@ func setupGlobalBrokerMock() (cleanup func(), err error) {
 	cleanup = busCleanup
 
 	// Start brokers mock over dbus.
-	brokersConfPath, brokerCleanup, err := initBrokers()
+	var brokerCleanup func()
+	globalBrokersConfPath, brokerCleanup, err = initBrokers()
 	if err != nil {
 		return cleanup, err
 	}
@@ -844,7 +1365,7 @@ func setupGlobalBrokerMock() (cleanup func(), err error) {
 	}
 
 	// Get manager shared across grpc services.
-	globalBrokerManager, err = brokers.NewManager(context.Background(), brokersConfPath, nil)
+	globalBrokerManager, err = brokers.NewManager(context.Background(), globalBrokersConfPath, nil, brokers.DefaultConfig)
 	if err != nil {
 		return cleanup, err
 	}
@@ -0,0 +1,125 @@
+package pam
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/decorate"
+	"golang.org/x/sys/unix"
+)
+
+// maxPAMContextFieldLength is the maximum length accepted for each field of the PAM context.
+const maxPAMContextFieldLength = 256
+
+// pamContextFromRequest validates the PAM context sent by the client against the process that performed the request.
+func (s Service) pamContextFromRequest(ctx context.Context, req *authd.PAMContext) (pamContext brokers.PAMContext, err error) {
+	defer decorate.OnError(&err, "invalid PAM context")
+
+	pamContext = brokers.PAMContext{
+		Service: req.GetService(),
+		TTY:     req.GetTty(),
+		RHost:   req.GetRhost(),
+		RUser:   req.GetRuser(),
+	}
+
+	for name, value := range map[string]string{
+		"service": pamContext.Service,
+		"tty":     pamContext.TTY,
+		"rhost":   pamContext.RHost,
+		"ruser":   pamContext.RUser,
+	} {
+		if len(value) > maxPAMContextFieldLength {
+			return brokers.PAMContext{}, fmt.Errorf("%s is longer than %d characters", name, maxPAMContextFieldLength)
+		}
+		if strings.ContainsFunc(value, unicode.IsControl) {
+			return brokers.PAMContext{}, fmt.Errorf("%s %q contains control characters", name, value)
+		}
+	}
+
+	if pamContext.TTY == "" {
+		return pamContext, nil
+	}
+
+	pid, err := s.permissionManager.PeerPID(ctx)
+	if err != nil {
+		return brokers.PAMContext{}, err
+	}
+	if err := checkTTY(pid, pamContext.TTY); err != nil {
+		return brokers.PAMContext{}, err
+	}
+
+	return pamContext, nil
+}
+
+// checkTTY ensures that the tty, if it's a terminal device, is the controlling terminal of the process.
+func checkTTY(pid int32, tty string) error {
+	isDevPath := strings.HasPrefix(tty, "/dev/")
+	path := tty
+	if !filepath.IsAbs(path) {
+		path = filepath.Join("/dev", tty)
+	}
+
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil || st.Mode&unix.S_IFMT != unix.S_IFCHR {
+		if isDevPath {
+			return fmt.Errorf("tty %q is not a terminal device", tty)
+		}
+		// Not a device, such as an X display or the "ssh" tty set by sshd.
+		return nil
+	}
+
+	ctty, err := controllingTTY(pid)
+	if err != nil {
+		return err
+	}
+	// Processes without a controlling terminal, such as display managers, can't be checked further.
+	if ctty == 0 {
+		return nil
+	}
+	if ctty != st.Rdev {
+		return fmt.Errorf("tty %q is not the terminal of the requesting process", tty)
+	}
+
+	return nil
+}
+
+// controllingTTY returns the device number of the controlling terminal of the process, or 0 if it has none.
+func controllingTTY(pid int32) (dev uint64, err error) {
+	defer decorate.OnError(&err, "can't get controlling terminal of process %d", pid)
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name can contain spaces and parentheses, so only parse the fields after its last parenthesis.
+	i := bytes.LastIndexByte(stat, ')')
+	if i < 0 {
+		return 0, fmt.Errorf("unexpected format: %q", stat)
+	}
+	// Fields are: state, ppid, pgrp, session, tty_nr…
+	fields := strings.Fields(string(stat[i+1:]))
+	if len(fields) < 5 {
+		return 0, fmt.Errorf("unexpected format: %q", stat)
+	}
+	ttyNr, err := strconv.ParseUint(fields[4], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tty number %q: %v", fields[4], err)
+	}
+	if ttyNr == 0 {
+		return 0, nil
+	}
+
+	// tty_nr encodes the major number in bits 8 to 15, and the minor number in bits 0 to 7 and 20 to 31.
+	major := uint32(ttyNr>>8) & 0xff
+	minor := uint32(ttyNr&0xff) | uint32(ttyNr>>12)&0xfff00
+	return unix.Mkdev(major, minor), nil
+}
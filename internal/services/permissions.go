@@ -8,15 +8,27 @@ import (
 )
 
 func (m Manager) globalPermissions(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	if strings.HasPrefix(info.FullMethod, "/authd.PAM/") {
-		if err := m.pamService.CheckGlobalAccess(ctx, info.FullMethod); err != nil {
-			return nil, err
-		}
-	} else if strings.HasPrefix(info.FullMethod, "/authd.NSS/") {
-		if err := m.userService.CheckGlobalAccess(ctx, info.FullMethod); err != nil {
-			return nil, err
-		}
+	if err := m.checkGlobalAccess(ctx, info.FullMethod); err != nil {
+		return nil, err
 	}
 
 	return handler(ctx, req)
 }
+
+func (m Manager) globalStreamPermissions(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := m.checkGlobalAccess(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func (m Manager) checkGlobalAccess(ctx context.Context, method string) error {
+	if strings.HasPrefix(method, "/authd.PAM/") {
+		return m.pamService.CheckGlobalAccess(ctx, method)
+	} else if strings.HasPrefix(method, "/authd.NSS/") {
+		return m.userService.CheckGlobalAccess(ctx, method)
+	}
+
+	return nil
+}
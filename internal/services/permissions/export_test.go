@@ -6,6 +6,10 @@ func NewTestPeerCredsInfo(uid uint32, pid int32) PeerCredsInfo {
 	return PeerCredsInfo{uid: uid, pid: pid}
 }
 
+func NewTestPeerCredsInfoWithStartTime(uid uint32, pid int32, startTime uint64) PeerCredsInfo {
+	return PeerCredsInfo{uid: uid, pid: pid, startTime: startTime}
+}
+
 var (
 	CurrentUserUID = currentUserUID
 )
@@ -63,6 +63,9 @@ func TestServerPeerCredsHandshake(t *testing.T) {
 	uid := currentUserUID()
 	require.Equal(t, fmt.Sprintf("uid: %d, pid: %d", uid, os.Getpid()),
 		i.AuthType(), "uid or pid received doesn't match what we expected")
+	wantStartTime, err := processStartTime(int32(os.Getpid()))
+	require.NoError(t, err, "Setup: could not get start time of current process")
+	require.Equal(t, wantStartTime, i.(peerCredsInfo).startTime, "start time received doesn't match what we expected")
 
 	// ClientHandshake status check.
 	c, i, err = s.ClientHandshake(context.Background(), "unused", conn)
@@ -78,6 +81,21 @@ func TestServerPeerCredsHandshake(t *testing.T) {
 	require.NoError(t, clientErr, "Client should not return an error")
 }
 
+func TestProcessStartTime(t *testing.T) {
+	t.Parallel()
+
+	startTime, err := processStartTime(int32(os.Getpid()))
+	require.NoError(t, err, "processStartTime should not fail for the current process")
+	require.NotZero(t, startTime, "processStartTime should return the start time of the current process")
+
+	parentStartTime, err := processStartTime(int32(os.Getppid()))
+	require.NoError(t, err, "processStartTime should not fail for the parent process")
+	require.LessOrEqual(t, parentStartTime, startTime, "Parent process should not have started after the current one")
+
+	_, err = processStartTime(-1)
+	require.Error(t, err, "processStartTime should fail for a process which does not exist")
+}
+
 func TestServerPeerCredsInvalidSocket(t *testing.T) {
 	t.Parallel()
 
@@ -46,13 +46,9 @@ func New(args ...Option) Manager {
 func (m Manager) IsRequestFromRoot(ctx context.Context) (err error) {
 	defer decorate.OnError(&err, "permission denied")
 
-	p, ok := peer.FromContext(ctx)
-	if !ok {
-		return errors.New("context request doesn't have gRPC peer information")
-	}
-	pci, ok := p.AuthInfo.(peerCredsInfo)
-	if !ok {
-		return errors.New("context request doesn't have valid gRPC peer credential information")
+	pci, err := peerCredsFromContext(ctx)
+	if err != nil {
+		return err
 	}
 
 	if pci.uid != m.rootUID {
@@ -61,3 +57,50 @@ func (m Manager) IsRequestFromRoot(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// PeerPID returns the pid of the process that performed the request.
+// It is extracted from peerCredsInfo in the gRPC context.
+func (m Manager) PeerPID(ctx context.Context) (pid int32, err error) {
+	defer decorate.OnError(&err, "can't get peer process")
+
+	pci, err := peerCredsFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return pci.pid, nil
+}
+
+// Peer identifies the process that performed a request.
+type Peer struct {
+	UID uint32
+	PID int32
+	// StartTime is the start time of the process, which tells it apart from a later process reusing its PID.
+	StartTime uint64
+}
+
+// Peer returns the process that performed the request.
+// It is extracted from peerCredsInfo in the gRPC context.
+func (m Manager) Peer(ctx context.Context) (_ Peer, err error) {
+	defer decorate.OnError(&err, "can't get peer process")
+
+	pci, err := peerCredsFromContext(ctx)
+	if err != nil {
+		return Peer{}, err
+	}
+
+	return Peer{UID: pci.uid, PID: pci.pid, StartTime: pci.startTime}, nil
+}
+
+// peerCredsFromContext returns the peer credentials stored in the gRPC context.
+func peerCredsFromContext(ctx context.Context) (peerCredsInfo, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return peerCredsInfo{}, errors.New("context request doesn't have gRPC peer information")
+	}
+	pci, ok := p.AuthInfo.(peerCredsInfo)
+	if !ok {
+		return peerCredsInfo{}, errors.New("context request doesn't have valid gRPC peer credential information")
+	}
+	return pci, nil
+}
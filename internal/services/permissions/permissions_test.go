@@ -77,6 +77,84 @@ func TestIsRequestFromRoot(t *testing.T) {
 	}
 }
 
+func TestPeerPID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		noPeerCredsInfo bool
+		noAuthInfo      bool
+
+		wantErr bool
+	}{
+		"Returns_the_peer_pid": {},
+
+		"Error_when_missing_peer_creds_Info": {noPeerCredsInfo: true, wantErr: true},
+		"Error_when_missing_auth_info_creds": {noAuthInfo: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if !tc.noPeerCredsInfo {
+				var authInfo credentials.AuthInfo
+				if !tc.noAuthInfo {
+					authInfo = permissions.NewTestPeerCredsInfo(permissions.CurrentUserUID(), 4242)
+				}
+				ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: authInfo})
+			}
+
+			pm := permissions.New()
+			pid, err := pm.PeerPID(ctx)
+			if tc.wantErr {
+				require.Error(t, err, "PeerPID should return an error but didn't")
+				return
+			}
+			require.NoError(t, err, "PeerPID should not return an error but did")
+			require.Equal(t, int32(4242), pid, "PeerPID should return the peer pid")
+		})
+	}
+}
+
+func TestPeer(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		noPeerCredsInfo bool
+		noAuthInfo      bool
+
+		wantErr bool
+	}{
+		"Returns_the_peer": {},
+
+		"Error_when_missing_peer_creds_Info": {noPeerCredsInfo: true, wantErr: true},
+		"Error_when_missing_auth_info_creds": {noAuthInfo: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if !tc.noPeerCredsInfo {
+				var authInfo credentials.AuthInfo
+				if !tc.noAuthInfo {
+					authInfo = permissions.NewTestPeerCredsInfoWithStartTime(1234, 4242, 4343)
+				}
+				ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: authInfo})
+			}
+
+			pm := permissions.New()
+			p, err := pm.Peer(ctx)
+			if tc.wantErr {
+				require.Error(t, err, "Peer should return an error but didn't")
+				return
+			}
+			require.NoError(t, err, "Peer should not return an error but did")
+			require.Equal(t, permissions.Peer{UID: 1234, PID: 4242, StartTime: 4343}, p, "Peer should return the peer process")
+		})
+	}
+}
+
 func TestWithUnixPeerCreds(t *testing.T) {
 	t.Parallel()
 
@@ -1,11 +1,15 @@
 package permissions
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math"
 	"net"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/ubuntu/decorate"
 	"golang.org/x/sys/unix"
@@ -57,7 +61,12 @@ func (serverPeerCreds) ServerHandshake(conn net.Conn) (n net.Conn, c credentials
 		return nil, nil, fmt.Errorf("Control() error: %v", err)
 	}
 
-	return conn, peerCredsInfo{uid: cred.Uid, pid: cred.Pid}, nil
+	startTime, err := processStartTime(cred.Pid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conn, peerCredsInfo{uid: cred.Uid, pid: cred.Pid, startTime: startTime}, nil
 }
 func (serverPeerCreds) ClientHandshake(_ context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
 	return conn, nil, nil
@@ -69,9 +78,37 @@ func (serverPeerCreds) OverrideServerName(_ string) error       { return nil }
 type peerCredsInfo struct {
 	uid uint32
 	pid int32
+	// startTime is the start time of the process, which tells it apart from a later process reusing its pid.
+	startTime uint64
 }
 
 // AuthType returns a string encrypting uid and pid of caller.
 func (p peerCredsInfo) AuthType() string {
 	return fmt.Sprintf("uid: %d, pid: %d", p.uid, p.pid)
 }
+
+// processStartTime returns the start time of the process, in clock ticks after the system boot.
+func processStartTime(pid int32) (startTime uint64, err error) {
+	defer decorate.OnError(&err, "can't get start time of process %d", pid)
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name can contain spaces and parentheses, so only parse the fields after its last parenthesis.
+	i := bytes.LastIndexByte(stat, ')')
+	if i < 0 {
+		return 0, fmt.Errorf("unexpected format: %q", stat)
+	}
+	// starttime is the 22nd field, and the 20th after the command name.
+	fields := strings.Fields(string(stat[i+1:]))
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("unexpected format: %q", stat)
+	}
+	startTime, err = strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start time %q: %v", fields[19], err)
+	}
+	return startTime, nil
+}
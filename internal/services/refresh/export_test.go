@@ -0,0 +1,17 @@
+package refresh
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/brokers"
+)
+
+// RefreshUsers refreshes all the users once.
+func RefreshUsers(r *Refresher, ctx context.Context) {
+	r.refreshUsers(ctx)
+}
+
+// HandleNotification applies a user change pushed by a broker.
+func HandleNotification(r *Refresher, ctx context.Context, n brokers.UserNotification) {
+	r.handleNotification(ctx, n)
+}
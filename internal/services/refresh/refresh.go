@@ -0,0 +1,277 @@
+// Package refresh periodically updates the information of the users from their broker.
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// Config is the configuration of the periodic refresh of the users.
+type Config struct {
+	// Interval is the time between two refreshes of all the users. Zero disables the refresh.
+	Interval time.Duration `mapstructure:"user_refresh_interval" yaml:"user_refresh_interval"`
+	// BrokerDelay is the minimum time between two requests sent to the same broker during a refresh.
+	BrokerDelay time.Duration `mapstructure:"user_refresh_broker_delay" yaml:"user_refresh_broker_delay"`
+	// TerminateRemovedUserSessions terminates the sessions of the users removed or disabled by their broker.
+	TerminateRemovedUserSessions bool `mapstructure:"terminate_removed_user_sessions" yaml:"terminate_removed_user_sessions"`
+}
+
+// DefaultConfig is the default configuration of the periodic refresh of the users.
+var DefaultConfig = Config{
+	BrokerDelay: time.Second,
+}
+
+// Refresher updates the users and their groups with the information returned by their broker.
+type Refresher struct {
+	userManager   *users.Manager
+	brokerManager *brokers.Manager
+	config        Config
+
+	terminateSessions func(uid uint32) error
+
+	stop func()
+	wg   sync.WaitGroup
+}
+
+type options struct {
+	terminateSessions func(uid uint32) error
+}
+
+// Option is a function that allows changing some of the default behaviors of the refresher.
+type Option func(*options)
+
+// WithSessionTerminator makes the refresher use a specific function to terminate the sessions of a user.
+// This option is only useful in tests.
+func WithSessionTerminator(terminate func(uid uint32) error) Option {
+	return func(o *options) {
+		o.terminateSessions = terminate
+	}
+}
+
+// New returns a new refresher, which applies the user changes pushed by the brokers and starts refreshing the users
+// periodically if enabled in the configuration.
+func New(ctx context.Context, userManager *users.Manager, brokerManager *brokers.Manager, config Config, args ...Option) (*Refresher, error) {
+	if config.Interval < 0 {
+		return nil, fmt.Errorf("user refresh interval can't be negative, got %v", config.Interval)
+	}
+	if config.BrokerDelay < 0 {
+		return nil, fmt.Errorf("user refresh broker delay can't be negative, got %v", config.BrokerDelay)
+	}
+
+	opts := options{
+		terminateSessions: terminateLogindSessions,
+	}
+	for _, arg := range args {
+		arg(&opts)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r := &Refresher{
+		userManager:       userManager,
+		brokerManager:     brokerManager,
+		config:            config,
+		terminateSessions: opts.terminateSessions,
+		stop:              cancel,
+	}
+
+	notifications, err := brokerManager.WatchUsers(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for n := range notifications {
+			r.handleNotification(ctx, n)
+		}
+	}()
+
+	if config.Interval == 0 {
+		log.Debug(ctx, "Periodic refresh of the users is disabled")
+		return r, nil
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(ctx)
+	}()
+
+	return r, nil
+}
+
+// Stop stops the periodic refresh and the handling of the user changes pushed by the brokers, waiting for any ongoing
+// update to be interrupted.
+func (r *Refresher) Stop() {
+	r.stop()
+	r.wg.Wait()
+}
+
+// run refreshes the users at every interval, with some jitter so that the machines using the same brokers don't
+// refresh at the same time.
+func (r *Refresher) run(ctx context.Context) {
+	for {
+		wait := r.config.Interval + rand.N(r.config.Interval/10+1)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		r.refreshUsers(ctx)
+	}
+}
+
+// refreshUsers updates all the users assigned to an available broker. Each broker is queried concurrently, but
+// sequentially for its users, waiting for the configured delay between two requests.
+func (r *Refresher) refreshUsers(ctx context.Context) {
+	log.Debug(ctx, "Refreshing users from their broker")
+
+	usrs, err := r.userManager.AllUsers()
+	if err != nil {
+		log.Warningf(ctx, "Could not get the users to refresh: %v", err)
+		return
+	}
+
+	availableBrokers := make(map[string]*brokers.Broker)
+	for _, b := range r.brokerManager.AvailableBrokers() {
+		availableBrokers[b.ID] = b
+	}
+
+	usersByBroker := make(map[*brokers.Broker][]string)
+	for _, u := range usrs {
+		brokerID, err := r.userManager.BrokerForUser(u.Name)
+		if err != nil {
+			log.Warningf(ctx, "Could not get broker of user %q: %v", u.Name, err)
+			continue
+		}
+		// The local broker does not know about any user.
+		if brokerID == "" || brokerID == brokers.LocalBrokerName {
+			continue
+		}
+		b, exists := availableBrokers[brokerID]
+		if !exists {
+			log.Debugf(ctx, "Not refreshing user %q: broker %q is not available", u.Name, brokerID)
+			continue
+		}
+		usersByBroker[b] = append(usersByBroker[b], u.Name)
+	}
+
+	var wg sync.WaitGroup
+	for b, usernames := range usersByBroker {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, username := range usernames {
+				if i > 0 {
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(r.config.BrokerDelay):
+					}
+				}
+				r.refreshUser(ctx, b, username)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// refreshUser updates the user with the information returned by the broker.
+func (r *Refresher) refreshUser(ctx context.Context, b *brokers.Broker, username string) {
+	info, err := b.UserInfo(ctx, username)
+	if err != nil {
+		log.Warningf(ctx, "Could not refresh user %q: %v", username, err)
+		return
+	}
+
+	if err := r.userManager.UpdateUser(info); err != nil {
+		log.Warningf(ctx, "Could not refresh user %q: %v", username, err)
+	}
+}
+
+// handleNotification applies a user change pushed by a broker. Changes pushed by a broker which the user isn't
+// assigned to are ignored.
+func (r *Refresher) handleNotification(ctx context.Context, n brokers.UserNotification) {
+	brokerID, err := r.userManager.BrokerForUser(n.Username)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		log.Debugf(ctx, "Ignoring change of unknown user %q pushed by broker %q", n.Username, n.BrokerID)
+		return
+	}
+	if err != nil {
+		log.Warningf(ctx, "Could not get broker of user %q: %v", n.Username, err)
+		return
+	}
+	if brokerID != n.BrokerID {
+		log.Warningf(ctx, "Ignoring change of user %q pushed by broker %q: the user is assigned to broker %q", n.Username, n.BrokerID, brokerID)
+		return
+	}
+
+	var b *brokers.Broker
+	for _, available := range r.brokerManager.AvailableBrokers() {
+		if available.ID == n.BrokerID {
+			b = available
+			break
+		}
+	}
+	if b == nil {
+		log.Warningf(ctx, "Ignoring change of user %q: broker %q is not available", n.Username, n.BrokerID)
+		return
+	}
+
+	if n.Removed {
+		r.revokeUser(ctx, b, n.Username)
+		return
+	}
+	r.refreshUser(ctx, b, n.Username)
+}
+
+// revokeUser removes the user from all its groups and locks it, keeping the user and its private group so that its
+// files stay owned by it, and terminates its sessions if enabled in the configuration.
+func (r *Refresher) revokeUser(ctx context.Context, b *brokers.Broker, username string) {
+	log.Noticef(ctx, "User %q was removed by broker %q, revoking it", username, b.Name)
+
+	u, err := r.userManager.UserByName(username)
+	if err != nil {
+		log.Warningf(ctx, "Could not revoke user %q: %v", username, err)
+		return
+	}
+
+	if err := r.userManager.RevokeUser(username); err != nil {
+		log.Warningf(ctx, "Could not revoke user %q: %v", username, err)
+		return
+	}
+
+	if !r.config.TerminateRemovedUserSessions {
+		return
+	}
+	if err := r.terminateSessions(u.UID); err != nil {
+		log.Warningf(ctx, "Could not terminate the sessions of user %q: %v", username, err)
+	}
+}
+
+// terminateLogindSessions terminates all the sessions of the user through logind.
+func terminateLogindSessions(uid uint32) (err error) {
+	defer decorate.OnError(&err, "can't terminate sessions of user %d", uid)
+
+	// Don't call dbus.SystemBus which caches globally system dbus (issues in tests)
+	bus, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return err
+	}
+	defer bus.Close()
+
+	obj := bus.Object("org.freedesktop.login1", "/org/freedesktop/login1")
+	return obj.Call("org.freedesktop.login1.Manager.TerminateUser", 0, uid).Err
+}
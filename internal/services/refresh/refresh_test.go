@@ -0,0 +1,173 @@
+package refresh_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/services/refresh"
+	"github.com/ubuntu/authd/internal/testutils"
+	"github.com/ubuntu/authd/internal/testutils/golden"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/idgenerator"
+	localgroupstestutils "github.com/ubuntu/authd/internal/users/localentries/testutils"
+	"github.com/ubuntu/authd/log"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config refresh.Config
+
+		wantErr bool
+	}{
+		"Successfully_create_a_disabled_refresher": {config: refresh.DefaultConfig},
+		"Successfully_create_a_periodic_refresher": {config: refresh.Config{Interval: time.Hour, BrokerDelay: time.Second}},
+
+		"Error_when_interval_is_negative":     {config: refresh.Config{Interval: -time.Second}, wantErr: true},
+		"Error_when_broker_delay_is_negative": {config: refresh.Config{BrokerDelay: -time.Second}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokerManager, err := brokers.NewManager(context.Background(), t.TempDir(), nil, brokers.DefaultConfig)
+			require.NoError(t, err, "Setup: could not create broker manager")
+			t.Cleanup(brokerManager.Stop)
+
+			r, err := refresh.New(context.Background(), nil, brokerManager, tc.config)
+			if tc.wantErr {
+				require.Error(t, err, "New should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "New should not return an error, but did")
+			r.Stop()
+		})
+	}
+}
+
+func TestRefreshUsers(t *testing.T) {
+	userManager, brokerManager, brokerID, dbDir := newManagersForTests(t)
+
+	r, err := refresh.New(context.Background(), userManager, brokerManager, refresh.Config{BrokerDelay: time.Millisecond})
+	require.NoError(t, err, "Setup: could not create refresher")
+	t.Cleanup(r.Stop)
+
+	refresh.RefreshUsers(r, context.Background())
+
+	checkDatabase(t, userManager, dbDir, brokerID)
+}
+
+func TestHandleNotification(t *testing.T) {
+	tests := map[string]struct {
+		username          string
+		removed           bool
+		fromOtherBroker   bool
+		terminateSessions bool
+
+		wantTerminatedUIDs []uint32
+	}{
+		"Refreshes_changed_user":                              {username: "user-pre-check"},
+		"Revokes_removed_user":                                {username: "user-pre-check", removed: true},
+		"Revokes_removed_user_and_terminates_its_sessions":    {username: "user-pre-check", removed: true, terminateSessions: true, wantTerminatedUIDs: []uint32{1111}},
+		"Ignores_changed_user_unknown_by_the_broker":          {username: "user-unknown-by-broker"},
+		"Ignores_user_unknown_by_authd":                       {username: "unknown-user", removed: true},
+		"Ignores_user_without_broker":                         {username: "user-without-broker", removed: true},
+		"Ignores_user_assigned_to_another_broker":             {username: "user-on-unavailable-broker", removed: true},
+		"Ignores_user_change_pushed_by_an_unavailable_broker": {username: "user-on-unavailable-broker", removed: true, fromOtherBroker: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			userManager, brokerManager, brokerID, dbDir := newManagersForTests(t)
+
+			var terminatedUIDs []uint32
+			terminate := func(uid uint32) error {
+				terminatedUIDs = append(terminatedUIDs, uid)
+				return nil
+			}
+			config := refresh.Config{TerminateRemovedUserSessions: tc.terminateSessions}
+			r, err := refresh.New(context.Background(), userManager, brokerManager, config, refresh.WithSessionTerminator(terminate))
+			require.NoError(t, err, "Setup: could not create refresher")
+			t.Cleanup(r.Stop)
+
+			n := brokers.UserNotification{BrokerID: brokerID, Username: tc.username, Removed: tc.removed}
+			if tc.fromOtherBroker {
+				n.BrokerID = "unavailable-broker-id"
+			}
+			refresh.HandleNotification(r, context.Background(), n)
+
+			require.Equal(t, tc.wantTerminatedUIDs, terminatedUIDs, "The sessions of the expected users should be terminated")
+			checkDatabase(t, userManager, dbDir, brokerID)
+		})
+	}
+}
+
+// newManagersForTests returns a user manager using the database fixture and a broker manager with a broker mock.
+func newManagersForTests(t *testing.T) (userManager *users.Manager, brokerManager *brokers.Manager, brokerID, dbDir string) {
+	t.Helper()
+
+	// We don't care about the output of gpasswd in this test, but we still need to mock it.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "empty.group"))
+
+	brokerCfg, cleanup, err := testutils.StartBusBrokerMock(t.TempDir(), "BrokerMock")
+	require.NoError(t, err, "Setup: could not start bus broker mock")
+	t.Cleanup(cleanup)
+
+	brokerManager, err = brokers.NewManager(context.Background(), filepath.Dir(brokerCfg), nil, brokers.DefaultConfig)
+	require.NoError(t, err, "Setup: could not create broker manager")
+	t.Cleanup(brokerManager.Stop)
+	brokerID = brokerManager.AvailableBrokers()[1].ID
+
+	dbContent, err := os.ReadFile(filepath.Join("testdata", "users.db.yaml"))
+	require.NoError(t, err, "Setup: could not read database fixture")
+	dbDir = t.TempDir()
+	err = db.Z_ForTests_CreateDBFromYAMLReader(strings.NewReader(strings.ReplaceAll(string(dbContent), "BROKER_ID", brokerID)), dbDir)
+	require.NoError(t, err, "Setup: could not create database from testdata")
+
+	userManager, err = users.NewManager(users.DefaultConfig, dbDir, users.WithIDGenerator(&idgenerator.IDGeneratorMock{
+		GIDsToGenerate: []uint32{12345},
+	}))
+	require.NoError(t, err, "Setup: could not create user manager")
+
+	return userManager, brokerManager, brokerID, dbDir
+}
+
+// checkDatabase closes the user manager and compares the content of its database with the golden file.
+func checkDatabase(t *testing.T, userManager *users.Manager, dbDir, brokerID string) {
+	t.Helper()
+
+	require.NoError(t, userManager.Stop(), "Teardown: could not close the user manager")
+	c, err := db.New(dbDir)
+	require.NoError(t, err, "Teardown: could not open the database")
+	t.Cleanup(func() { _ = c.Close() })
+
+	got, err := db.Z_ForTests_DumpNormalizedYAML(c)
+	require.NoError(t, err, "Teardown: could not dump the database")
+	golden.CheckOrUpdate(t, strings.ReplaceAll(got, brokerID, "BROKER_ID"))
+}
+
+func TestMain(m *testing.M) {
+	// Needed to skip the test setup when running the gpasswd mock.
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "" {
+		os.Exit(m.Run())
+	}
+
+	log.SetLevel(log.DebugLevel)
+
+	cleanup, err := testutils.StartSystemBusMock()
+	if err != nil {
+		fmt.Println("Error starting system bus mock:", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	m.Run()
+}
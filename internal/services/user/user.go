@@ -165,22 +165,9 @@ func (s Service) userPreCheck(ctx context.Context, username string) (types.UserE
 	username = strings.ToLower(username)
 
 	// Check if the user exists in at least one broker.
-	var userinfo string
-	var err error
-	for _, b := range s.brokerManager.AvailableBrokers() {
-		// The local broker is not a real broker, so we skip it.
-		if b.ID == brokers.LocalBrokerName {
-			continue
-		}
-
-		userinfo, err = b.UserPreCheck(ctx, username)
-		if err == nil && userinfo != "" {
-			break
-		}
-	}
-
-	if err != nil || userinfo == "" {
-		return types.UserEntry{}, fmt.Errorf("user %q is not known by any broker", username)
+	userinfo, err := s.brokerManager.UserPreCheck(ctx, username)
+	if err != nil {
+		return types.UserEntry{}, err
 	}
 
 	var u types.UserEntry
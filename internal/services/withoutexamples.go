@@ -2,7 +2,8 @@
 
 package services
 
-// Stop stops the underlying database only in production code.
+// Stop stops the broker manager and the underlying database.
 func (m *Manager) Stop() error {
+	m.brokerManager.Stop()
 	return m.stop()
 }
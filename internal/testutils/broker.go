@@ -3,6 +3,7 @@ package testutils
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"html/template"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 )
 
 const (
@@ -23,6 +25,9 @@ const (
 
 	// IDSeparator is the value used to append values to the sessionID in the broker mock.
 	IDSeparator = "_separator_"
+
+	// hangDuration is how long the broker mock takes to reply when it's requested to hang.
+	hangDuration = 3 * time.Second
 )
 
 const (
@@ -40,11 +45,27 @@ const (
 
 var brokerConfigTemplate = `[authd]
 name = %s
-brand_icon = mock_icon.png
+brand_icon = mock_icon.svg
 dbus_name = com.ubuntu.authd.%s
 dbus_object = /com/ubuntu/authd/%s
+webauthn_rp_id = authd.example.com
+`
+
+// brokerIcon is the brand icon of the broker mock.
+const brokerIcon = `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16"><rect width="16" height="16" fill="#e95420"/></svg>
 `
 
+var (
+	brokerMocks   = make(map[string]brokerMock)
+	brokerMocksMu sync.Mutex
+)
+
+// brokerMock is a running broker mock.
+type brokerMock struct {
+	conn *dbus.Conn
+	bus  *BrokerBusMock
+}
+
 type isAuthenticatedCtx struct {
 	ctx        context.Context
 	cancelFunc context.CancelFunc
@@ -53,8 +74,13 @@ type isAuthenticatedCtx struct {
 // BrokerBusMock is the D-Bus object that will answer calls for the broker mock.
 type BrokerBusMock struct {
 	name                   string
+	conn                   *dbus.Conn
 	isAuthenticatedCalls   map[string]isAuthenticatedCtx
 	isAuthenticatedCallsMu sync.RWMutex
+
+	settings       map[string]string
+	configureCalls int
+	settingsMu     sync.Mutex
 }
 
 // StartBusBrokerMock starts the D-Bus service and exports it on the system bus.
@@ -70,6 +96,7 @@ func StartBusBrokerMock(cfgDir string, brokerName string) (string, func(), error
 
 	bus := BrokerBusMock{
 		name:                   brokerName,
+		conn:                   conn,
 		isAuthenticatedCalls:   map[string]isAuthenticatedCtx{},
 		isAuthenticatedCallsMu: sync.RWMutex{},
 	}
@@ -106,21 +133,71 @@ func StartBusBrokerMock(cfgDir string, brokerName string) (string, func(), error
 		return "", nil, err
 	}
 
+	brokerMocksMu.Lock()
+	brokerMocks[brokerName] = brokerMock{conn: conn, bus: &bus}
+	brokerMocksMu.Unlock()
+
 	return configPath, func() {
+		brokerMocksMu.Lock()
+		delete(brokerMocks, brokerName)
+		brokerMocksMu.Unlock()
 		_, _ = conn.ReleaseName(busName)
 		_ = conn.Close()
 	}, nil
 }
 
+// EmitBrokerSignal emits a signal of the broker interface from the object of the named broker mock.
+func EmitBrokerSignal(brokerName, member string, args ...any) error {
+	brokerMocksMu.Lock()
+	mock, exists := brokerMocks[brokerName]
+	brokerMocksMu.Unlock()
+	if !exists {
+		return fmt.Errorf("no broker mock named %q is running", brokerName)
+	}
+
+	return mock.conn.Emit(dbus.ObjectPath(fmt.Sprintf(objectPathFmt, brokerName)), dbusInterface+"."+member, args...)
+}
+
+// BrokerMockSettings returns the settings received by the named broker mock through Configure, and how many times
+// Configure was called.
+func BrokerMockSettings(brokerName string) (settings map[string]string, configureCalls int, err error) {
+	brokerMocksMu.Lock()
+	mock, exists := brokerMocks[brokerName]
+	brokerMocksMu.Unlock()
+	if !exists {
+		return nil, 0, fmt.Errorf("no broker mock named %q is running", brokerName)
+	}
+
+	mock.bus.settingsMu.Lock()
+	defer mock.bus.settingsMu.Unlock()
+	return mock.bus.settings, mock.bus.configureCalls, nil
+}
+
 func writeConfig(cfgDir, name string) (string, error) {
 	cfgPath := filepath.Join(cfgDir, name+".conf")
-	s := fmt.Sprintf(brokerConfigTemplate, name, name, name, name)
+	s := fmt.Sprintf(brokerConfigTemplate, name, name, name)
 	if err := os.WriteFile(cfgPath, []byte(s), 0600); err != nil {
 		return "", err
 	}
+	if err := os.WriteFile(filepath.Join(cfgDir, "mock_icon.svg"), []byte(brokerIcon), 0600); err != nil {
+		return "", err
+	}
 	return cfgPath, nil
 }
 
+// Configure stores the settings sent by the daemon, or returns an error if the settings ask for it.
+func (b *BrokerBusMock) Configure(settings map[string]string) (dbusErr *dbus.Error) {
+	if settings["broker.reject"] != "" {
+		return dbus.MakeFailedError(fmt.Errorf("broker %q: invalid settings", b.name))
+	}
+
+	b.settingsMu.Lock()
+	defer b.settingsMu.Unlock()
+	b.settings = settings
+	b.configureCalls++
+	return nil
+}
+
 // NewSession returns default values to be used in tests or an error if requested.
 func (b *BrokerBusMock) NewSession(username, lang, mode string) (sessionID, encryptionKey string, dbusErr *dbus.Error) {
 	parsedUsername := parseSessionID(username)
@@ -130,9 +207,28 @@ func (b *BrokerBusMock) NewSession(username, lang, mode string) (sessionID, encr
 	if parsedUsername == "ns_no_id" {
 		return "", username + "_key", nil
 	}
+	if parsedUsername == "ns_hang" {
+		time.Sleep(hangDuration)
+	}
+	if parsedUsername == "ns_x25519" {
+		return GenerateSessionID(username), X25519EncryptionKey, nil
+	}
 	return GenerateSessionID(username), GenerateEncryptionKey(b.name), nil
 }
 
+// NewSessionWithContext returns default values to be used in tests or an error if requested.
+// The PAM context is returned as encryption key when requested.
+func (b *BrokerBusMock) NewSessionWithContext(username, lang, mode string, pamContext map[string]string) (sessionID, encryptionKey string, dbusErr *dbus.Error) {
+	switch parseSessionID(username) {
+	case "ns_no_context_support":
+		return "", "", dbus.NewError("org.freedesktop.DBus.Error.UnknownMethod", []interface{}{"NewSessionWithContext is not supported"})
+	case "ns_context":
+		return GenerateSessionID(username), fmt.Sprintf("service=%s tty=%s rhost=%s ruser=%s",
+			pamContext["service"], pamContext["tty"], pamContext["rhost"], pamContext["ruser"]), nil
+	}
+	return b.NewSession(username, lang, mode)
+}
+
 // GetAuthenticationModes returns default values to be used in tests or an error if requested.
 func (b *BrokerBusMock) GetAuthenticationModes(sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, dbusErr *dbus.Error) {
 	sessionID = parseSessionID(sessionID)
@@ -145,6 +241,11 @@ func (b *BrokerBusMock) GetAuthenticationModes(sessionID string, supportedUILayo
 		return nil, nil
 	case "gam_error":
 		return nil, dbus.MakeFailedError(fmt.Errorf("broker %q: GetAuthenticationModes errored out", b.name))
+	case "gam_hang":
+		time.Sleep(hangDuration)
+		return []map[string]string{
+			{layouts.ID: "mode1", layouts.Label: "Mode 1"},
+		}, nil
 	case "gam_multiple_modes":
 		return []map[string]string{
 			{layouts.ID: "mode1", layouts.Label: "Mode 1"},
@@ -199,8 +300,89 @@ func (b *BrokerBusMock) SelectAuthenticationMode(sessionID, authenticationModeNa
 			layouts.Entry:   "entry_type",
 			"unknown_field": "unknown",
 		}, nil
+	case "sam_success_form":
+		return map[string]string{
+			layouts.Type:  layouts.Form,
+			layouts.Label: "Enter the code sent to user1@example.com",
+			layouts.Entry: entries.Chars,
+		}, nil
+	case "sam_success_choice":
+		return map[string]string{
+			layouts.Type:  layouts.Choice,
+			layouts.Label: "Select your phone",
+			layouts.Choices: layouts.FormatChoices(
+				layouts.ChoiceItem{ID: "phone1", Label: "Work phone"},
+				layouts.ChoiceItem{ID: "phone2", Label: "Personal phone"},
+			),
+		}, nil
+	case "sam_missing_choices":
+		return map[string]string{
+			layouts.Type:  layouts.Choice,
+			layouts.Label: "Select your phone",
+		}, nil
+	case "sam_invalid_choices":
+		return map[string]string{
+			layouts.Type:    layouts.Choice,
+			layouts.Choices: "phone1,phone2",
+		}, nil
+	case "sam_success_fields":
+		return map[string]string{
+			layouts.Type:  layouts.Form,
+			layouts.Label: "Sign in with your security key",
+			layouts.Fields: layouts.FormatFields(
+				layouts.FieldItem{ID: "username", Label: "Username", Entry: entries.Chars},
+				layouts.FieldItem{ID: "pin", Label: "PIN", Entry: entries.CharsPassword},
+			),
+		}, nil
+	case "sam_invalid_fields":
+		return map[string]string{
+			layouts.Type:   layouts.Form,
+			layouts.Label:  "Sign in with your security key",
+			layouts.Fields: "username,pin",
+		}, nil
+	case "sam_fields_with_unsupported_entry":
+		return map[string]string{
+			layouts.Type:  layouts.Form,
+			layouts.Label: "Sign in with your security key",
+			layouts.Fields: layouts.FormatFields(
+				layouts.FieldItem{ID: "pin", Label: "PIN", Entry: "fingerprint"},
+			),
+		}, nil
+	case "sam_success_webauthn":
+		return map[string]string{
+			layouts.Type:        layouts.WebAuthn,
+			layouts.Label:       "Touch your security key",
+			layouts.Challenge:   base64.StdEncoding.EncodeToString([]byte("webauthn-challenge")),
+			layouts.RPID:        "authd.example.com",
+			layouts.Credentials: layouts.FormatCredentials([]byte("credential1"), []byte("credential2")),
+		}, nil
+	case "sam_invalid_webauthn_challenge":
+		return map[string]string{
+			layouts.Type:      layouts.WebAuthn,
+			layouts.Challenge: "not base64!",
+			layouts.RPID:      "authd.example.com",
+		}, nil
+	case "sam_webauthn_other_rp_id":
+		return map[string]string{
+			layouts.Type:      layouts.WebAuthn,
+			layouts.Challenge: base64.StdEncoding.EncodeToString([]byte("webauthn-challenge")),
+			layouts.RPID:      "other.example.com",
+		}, nil
+	case "sam_invalid_webauthn_credentials":
+		return map[string]string{
+			layouts.Type:        layouts.WebAuthn,
+			layouts.Challenge:   base64.StdEncoding.EncodeToString([]byte("webauthn-challenge")),
+			layouts.RPID:        "authd.example.com",
+			layouts.Credentials: "credential1",
+		}, nil
 	case "sam_error":
 		return nil, dbus.MakeFailedError(fmt.Errorf("broker %q: SelectAuthenticationMode errored out", b.name))
+	case "sam_hang":
+		time.Sleep(hangDuration)
+		return map[string]string{
+			layouts.Type:  "required-entry",
+			layouts.Entry: "entry_type",
+		}, nil
 	case "sam_no_layout":
 		return nil, nil
 	case "sam_empty_layout":
@@ -264,6 +446,16 @@ func (b *BrokerBusMock) IsAuthenticated(sessionID, authenticationData string) (a
 			data = fmt.Sprintf(`{"userinfo": %s}`, userInfoFromName(sessionID, nil))
 		}
 
+	case "ia_progress":
+		for _, message := range []string{"Push sent to your phone", "Number to match: 42"} {
+			if err := b.conn.Emit(dbus.ObjectPath(fmt.Sprintf(objectPathFmt, b.name)),
+				dbusInterface+".AuthenticationProgress", sessionID, message); err != nil {
+				return "", "", dbus.MakeFailedError(err)
+			}
+		}
+		// Give some time to the signals to be handled before the reply.
+		time.Sleep(time.Duration(SleepMultiplier() * float64(100*time.Millisecond)))
+
 	case "ia_next":
 		access = authNext
 		data = ""
@@ -288,6 +480,10 @@ func (b *BrokerBusMock) IsAuthenticated(sessionID, authenticationData string) (a
 		access = authDenied
 		data = ""
 
+	case "ia_retry":
+		access = authRetry
+		data = `{"message": "wrong password"}`
+
 	case "ia_retry_without_data":
 		access = authRetry
 		data = ""
@@ -314,6 +510,9 @@ func (b *BrokerBusMock) EndSession(sessionID string) (dbusErr *dbus.Error) {
 	if sessionID == "es_error" {
 		return dbus.MakeFailedError(fmt.Errorf("broker %q: EndSession errored out", b.name))
 	}
+	if sessionID == "es_hang" {
+		time.Sleep(hangDuration)
+	}
 	return nil
 }
 
@@ -331,10 +530,82 @@ func (b *BrokerBusMock) CancelIsAuthenticated(sessionID string) (dbusErr *dbus.E
 
 // UserPreCheck returns default values to be used in tests or an error if requested.
 func (b *BrokerBusMock) UserPreCheck(username string) (userinfo string, dbusErr *dbus.Error) {
-	if strings.ToLower(username) != "user-pre-check" {
-		return "", dbus.MakeFailedError(fmt.Errorf("broker %q: UserPreCheck errored out", b.name))
+	if strings.ToLower(username) == "user-pre-check-hang" {
+		time.Sleep(hangDuration)
+	}
+	switch strings.ToLower(username) {
+	case "user-pre-check":
+		return userInfoFromName(username, nil), nil
+	case "user-pre-check-no-groups":
+		return fmt.Sprintf(`{"Name": %q, "Dir": "/home/%[1]s", "Shell": "/bin/sh"}`, username), nil
+	case "user-pre-check-other-user":
+		return userInfoFromName("other-user", nil), nil
+	}
+	return "", dbus.MakeFailedError(fmt.Errorf("broker %q: UserPreCheck errored out", b.name))
+}
+
+// CheckAccount returns the account status matching the username, or an error if requested. Brokers are not required to
+// support account checks, so this is reported for any other user.
+func (b *BrokerBusMock) CheckAccount(username string) (status, msg string, dbusErr *dbus.Error) {
+	switch strings.ToLower(username) {
+	case "user-account-valid":
+		return "valid", "", nil
+	case "user-account-expired":
+		return "expired", "Your account expired", nil
+	case "user-account-password-change":
+		return "password_change_required", "", nil
+	case "user-account-denied":
+		return "denied", "Your account was disabled", nil
+	case "user-account-invalid-status":
+		return "not-a-status", "", nil
+	case "user-account-hang":
+		time.Sleep(hangDuration)
+		return "valid", "", nil
+	case "user-account-error":
+		return "", "", dbus.MakeFailedError(fmt.Errorf("broker %q: CheckAccount errored out", b.name))
+	}
+	return "", "", unknownMethodError("CheckAccount")
+}
+
+// OpenUserSession returns the environment of the session matching the username, or an error if requested. Brokers are
+// not required to support session hooks, so this is reported for any other user.
+func (b *BrokerBusMock) OpenUserSession(username string, pamContext map[string]string) (env map[string]string, dbusErr *dbus.Error) {
+	return b.userSessionEnv("OpenUserSession", username)
+}
+
+// CloseUserSession returns an error if requested. Brokers are not required to support session hooks, so this is
+// reported for any user not handled by the mock.
+func (b *BrokerBusMock) CloseUserSession(username string, pamContext map[string]string) (dbusErr *dbus.Error) {
+	_, dbusErr = b.userSessionEnv("CloseUserSession", username)
+	return dbusErr
+}
+
+// SetUserCredentials returns the environment of the session matching the username, or an error if requested. Brokers
+// are not required to support session hooks, so this is reported for any other user.
+func (b *BrokerBusMock) SetUserCredentials(username, action string) (env map[string]string, dbusErr *dbus.Error) {
+	return b.userSessionEnv("SetUserCredentials", username)
+}
+
+func (b *BrokerBusMock) userSessionEnv(method, username string) (env map[string]string, dbusErr *dbus.Error) {
+	switch strings.ToLower(username) {
+	case "user-session":
+		return map[string]string{}, nil
+	case "user-session-env":
+		return map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_" + username, "AUTHD_BROKER": b.name}, nil
+	case "user-session-invalid-env":
+		return map[string]string{"LD_PRELOAD": "/tmp/" + username + ".so"}, nil
+	case "user-session-hang":
+		time.Sleep(hangDuration)
+		return map[string]string{}, nil
+	case "user-session-error":
+		return nil, dbus.MakeFailedError(fmt.Errorf("broker %q: %s errored out", b.name, method))
 	}
-	return userInfoFromName(username, nil), nil
+	return nil, unknownMethodError(method)
+}
+
+// unknownMethodError returns the error sent by D-Bus when calling a method the broker does not implement.
+func unknownMethodError(method string) *dbus.Error {
+	return &dbus.Error{Name: "org.freedesktop.DBus.Error.UnknownMethod", Body: []any{method + " is not supported"}}
 }
 
 // parseSessionID is wrapper around the sessionID to remove some values appended during the tests.
@@ -430,6 +701,9 @@ func GenerateSessionID(username string) string {
 	return fmt.Sprintf("%s-session_id", username)
 }
 
+// X25519EncryptionKey is an X25519 public key, encoded as the brokers using X25519 encryption return it.
+const X25519EncryptionKey = "MCowBQYDK2VuAyEAL+V9o0fNYkMVKNqsX7spBzD/9oSvxM/C7ZCZX1jLO3Q="
+
 // GenerateEncryptionKey returns an encryption key that can be used in tests.
 func GenerateEncryptionKey(brokerName string) string {
 	return fmt.Sprintf("%s-key", brokerName)
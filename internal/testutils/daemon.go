@@ -196,7 +196,8 @@ paths:
 		logger("Daemon stopped (%v)\n ##### Output #####\n %s \n ##### END #####", err, out)
 	}()
 
-	conn, err := grpc.NewClient("unix://"+opts.socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage))
+	conn, err := grpc.NewClient("unix://"+opts.socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage),
+		grpc.WithStreamInterceptor(errmessages.FormatErrorMessageStream))
 	require.NoError(t, err, "Setup: could not connect to the daemon on %s", opts.socketPath)
 	defer conn.Close()
 
@@ -13,7 +13,7 @@ import (
 	"syscall"
 
 	// sqlite3 driver.
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/fileutils"
 	"github.com/ubuntu/authd/log"
@@ -208,6 +208,34 @@ func (err NoDataFoundError) Error() string {
 // Is makes this error insensitive to the key and table names.
 func (NoDataFoundError) Is(target error) bool { return target == NoDataFoundError{} }
 
+// LockedError is returned when the database is locked by another connection or process.
+type LockedError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (err LockedError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the error of the database.
+func (err LockedError) Unwrap() error { return err.err }
+
+// Is makes this error insensitive to the error of the database.
+func (LockedError) Is(target error) bool { return target == LockedError{} }
+
+// withLockedError returns err as a LockedError if it's due to the database being locked, or err as is otherwise.
+func withLockedError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+	if sqliteErr.Code != sqlite3.ErrBusy && sqliteErr.Code != sqlite3.ErrLocked {
+		return err
+	}
+	return LockedError{err}
+}
+
 func closeRows(rows *sql.Rows) {
 	if err := rows.Close(); err != nil {
 		log.Warningf(context.Background(), "failed to close rows: %v", err)
@@ -220,12 +248,12 @@ func commitOrRollBackTransaction(err error, tx *sql.Tx) error {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			err = errors.Join(err, fmt.Errorf("failed to rollback transaction: %w", rollbackErr))
 		}
-		return err
+		return withLockedError(err)
 	}
 
 	// Otherwise, commit the transaction
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return withLockedError(fmt.Errorf("failed to commit transaction: %w", err))
 	}
 
 	return nil
@@ -2,12 +2,17 @@ package db_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/fileutils"
@@ -488,6 +493,37 @@ func TestMigrationToLowercaseUserAndGroupNamesWithBackupFailure(t *testing.T) {
 	golden.CheckOrUpdate(t, string(userGroupContent), golden.WithPath("groups"))
 }
 
+func TestMigrationAddingLockedColumn(t *testing.T) {
+	// Don't run this test in parallel because it writes a global variable (via db.SetCreateSchemaQuery)
+	origQuery := db.GetCreateSchemaQuery()
+	oldQuery := strings.Replace(origQuery, ",\n    locked    BOOLEAN DEFAULT FALSE", "", 1)
+	require.NotEqual(t, origQuery, oldQuery, "Setup: could not remove the locked column from the schema")
+	db.SetCreateSchemaQuery(oldQuery)
+	t.Cleanup(func() {
+		db.SetCreateSchemaQuery(origQuery)
+	})
+
+	// Create a database without the locked column from the testdata
+	dbDir := t.TempDir()
+	dbFile := "one_user_and_group_without_locked_column.db.yaml"
+	err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", dbFile), dbDir)
+	require.NoError(t, err, "Setup: could not create database from testdata")
+	db.SetCreateSchemaQuery(origQuery)
+
+	// Run the migrations
+	m, err := db.New(dbDir)
+	require.NoError(t, err)
+
+	u, err := m.UserByName("user1")
+	require.NoError(t, err, "UserByName should not return an error after the migration")
+	require.False(t, u.Locked, "Existing users should not be locked after the migration")
+
+	dbContent, err := db.Z_ForTests_DumpNormalizedYAML(m)
+	require.NoError(t, err)
+
+	golden.CheckOrUpdate(t, dbContent)
+}
+
 func TestUpdateUserEntry(t *testing.T) {
 	t.Parallel()
 
@@ -875,6 +911,29 @@ func TestUpdateBrokerForUser(t *testing.T) {
 	require.Error(t, err, "UpdateBrokerForUser for a nonexistent user should return an error")
 }
 
+func TestRenameBrokerIDs(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "multiple_users_and_groups")
+
+	updated, err := c.RenameBrokerIDs(map[string]string{"broker-id": "new-broker-id", "unassigned-id": "other-id"})
+	require.NoError(t, err, "RenameBrokerIDs should not return an error")
+	require.Equal(t, int64(3), updated, "RenameBrokerIDs should update all users assigned to the renamed broker")
+
+	u, err := c.UserByName("user1")
+	require.NoError(t, err, "Setup: UserByName should not return an error")
+	require.Equal(t, "new-broker-id", u.BrokerID, "Broker ID should have been renamed")
+
+	u, err = c.UserByName("userwithoutbroker")
+	require.NoError(t, err, "Setup: UserByName should not return an error")
+	require.Empty(t, u.BrokerID, "Users without broker should not be assigned one")
+
+	// Renaming again is a no-op.
+	updated, err = c.RenameBrokerIDs(map[string]string{"broker-id": "new-broker-id"})
+	require.NoError(t, err, "RenameBrokerIDs should not return an error")
+	require.Zero(t, updated, "RenameBrokerIDs should not update any user once migrated")
+}
+
 func TestRemoveDb(t *testing.T) {
 	t.Parallel()
 
@@ -889,6 +948,50 @@ func TestRemoveDb(t *testing.T) {
 	require.ErrorIs(t, db.RemoveDB(dbDir), fs.ErrNotExist, "RemoveDB should return os.ErrNotExist on the second call")
 }
 
+func TestLockedError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err error
+
+		want bool
+	}{
+		"Database_busy_is_locked":               {err: sqlite3.Error{Code: sqlite3.ErrBusy}, want: true},
+		"Database_table_locked_is_locked":       {err: sqlite3.Error{Code: sqlite3.ErrLocked}, want: true},
+		"Wrapped_database_busy_is_locked":       {err: fmt.Errorf("failed to update user: %w", sqlite3.Error{Code: sqlite3.ErrBusy}), want: true},
+		"Other_database_error_is_not_locked":    {err: sqlite3.Error{Code: sqlite3.ErrConstraint}},
+		"Error_not_from_database_is_not_locked": {err: errors.New("database is locked")},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.WithLockedError(tc.err)
+			require.ErrorIs(t, err, tc.err, "The original error should be kept")
+			require.Equal(t, tc.want, errors.Is(err, db.LockedError{}), "The error should be a LockedError only if the database is locked")
+		})
+	}
+}
+
+func TestUpdateUserEntryWhenDatabaseIsLocked(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	other, err := sql.Open("sqlite3", c.Path())
+	require.NoError(t, err, "Setup: could not open a second connection to the database")
+	t.Cleanup(func() { _ = other.Close() })
+	tx, err := other.Begin()
+	require.NoError(t, err, "Setup: could not start a transaction on the second connection")
+	t.Cleanup(func() { _ = tx.Rollback() })
+	_, err = tx.Exec("UPDATE users SET gecos = 'locked'")
+	require.NoError(t, err, "Setup: could not lock the database from the second connection")
+
+	user := db.NewUserRow("user1", 1111, 11111, "gecos", "/home/user1", "/bin/bash")
+	err = c.UpdateUserEntry(user, []db.GroupRow{db.NewGroupRow("group1", 11111, "12345678")}, nil)
+	require.ErrorIs(t, err, db.LockedError{}, "UpdateUserEntry should return a LockedError when the database is locked")
+}
+
 func TestDeleteUser(t *testing.T) {
 	t.Parallel()
 
@@ -29,3 +29,8 @@ func GetCreateSchemaQuery() string {
 func SetCreateSchemaQuery(query string) {
 	createSchemaQuery = query
 }
+
+// WithLockedError exposes withLockedError for testing.
+func WithLockedError(err error) error {
+	return withLockedError(err)
+}
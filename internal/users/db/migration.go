@@ -206,6 +206,23 @@ var schemaMigrations = []schemaMigration{
 			return err
 		},
 	},
+	{
+		description: "Add locked column to users",
+		migrate: func(m *Manager) error {
+			// The column already exists if the database was created with the current schema.
+			var exists bool
+			query := `SELECT COUNT(*) > 0 FROM pragma_table_info('users') WHERE name = 'locked'`
+			if err := m.db.QueryRow(query).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to check for locked column: %w", err)
+			}
+			if exists {
+				return nil
+			}
+
+			_, err := m.db.Exec(`ALTER TABLE users ADD COLUMN locked BOOLEAN DEFAULT FALSE`)
+			return err
+		},
+	},
 }
 
 func (m *Manager) maybeApplyMigrations() error {
@@ -18,7 +18,7 @@ func (m *Manager) UpdateUserEntry(user UserRow, authdGroups []GroupRow, localGro
 	// Start a transaction
 	tx, err := m.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+		return withLockedError(fmt.Errorf("failed to start transaction: %w", err))
 	}
 
 	// Ensure the transaction is committed or rolled back
@@ -76,6 +76,11 @@ func handleUserUpdate(db queryable, u UserRow) error {
 		u.Shell = existingUser.Shell
 	}
 
+	// Keep the broker of the user, which is only set once the user successfully authenticated.
+	if u.BrokerID == "" {
+		u.BrokerID = existingUser.BrokerID
+	}
+
 	return insertOrUpdateUserByID(db, u)
 }
 
@@ -179,3 +184,33 @@ func (m *Manager) UpdateBrokerForUser(username, brokerID string) error {
 
 	return nil
 }
+
+// RenameBrokerIDs replaces, for all users, the broker IDs which are keys of brokerIDs by their associated value.
+// It returns the number of users whose broker ID was updated.
+func (m *Manager) RenameBrokerIDs(brokerIDs map[string]string) (updated int64, err error) {
+	// Start a transaction
+	tx, err := m.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	// Ensure the transaction is committed or rolled back
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+	}()
+
+	query := `UPDATE users SET broker_id = ? WHERE broker_id = ?`
+	for oldID, newID := range brokerIDs {
+		res, err := tx.Exec(query, newID, oldID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rename broker ID %q to %q: %w", oldID, newID, err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		updated += rowsAffected
+	}
+
+	return updated, nil
+}
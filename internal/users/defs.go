@@ -41,3 +41,19 @@ func groupEntryFromGroupWithMembers(g db.GroupWithMembers) types.GroupEntry {
 
 // NoDataFoundError is the error returned when no entry is found in the db.
 type NoDataFoundError = db.NoDataFoundError
+
+// LockedError is the error returned when the db is locked by another connection or process.
+type LockedError = db.LockedError
+
+// ConflictError is returned when a user or group of authd has the name of a user or group of the system.
+type ConflictError struct {
+	msg string
+}
+
+// Error implements the error interface.
+func (err ConflictError) Error() string {
+	return err.msg
+}
+
+// Is makes this error insensitive to the conflicting name.
+func (ConflictError) Is(target error) bool { return target == ConflictError{} }
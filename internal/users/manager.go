@@ -109,8 +109,33 @@ func (m *Manager) Stop() error {
 	return m.db.Close()
 }
 
-// UpdateUser updates the user information in the db.
-func (m *Manager) UpdateUser(u types.UserInfo) (err error) {
+// UpdateUser updates the user information in the db. This unlocks the user, as its broker knows it.
+func (m *Manager) UpdateUser(u types.UserInfo) error {
+	return m.updateUser(u, false)
+}
+
+// RevokeUser removes the user from all its groups and locks it. The user and its private group are kept, so that its
+// files stay owned by it.
+func (m *Manager) RevokeUser(username string) (err error) {
+	defer decorate.OnError(&err, "failed to revoke user %q", username)
+
+	u, err := m.db.UserByName(username)
+	if err != nil {
+		return err
+	}
+
+	return m.updateUser(types.UserInfo{
+		Name:   u.Name,
+		UID:    u.UID,
+		Gecos:  u.Gecos,
+		Dir:    u.Dir,
+		Shell:  u.Shell,
+		Groups: []types.GroupInfo{},
+	}, true)
+}
+
+// updateUser updates the user information in the db and sets whether the user is locked.
+func (m *Manager) updateUser(u types.UserInfo, locked bool) (err error) {
 	defer decorate.OnError(&err, "failed to update user %q", u.Name)
 
 	log.Debugf(context.TODO(), "Updating user %q", u.Name)
@@ -141,7 +166,7 @@ func (m *Manager) UpdateUser(u types.UserInfo) (err error) {
 		var unknownUserErr user.UnknownUserError
 		if !errors.As(err, &unknownUserErr) {
 			log.Errorf(context.Background(), "User already exists on the system: %+v", existingUser)
-			return fmt.Errorf("user %q already exists on the system (but not in this authd instance)", u.Name)
+			return ConflictError{fmt.Sprintf("user %q already exists on the system (but not in this authd instance)", u.Name)}
 		}
 
 		// The user does not exist, so we generate a unique UID for it. To avoid that a user with the same UID is
@@ -221,6 +246,7 @@ func (m *Manager) UpdateUser(u types.UserInfo) (err error) {
 	// Update user information in the db.
 	userPrivateGroup := groupRows[0]
 	userRow := db.NewUserRow(u.Name, uid, userPrivateGroup.GID, u.Gecos, u.Dir, u.Shell)
+	userRow.Locked = locked
 	if err := m.db.UpdateUserEntry(userRow, groupRows, localGroups); err != nil {
 		return err
 	}
@@ -253,7 +279,7 @@ func (m *Manager) checkGroupNameConflict(name string, ugid string) error {
 		var unknownGroupErr user.UnknownGroupError
 		if !errors.As(err, &unknownGroupErr) {
 			log.Errorf(context.Background(), "Group already exists on the system: %+v", existingGroup)
-			return fmt.Errorf("group %q already exists on the system (but not in this authd instance)", name)
+			return ConflictError{fmt.Sprintf("group %q already exists on the system (but not in this authd instance)", name)}
 		}
 		// The group does not exist on the system, so we can proceed.
 		return nil
@@ -331,6 +357,16 @@ func (m *Manager) BrokerForUser(username string) (string, error) {
 	return u.BrokerID, nil
 }
 
+// IsUserLocked returns whether the user was locked after being removed by its broker.
+func (m *Manager) IsUserLocked(username string) (bool, error) {
+	u, err := m.db.UserByName(username)
+	if err != nil {
+		return false, err
+	}
+
+	return u.Locked, nil
+}
+
 // UpdateBrokerForUser updates the broker ID for the given user.
 func (m *Manager) UpdateBrokerForUser(username, brokerID string) error {
 	if err := m.db.UpdateBrokerForUser(username, brokerID); err != nil {
@@ -340,6 +376,23 @@ func (m *Manager) UpdateBrokerForUser(username, brokerID string) error {
 	return nil
 }
 
+// RenameBrokerIDs replaces the previous broker IDs, which are the keys of brokerIDs, by the current ones for all users.
+func (m *Manager) RenameBrokerIDs(brokerIDs map[string]string) error {
+	if len(brokerIDs) == 0 {
+		return nil
+	}
+
+	updated, err := m.db.RenameBrokerIDs(brokerIDs)
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		log.Infof(context.Background(), "Migrated the broker of %d user(s) to the new broker IDs", updated)
+	}
+
+	return nil
+}
+
 // UserByName returns the user information for the given user name.
 func (m *Manager) UserByName(username string) (types.UserEntry, error) {
 	usr, err := m.db.UserByName(username)
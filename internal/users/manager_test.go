@@ -154,6 +154,7 @@ func TestUpdateUser(t *testing.T) {
 		localGroupsFile string
 
 		wantErr     bool
+		wantErrType error
 		noOutput    bool
 		wantSameUID bool
 	}{
@@ -170,8 +171,8 @@ func TestUpdateUser(t *testing.T) {
 		"Error_if_group_has_no_name":                              {groupsCase: "nameless-group", wantErr: true, noOutput: true},
 		"Error_if_group_has_conflicting_gid":                      {groupsCase: "different-name-same-gid", dbFile: "one_user_and_group", wantErr: true, noOutput: true},
 		"Error_if_group_with_same_name_but_different_UGID_exists": {groupsCase: "authd-group", dbFile: "one_user_and_group", wantErr: true, noOutput: true},
-		"Error_if_user_exists_on_system":                          {userCase: "user-exists-on-system", wantErr: true, noOutput: true},
-		"Error_if_group_exists_on_system":                         {groupsCase: "group-exists-on-system", wantErr: true, noOutput: true},
+		"Error_if_user_exists_on_system":                          {userCase: "user-exists-on-system", wantErrType: users.ConflictError{}, noOutput: true},
+		"Error_if_group_exists_on_system":                         {groupsCase: "group-exists-on-system", wantErrType: users.ConflictError{}, noOutput: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -227,8 +228,8 @@ func TestUpdateUser(t *testing.T) {
 			err := m.UpdateUser(user.UserInfo)
 			log.Debugf(context.Background(), "UpdateUser error: %v", err)
 
-			requireErrorAssertions(t, err, nil, tc.wantErr)
-			if tc.wantErr && tc.noOutput {
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if (tc.wantErr || tc.wantErrType != nil) && tc.noOutput {
 				return
 			}
 
@@ -329,6 +330,56 @@ func TestUpdateBrokerForUser(t *testing.T) {
 	}
 }
 
+func TestRevokeUser(t *testing.T) {
+	tests := map[string]struct {
+		username string
+
+		wantErr     bool
+		wantErrType error
+	}{
+		"Successfully_revoke_user": {},
+
+		"Error_if_user_does_not_exist": {username: "doesnotexist", wantErrType: db.NoDataFoundError{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "empty.group"))
+
+			if tc.username == "" {
+				tc.username = "user1"
+			}
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "multiple_users_and_groups.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir)
+
+			err = m.RevokeUser(tc.username)
+
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if tc.wantErrType != nil || tc.wantErr {
+				return
+			}
+
+			locked, err := m.IsUserLocked(tc.username)
+			require.NoError(t, err, "IsUserLocked should not return an error, but did")
+			require.True(t, locked, "Revoked user should be locked")
+
+			got, err := db.Z_ForTests_DumpNormalizedYAML(userstestutils.GetManagerDB(m))
+			require.NoError(t, err, "Created database should be valid yaml content")
+
+			golden.CheckOrUpdate(t, got)
+
+			err = m.UpdateUser(types.UserInfo{Name: tc.username, Dir: "/home/" + tc.username})
+			require.NoError(t, err, "UpdateUser should not return an error, but did")
+			locked, err = m.IsUserLocked(tc.username)
+			require.NoError(t, err, "IsUserLocked should not return an error, but did")
+			require.False(t, locked, "Updated user should be unlocked")
+		})
+	}
+}
+
 func TestUserByIDAndName(t *testing.T) {
 	tests := map[string]struct {
 		uid        uint32
@@ -1260,22 +1260,6 @@ do_pam_action (pam_handle_t *pamh,
 {
   g_autoptr(GThread) thread = NULL;
 
-#ifndef AUTHD_TEST_EXEC_MODULE
-  /* These actions aren't implemented in the go side, so let's just simplify
-   * the code in this case, and return what the module would do.
-   * But if something changes, keep this in sync with pam.go!
-   */
-  switch (action)
-    {
-    case action_type_setcred:
-    case action_type_open_session:
-    case action_type_close_session:
-      return PAM_IGNORE;
-    default:
-      break;
-    }
-#endif
-
   thread = g_thread_new (action_type_to_string (action),
                          do_pam_action_thread_adapter, &(ActionThreadArgs){
     .pamh = pamh,
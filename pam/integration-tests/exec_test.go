@@ -837,7 +837,7 @@ func TestExecModule(t *testing.T) {
 	}
 }
 
-func TestExecModuleUnimplementedActions(t *testing.T) {
+func TestExecModuleSessionActionsWithoutDaemon(t *testing.T) {
 	t.Parallel()
 	t.Cleanup(pam_test.MaybeDoLeakCheck)
 
@@ -848,7 +848,8 @@ func TestExecModuleUnimplementedActions(t *testing.T) {
 	libPath := buildExecModule(t)
 	execClient := buildExecClient(t)
 
-	tx := preparePamTransaction(t, libPath, execClient, nil, "an-user")
+	// The session actions are ignored when authd can't be reached.
+	tx := preparePamTransaction(t, libPath, execClient, []string{"socket=/some-path/not-existent-socket", "connection_timeout=100"}, "an-user")
 	require.Error(t, tx.SetCred(pam.Flags(0)), pam.ErrIgnore)
 	require.Error(t, tx.OpenSession(pam.Flags(0)), pam.ErrIgnore)
 	require.Error(t, tx.CloseSession(pam.Flags(0)), pam.ErrIgnore)
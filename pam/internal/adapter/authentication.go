@@ -2,12 +2,10 @@ package adapter
 
 import (
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/sha512"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"time"
@@ -16,7 +14,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/msteinert/pam/v2"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	pam_proto "github.com/ubuntu/authd/pam/internal/proto"
@@ -36,48 +36,80 @@ var (
 )
 
 // sendIsAuthenticated sends the authentication secrets or wait request to the brokers.
-// The event will contain the returned value from the broker.
+// The event will contain the returned value from the broker, after the progress messages it sent, if any.
 func sendIsAuthenticated(ctx context.Context, client authd.PAMClient, sessionID string,
 	authData *authd.IARequest_AuthenticationData, secret *string) tea.Cmd {
-	return func() (msg tea.Msg) {
+	return func() tea.Msg {
 		log.Debugf(context.TODO(), "Authentication request for session %q: %#v",
 			sessionID, authData.Item)
-		defer func() {
-			log.Debugf(context.TODO(), "Authentication completed for session %q: %#v",
-				sessionID, msg)
-		}()
 
-		res, err := client.IsAuthenticated(ctx, &authd.IARequest{
+		stream, err := client.IsAuthenticatedStream(ctx, &authd.IARequest{
 			SessionId:          sessionID,
 			AuthenticationData: authData,
 		})
 		if err != nil {
-			if st := status.Convert(err); st.Code() == codes.Canceled {
-				// Note that this error is only the client-side error, so being here doesn't
-				// mean the cancellation on broker side is fully completed.
-
-				// Wait for the cancellation requests to have been delivered and actually handled.
-				// The multiplier can be increased to avoid that we return the cancelled event too
-				// early, but it implies slowing down the UI responses.
-				<-time.After(cancellationWait * 3)
-
-				return isAuthenticatedResultReceived{
-					access: auth.Cancelled,
-					secret: secret,
-				}
-			}
-			return pamError{
-				status: pam.ErrSystem,
-				msg:    fmt.Sprintf("authentication status failure: %v", err),
+			return isAuthenticatedFailed(sessionID, err, secret)
+		}
+
+		return receiveIsAuthenticated(stream, sessionID, secret)()
+	}
+}
+
+// receiveIsAuthenticated waits for the next response of the authentication stream. A progress message comes with the
+// command receiving the following response.
+func receiveIsAuthenticated(stream authd.PAM_IsAuthenticatedStreamClient, sessionID string, secret *string) tea.Cmd {
+	return func() (msg tea.Msg) {
+		res, err := stream.Recv()
+		if err == nil && res.GetResponse() == nil {
+			err = errors.New("empty response")
+		}
+		if errors.Is(err, io.EOF) {
+			err = errors.New("no authentication result received")
+		}
+		if err != nil {
+			return isAuthenticatedFailed(sessionID, err, secret)
+		}
+
+		if progress, ok := res.GetResponse().(*authd.IAStreamResponse_Progress); ok {
+			return isAuthenticatedProgressReceived{
+				msg:  progress.Progress,
+				next: receiveIsAuthenticated(stream, sessionID, secret),
 			}
 		}
 
+		result := res.GetResult()
+		log.Debugf(context.TODO(), "Authentication completed for session %q: %s",
+			sessionID, result.GetAccess())
+		return isAuthenticatedResultReceived{
+			access: result.GetAccess(),
+			msg:    result.GetMsg(),
+			secret: secret,
+		}
+	}
+}
+
+// isAuthenticatedFailed returns the event for a failed authentication request, which may have been cancelled.
+func isAuthenticatedFailed(sessionID string, err error, secret *string) tea.Msg {
+	log.Debugf(context.TODO(), "Authentication failed for session %q: %v", sessionID, err)
+
+	if st := status.Convert(err); st.Code() == codes.Canceled {
+		// Note that this error is only the client-side error, so being here doesn't
+		// mean the cancellation on broker side is fully completed.
+
+		// Wait for the cancellation requests to have been delivered and actually handled.
+		// The multiplier can be increased to avoid that we return the cancelled event too
+		// early, but it implies slowing down the UI responses.
+		<-time.After(cancellationWait * 3)
+
 		return isAuthenticatedResultReceived{
-			access: res.Access,
-			msg:    res.Msg,
+			access: auth.Cancelled,
 			secret: secret,
 		}
 	}
+	return pamError{
+		status: pamStatusFromError(err, pam.ErrSystem),
+		msg:    fmt.Sprintf("authentication status failure: %v", err),
+	}
 }
 
 // isAuthenticatedRequested is the internal events signalling that authentication
@@ -101,6 +133,13 @@ type isAuthenticatedResultReceived struct {
 	msg    string
 }
 
+// isAuthenticatedProgressReceived is the internal event with a progress message of the broker for the ongoing
+// authentication request. next receives the following response of the broker.
+type isAuthenticatedProgressReceived struct {
+	msg  string
+	next tea.Cmd
+}
+
 // isAuthenticatedCancelled is the event to cancel the auth request.
 type isAuthenticatedCancelled struct{}
 
@@ -122,6 +161,7 @@ type authenticationComponent interface {
 type authenticationModel struct {
 	client     authd.PAMClient
 	clientType PamClientType
+	lang       string
 
 	inProgress       bool
 	currentModel     authenticationComponent
@@ -129,12 +169,14 @@ type authenticationModel struct {
 	currentBrokerID  string
 	currentSecret    string
 	currentLayout    string
+	currentUILayout  *authd.UILayout
 
 	authTracker *authTracker
 
-	encryptionKey *rsa.PublicKey
+	encrypter encryption.Encrypter
 
-	errorMsg string
+	errorMsg    string
+	progressMsg string
 }
 
 type authTracker struct {
@@ -168,10 +210,11 @@ type newPasswordCheckResult struct {
 }
 
 // newAuthenticationModel initializes a authenticationModel which needs to be Compose then.
-func newAuthenticationModel(client authd.PAMClient, clientType PamClientType) authenticationModel {
+func newAuthenticationModel(client authd.PAMClient, clientType PamClientType, lang string) authenticationModel {
 	return authenticationModel{
 		client:      client,
 		clientType:  clientType,
+		lang:        lang,
 		authTracker: &authTracker{cond: sync.NewCond(&sync.Mutex{})},
 	}
 }
@@ -210,6 +253,7 @@ func (m authenticationModel) Update(msg tea.Msg) (authModel authenticationModel,
 			return m, nil
 		}
 		m.inProgress = true
+		m.progressMsg = ""
 
 	case stopAuthentication:
 		safeMessageDebug(msg, "current model %v, focused %v",
@@ -225,7 +269,7 @@ func (m authenticationModel) Update(msg tea.Msg) (authModel authenticationModel,
 		currentSecret := m.currentSecret
 		return m, func() tea.Msg {
 			res := newPasswordCheckResult{ctx: msg.ctx, password: msg.password}
-			if err := checkPasswordQuality(currentSecret, msg.password); err != nil {
+			if err := checkPasswordQuality(m.lang, currentSecret, msg.password); err != nil {
 				res.msg = err.Error()
 			}
 			return res
@@ -289,14 +333,22 @@ func (m authenticationModel) Update(msg tea.Msg) (authModel authenticationModel,
 			if hasSecret && clientType == Gdm && currentLayout == layouts.NewPassword {
 				return newPasswordCheck{ctx: ctx, password: secret.Secret}
 			}
+			if needsWebAuthnAssertion(msg.item) {
+				return webAuthnAssertionRequested{ctx: ctx}
+			}
 
 			return isAuthenticatedRequestedSend{msg, ctx}
 		}
 
+	case webAuthnAssertionRequested:
+		safeMessageDebug(msg)
+		return m, tea.Sequence(sendEvent(webAuthnTouchRequested{}),
+			getWebAuthnAssertion(msg.ctx, m.currentUILayout))
+
 	case isAuthenticatedRequestedSend:
 		safeMessageDebug(msg)
 		// no password value, pass it as is
-		plainTextSecret, err := msg.encryptSecretIfPresent(m.encryptionKey)
+		plainTextSecret, err := msg.encryptSecretIfPresent(m.encrypter)
 		if err != nil {
 			return m, sendEvent(pamError{status: pam.ErrSystem, msg: fmt.Sprintf("could not encrypt password payload: %v", err)})
 		}
@@ -307,8 +359,14 @@ func (m authenticationModel) Update(msg tea.Msg) (authModel authenticationModel,
 		safeMessageDebug(msg)
 		return m, m.cancelIsAuthenticated()
 
+	case isAuthenticatedProgressReceived:
+		safeMessageDebug(msg)
+		m.progressMsg = msg.msg
+		return m, msg.next
+
 	case isAuthenticatedResultReceived:
 		safeMessageDebug(msg)
+		m.progressMsg = ""
 
 		// Resets password if the authentication wasn't successful.
 		defer func() {
@@ -344,7 +402,7 @@ func (m authenticationModel) Update(msg tea.Msg) (authModel authenticationModel,
 
 		case auth.Denied:
 			if authMsg == "" {
-				authMsg = "Access denied"
+				authMsg = i18n.Translate(m.lang, "Access denied")
 			}
 			return m, sendEvent(pamError{status: pam.ErrAuth, msg: authMsg})
 
@@ -428,13 +486,15 @@ func (m *authenticationModel) Blur() {
 
 // Compose initialize the authentication model to be used.
 // It creates and attaches the sub layout models based on UILayout.
-func (m *authenticationModel) Compose(brokerID, sessionID string, encryptionKey *rsa.PublicKey, layout *authd.UILayout) tea.Cmd {
+func (m *authenticationModel) Compose(brokerID, sessionID string, encrypter encryption.Encrypter, layout *authd.UILayout) tea.Cmd {
 	m.currentBrokerID = brokerID
 	m.currentSessionID = sessionID
-	m.encryptionKey = encryptionKey
+	m.encrypter = encrypter
 	m.currentLayout = layout.Type
+	m.currentUILayout = layout
 
 	m.errorMsg = ""
+	m.progressMsg = ""
 
 	if m.clientType != InteractiveTerminal {
 		m.currentModel = &focusTrackerModel{}
@@ -443,7 +503,8 @@ func (m *authenticationModel) Compose(brokerID, sessionID string, encryptionKey
 
 	switch layout.Type {
 	case layouts.Form:
-		form := newFormModel(layout.GetLabel(), layout.GetEntry(), layout.GetButton(), layout.GetWait() == layouts.True)
+		form := newFormModel(layout.GetLabel(), layout.GetEntry(), layout.GetButton(), layout.GetWait() == layouts.True,
+			layout.GetFields())
 		m.currentModel = form
 
 	case layouts.QrCode:
@@ -455,9 +516,15 @@ func (m *authenticationModel) Compose(brokerID, sessionID string, encryptionKey
 		m.currentModel = qrcodeModel
 
 	case layouts.NewPassword:
-		newPasswordModel := newNewPasswordModel(layout.GetLabel(), layout.GetEntry(), layout.GetButton())
+		newPasswordModel := newNewPasswordModel(m.lang, layout.GetLabel(), layout.GetEntry(), layout.GetButton())
 		m.currentModel = newPasswordModel
 
+	case layouts.Choice:
+		m.currentModel = newChoiceModel(layout.GetLabel(), layout.GetChoices())
+
+	case layouts.WebAuthn:
+		m.currentModel = newWebAuthnModel(m.lang, layout.GetLabel())
+
 	default:
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
@@ -480,6 +547,10 @@ func (m authenticationModel) View() string {
 	}
 	contents := []string{m.currentModel.View()}
 
+	if m.progressMsg != "" {
+		contents = append(contents, m.progressMsg)
+	}
+
 	errMsg := m.errorMsg
 	if errMsg != "" {
 		contents = append(contents, errorStyle.Render(errMsg))
@@ -498,6 +569,7 @@ func (m *authenticationModel) Reset() tea.Cmd {
 	m.currentSessionID = ""
 	m.currentBrokerID = ""
 	m.currentLayout = ""
+	m.currentUILayout = nil
 	return m.cancelIsAuthenticated()
 }
 
@@ -522,22 +594,36 @@ func dataToMsg(data string) (string, error) {
 	return r, nil
 }
 
-func (authData *isAuthenticatedRequestedSend) encryptSecretIfPresent(publicKey *rsa.PublicKey) (*string, error) {
-	// no password value, pass it as is
-	secret, ok := authData.item.(*authd.IARequest_AuthenticationData_Secret)
-	if !ok {
+func (authData *isAuthenticatedRequestedSend) encryptSecretIfPresent(encrypter encryption.Encrypter) (*string, error) {
+	switch item := authData.item.(type) {
+	case *authd.IARequest_AuthenticationData_Secret:
+		base64Encoded, err := encrypter.Encrypt(item.Secret)
+		if err != nil {
+			return nil, err
+		}
+		// replace the password with its encrypted value
+		authData.item = &authd.IARequest_AuthenticationData_Secret{Secret: base64Encoded}
+		return &item.Secret, nil
+
+	case *authd.IARequest_AuthenticationData_Fields:
+		// Each field is encrypted on its own, there's no single secret to return.
+		secrets := make(map[string]string, len(item.Fields.GetSecrets()))
+		for id, value := range item.Fields.GetSecrets() {
+			base64Encoded, err := encrypter.Encrypt(value)
+			if err != nil {
+				return nil, err
+			}
+			secrets[id] = base64Encoded
+		}
+		authData.item = &authd.IARequest_AuthenticationData_Fields{
+			Fields: &authd.IARequest_AuthenticationData_FieldSecrets{Secrets: secrets},
+		}
 		return nil, nil
-	}
 
-	ciphertext, err := rsa.EncryptOAEP(sha512.New(), rand.Reader, publicKey, []byte(secret.Secret), nil)
-	if err != nil {
-		return nil, err
+	default:
+		// no password value, pass it as is
+		return nil, nil
 	}
-
-	// encrypt it to base64 and replace the password with it
-	base64Encoded := base64.StdEncoding.EncodeToString(ciphertext)
-	authData.item = &authd.IARequest_AuthenticationData_Secret{Secret: base64Encoded}
-	return &secret.Secret, nil
 }
 
 // wait waits for the current authentication to be completed.
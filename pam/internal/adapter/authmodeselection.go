@@ -70,15 +70,17 @@ func (m authModeSelectionModel) Init() tea.Cmd {
 			entries.CharsPassword,
 		)
 		rendersQrCode := true
+		supportsFields := true
 
 		return supportedUILayoutsReceived{
 			layouts: []*authd.UILayout{
 				{
-					Type:   layouts.Form,
-					Label:  &required,
-					Entry:  &supportedEntries,
-					Wait:   &layouts.OptionalWithBooleans,
-					Button: &optional,
+					Type:           layouts.Form,
+					Label:          &required,
+					Entry:          &supportedEntries,
+					Wait:           &layouts.OptionalWithBooleans,
+					Button:         &optional,
+					SupportsFields: &supportsFields,
 				},
 				{
 					Type:          layouts.QrCode,
@@ -95,6 +97,17 @@ func (m authModeSelectionModel) Init() tea.Cmd {
 					Entry:  &supportedEntries,
 					Button: &optional,
 				},
+				{
+					Type:  layouts.Choice,
+					Label: &optional,
+				},
+				{
+					Type:        layouts.WebAuthn,
+					Label:       &optional,
+					Challenge:   &required,
+					RpId:        &required,
+					Credentials: &optional,
+				},
 			},
 		}
 	}
@@ -230,7 +243,7 @@ func getAuthenticationModes(client authd.PAMClient, sessionID string, uiLayouts
 		gamResp, err := client.GetAuthenticationModes(context.Background(), gamReq)
 		if err != nil {
 			return pamError{
-				status: pam.ErrSystem,
+				status: pamStatusFromError(err, pam.ErrSystem),
 				msg:    fmt.Sprintf("could not get authentication modes: %v", err),
 			}
 		}
@@ -16,6 +16,7 @@ import (
 type brokerSelectionModel struct {
 	List
 
+	pamMTx pam.ModuleTransaction
 	client authd.PAMClient
 
 	availableBrokers []*authd.ABResponse_BrokerInfo
@@ -44,9 +45,10 @@ func selectBroker(brokerID string) tea.Cmd {
 }
 
 // newBrokerSelectionModel initializes an empty list with default options of brokerSelectionModel.
-func newBrokerSelectionModel(client authd.PAMClient, clientType PamClientType) brokerSelectionModel {
+func newBrokerSelectionModel(mTx pam.ModuleTransaction, client authd.PAMClient, clientType PamClientType) brokerSelectionModel {
 	return brokerSelectionModel{
 		List:   NewList(clientType, "Select your provider"),
+		pamMTx: mTx,
 		client: client,
 	}
 }
@@ -60,7 +62,7 @@ func (m brokerSelectionModel) Init() tea.Cmd {
 func (m brokerSelectionModel) Update(msg tea.Msg) (brokerSelectionModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case supportedUILayoutsSet:
-		return m, getAvailableBrokers(m.client)
+		return m, getAvailableBrokers(m.pamMTx, m.client)
 
 	case brokersListReceived:
 		safeMessageDebug(msg)
@@ -155,12 +157,19 @@ type brokerItem struct {
 func (i brokerItem) FilterValue() string { return "" }
 
 // getAvailableBrokers returns available broker list from authd.
-func getAvailableBrokers(client authd.PAMClient) tea.Cmd {
+// The brokers are filtered by the PAM service and the PAM user, if it's already known.
+func getAvailableBrokers(mTx pam.ModuleTransaction, client authd.PAMClient) tea.Cmd {
 	return func() tea.Msg {
-		brokersInfo, err := client.AvailableBrokers(context.TODO(), &authd.Empty{})
+		// These are only hints for authd, so we don't fail if we can't get them.
+		service, _ := mTx.GetItem(pam.Service)
+		username, _ := mTx.GetItem(pam.User)
+		brokersInfo, err := client.AvailableBrokers(context.TODO(), &authd.ABRequest{
+			Username: username,
+			Service:  service,
+		})
 		if err != nil {
 			return pamError{
-				status: pam.ErrSystem,
+				status: pamStatusFromError(err, pam.ErrSystem),
 				msg:    fmt.Sprintf("could not get current available brokers: %v", err),
 			}
 		}
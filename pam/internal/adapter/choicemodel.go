@@ -0,0 +1,95 @@
+package adapter
+
+import (
+	"context"
+
+	tea_list "github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+)
+
+// choiceModel is the choice layout type to allow authentication by selecting one of the options provided by the broker.
+type choiceModel struct {
+	List
+
+	label string
+}
+
+// choiceItem is the list item corresponding to a choice provided by the broker.
+type choiceItem struct {
+	id    string
+	label string
+}
+
+// FilterValue allows filtering the list items.
+func (i choiceItem) FilterValue() string { return "" }
+
+// newChoiceModel initializes and return a new choiceModel.
+func newChoiceModel(label string, choices []*authd.UILayout_Choice) *choiceModel {
+	l := NewList(InteractiveTerminal, "")
+
+	var items []tea_list.Item
+	for _, c := range choices {
+		items = append(items, choiceItem{id: c.GetId(), label: c.GetLabel()})
+	}
+	l.SetItems(items)
+
+	return &choiceModel{
+		List:  l,
+		label: label,
+	}
+}
+
+// Init initializes choiceModel.
+func (m *choiceModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles events and actions.
+func (m *choiceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case startAuthentication:
+		m.Select(0)
+		return m, nil
+
+	case listItemSelected:
+		if !m.Focused() {
+			return m, nil
+		}
+		item, ok := msg.item.(choiceItem)
+		if !ok {
+			return m, nil
+		}
+
+		safeMessageDebug(msg)
+		return m, sendEvent(isAuthenticatedRequested{
+			item: &authd.IARequest_AuthenticationData_Choice{Choice: item.id},
+		})
+	}
+
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return m, cmd
+}
+
+// View renders a text view of the choices.
+func (m *choiceModel) View() string {
+	var fields []string
+	if m.label != "" {
+		fields = append(fields, m.label)
+	}
+	fields = append(fields, m.List.View())
+
+	return lipgloss.JoinVertical(lipgloss.Left, fields...)
+}
+
+// Focus focuses this model.
+func (m *choiceModel) Focus() tea.Cmd {
+	log.Debugf(context.TODO(), "%T: Focus", m)
+	// The authentication model only forwards the events to the focused models, so
+	// we can't rely on the list focus event to be delivered.
+	m.focused = true
+	return nil
+}
@@ -3,8 +3,6 @@ package adapter
 import (
 	"context"
 	"fmt"
-	"os"
-	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/msteinert/pam/v2"
@@ -21,33 +19,28 @@ func sendEvent(msg tea.Msg) tea.Cmd {
 }
 
 // startBrokerSession returns the sessionID after marking a broker as current.
-func startBrokerSession(client authd.PAMClient, brokerID, username string, mode authd.SessionMode) tea.Cmd {
+func startBrokerSession(mTx pam.ModuleTransaction, client authd.PAMClient, brokerID, username, lang string, mode authd.SessionMode) tea.Cmd {
 	return func() tea.Msg {
 		if brokerID == brokers.LocalBrokerName {
 			return pamError{status: pam.ErrIgnore}
 		}
 
 		// Start a transaction for this user with the broker.
-		// TODO: gdm case?
-		lang := "C"
-		for _, e := range []string{"LANG", "LC_MESSAGES", "LC_ALL"} {
-			l := os.Getenv(e)
-			if l != "" {
-				lang = l
-			}
-		}
-		lang = strings.TrimSuffix(lang, ".UTF-8")
-
 		sbReq := &authd.SBRequest{
-			BrokerId: brokerID,
-			Username: username,
-			Lang:     lang,
-			Mode:     mode,
+			BrokerId:   brokerID,
+			Username:   username,
+			Lang:       lang,
+			Mode:       mode,
+			PamContext: GetPAMContext(mTx),
+			SupportedEncryptionAlgorithms: []authd.EncryptionAlgorithm{
+				authd.EncryptionAlgorithm_ENCRYPTION_RSA_OAEP,
+				authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+			},
 		}
 
 		sbResp, err := client.SelectBroker(context.TODO(), sbReq)
 		if err != nil {
-			return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("can't select broker: %v", err)}
+			return pamError{status: pamStatusFromError(err, pam.ErrSystem), msg: fmt.Sprintf("can't select broker: %v", err)}
 		}
 
 		sessionID := sbResp.GetSessionId()
@@ -58,11 +51,23 @@ func startBrokerSession(client authd.PAMClient, brokerID, username string, mode
 		if encryptionKey == "" {
 			return pamError{status: pam.ErrSystem, msg: "no encryption key returned by broker"}
 		}
+		usedBrokerID := sbResp.GetBrokerId()
+		if usedBrokerID == "" {
+			usedBrokerID = brokerID
+		}
+		var unavailableBrokerID string
+		if usedBrokerID != brokerID {
+			log.Infof(context.TODO(), "Broker %q is unavailable, session started on fallback broker %q", brokerID, usedBrokerID)
+			unavailableBrokerID = brokerID
+		}
 
 		return SessionStarted{
-			brokerID:      brokerID,
-			sessionID:     sessionID,
-			encryptionKey: encryptionKey,
+			brokerID:            usedBrokerID,
+			unavailableBrokerID: unavailableBrokerID,
+			sessionID:           sessionID,
+			brokerSessionID:     brokers.BrokerSessionID(usedBrokerID, sessionID),
+			encryptionKey:       encryptionKey,
+			encryptionAlgorithm: sbResp.GetEncryptionAlgorithm(),
 		}
 	}
 }
@@ -78,7 +83,7 @@ func getLayout(client authd.PAMClient, sessionID, authModeID string) tea.Cmd {
 		if err != nil {
 			// TODO: probably go back to broker selection here
 			return pamError{
-				status: pam.ErrSystem,
+				status: pamStatusFromError(err, pam.ErrSystem),
 				msg:    fmt.Sprintf("can't select authentication mode: %v", err),
 			}
 		}
@@ -0,0 +1,163 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+)
+
+// FirstPassPolicy defines how the password already set in PAM_AUTHTOK by a previous module is used.
+type FirstPassPolicy int
+
+const (
+	// NoFirstPass ignores PAM_AUTHTOK and always prompts the user.
+	NoFirstPass FirstPassPolicy = iota
+	// TryFirstPass sends PAM_AUTHTOK as password, prompting the user if it's unset or rejected.
+	TryFirstPass
+	// UseFirstPass sends PAM_AUTHTOK as password, failing if it's unset or rejected.
+	UseFirstPass
+)
+
+// firstPassResultReceived is the internal event with the authentication result of the password in PAM_AUTHTOK.
+type firstPassResultReceived struct {
+	layout   *authd.UILayout
+	password string
+	access   string
+	msg      string
+}
+
+// isPasswordForm returns whether the layout is a form asking for a single password.
+func isPasswordForm(layout *authd.UILayout) bool {
+	return layout.GetType() == layouts.Form &&
+		len(layout.GetFields()) == 0 &&
+		layout.GetEntry() == entries.CharsPassword
+}
+
+// sendFirstPass sends the password to the broker as the secret of the session, without prompting the user.
+func sendFirstPass(client authd.PAMClient, session *sessionInfo, layout *authd.UILayout, password string) tea.Cmd {
+	return func() tea.Msg {
+		log.Debugf(context.TODO(), "Authentication with PAM_AUTHTOK for session %q", session.sessionID)
+
+		secret, err := session.encrypter.Encrypt(password)
+		if err != nil {
+			return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("could not encrypt password payload: %v", err)}
+		}
+
+		res, err := client.IsAuthenticated(context.TODO(), &authd.IARequest{
+			SessionId: session.sessionID,
+			AuthenticationData: &authd.IARequest_AuthenticationData{
+				Item: &authd.IARequest_AuthenticationData_Secret{Secret: secret},
+			},
+		})
+		if err != nil {
+			return pamError{
+				status: pamStatusFromError(err, pam.ErrSystem),
+				msg:    fmt.Sprintf("authentication status failure: %v", err),
+			}
+		}
+
+		log.Debugf(context.TODO(), "Authentication with PAM_AUTHTOK completed for session %q: %s",
+			session.sessionID, res.GetAccess())
+		return firstPassResultReceived{
+			layout:   layout,
+			password: password,
+			access:   res.GetAccess(),
+			msg:      res.GetMsg(),
+		}
+	}
+}
+
+// canTryFirstPass returns whether the password set in PAM_AUTHTOK should be sent for the layout.
+func (m uiModel) canTryFirstPass(layout *authd.UILayout) bool {
+	return m.firstPass != NoFirstPass && !m.firstPassTried &&
+		m.sessionMode == authd.SessionMode_LOGIN && isPasswordForm(layout)
+}
+
+// tryFirstPass sends the password set in PAM_AUTHTOK, if any, or prompts the user for the layout.
+func (m uiModel) tryFirstPass(layout *authd.UILayout) tea.Cmd {
+	password, err := m.pamMTx.GetItem(pam.Authtok)
+	if err != nil {
+		log.Warningf(context.TODO(), "Impossible to get PAM_AUTHTOK: %v", err)
+	}
+	if password != "" {
+		return sendFirstPass(m.client, m.currentSession, layout, password)
+	}
+
+	if m.firstPass == UseFirstPass {
+		return sendEvent(pamError{status: pam.ErrAuth, msg: i18n.Translate(m.lang, "no password set by a previous module")})
+	}
+	return sendEvent(UILayoutReceived{layout})
+}
+
+// handleFirstPassResult handles the authentication result of the password set in PAM_AUTHTOK.
+func (m *uiModel) handleFirstPassResult(msg firstPassResultReceived) tea.Cmd {
+	if m.currentSession == nil {
+		return nil
+	}
+
+	authMsg, err := dataToMsg(msg.msg)
+	if err != nil {
+		return sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
+	}
+
+	switch msg.access {
+	case auth.Granted:
+		m.currentSession.password = msg.password
+		return sendEvent(PamSuccess{BrokerID: m.currentSession.brokerID, msg: authMsg})
+
+	case auth.Next:
+		m.currentSession.password = msg.password
+		m.authenticationModel.currentSecret = msg.password
+		return sendEvent(GetAuthenticationModesRequested{})
+
+	case auth.Retry:
+		if m.firstPass == UseFirstPass {
+			if authMsg == "" {
+				authMsg = i18n.Translate(m.lang, "Access denied")
+			}
+			return sendEvent(pamError{status: pam.ErrAuth, msg: authMsg})
+		}
+		return sendEvent(UILayoutReceived{msg.layout})
+
+	case auth.Denied:
+		if authMsg == "" {
+			authMsg = i18n.Translate(m.lang, "Access denied")
+		}
+		return sendEvent(pamError{status: pam.ErrAuth, msg: authMsg})
+	}
+
+	return nil
+}
+
+// keepPassword keeps the password the user successfully authenticated with, to set it as PAM_AUTHTOK.
+func (m *uiModel) keepPassword(msg isAuthenticatedResultReceived) {
+	if m.currentSession == nil || msg.secret == nil {
+		return
+	}
+	if msg.access != auth.Granted && msg.access != auth.Next {
+		return
+	}
+	if !isPasswordForm(m.authenticationModel.currentUILayout) &&
+		m.authenticationModel.currentLayout != layouts.NewPassword {
+		return
+	}
+	m.currentSession.password = *msg.secret
+}
+
+// setAuthTok sets the password the user authenticated with as PAM_AUTHTOK, for the next modules of the stack.
+func (m uiModel) setAuthTok() {
+	if m.sessionMode != authd.SessionMode_LOGIN || m.currentSession == nil || m.currentSession.password == "" {
+		return
+	}
+	if err := m.pamMTx.SetItem(pam.Authtok, m.currentSession.password); err != nil {
+		log.Warningf(context.TODO(), "Impossible to set PAM_AUTHTOK: %v", err)
+	}
+}
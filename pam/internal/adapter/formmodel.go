@@ -20,16 +20,30 @@ type formModel struct {
 	focusableModels []authenticationComponent
 	focusIndex      int
 
+	// fieldIDs and fieldLabels describe the entries of a multi-field form, which are the first focusable models.
+	fieldIDs    []string
+	fieldLabels []string
+
 	wait bool
 }
 
 // newFormModel initializes and return a new formModel.
-func newFormModel(label, entryType, buttonLabel string, wait bool) formModel {
+func newFormModel(label, entryType, buttonLabel string, wait bool, fields []*authd.UILayout_Field) formModel {
 	var focusableModels []authenticationComponent
+	var fieldIDs, fieldLabels []string
 
 	// TODO: add digits and force validation.
+	for _, f := range fields {
+		entry := newTextInputModel(f.GetEntry())
+		focusableModels = append(focusableModels, &entry)
+		fieldIDs = append(fieldIDs, f.GetId())
+		fieldLabels = append(fieldLabels, strings.TrimSuffix(f.GetLabel(), ":")+":")
+	}
 	switch entryType {
 	case entries.Chars, entries.CharsPassword:
+		if len(fields) > 0 {
+			break
+		}
 		entry := newTextInputModel(entryType)
 		focusableModels = append(focusableModels, &entry)
 		label = strings.TrimSuffix(label, ":") + ":"
@@ -44,6 +58,8 @@ func newFormModel(label, entryType, buttonLabel string, wait bool) formModel {
 		wait:  wait,
 
 		focusableModels: focusableModels,
+		fieldIDs:        fieldIDs,
+		fieldLabels:     fieldLabels,
 	}
 }
 
@@ -68,10 +84,16 @@ func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Start again from the first field of a multi-field form.
+		var cmd tea.Cmd
+		if len(m.fieldIDs) > 0 && m.focusIndex != 0 {
+			cmd = m.focusField(0)
+		}
+
 		if !m.wait {
-			return m, nil
+			return m, cmd
 		}
-		return m, tea.Sequence(m.updateFocusModel(msg), sendEvent(isAuthenticatedRequested{
+		return m, tea.Sequence(cmd, m.updateFocusModel(msg), sendEvent(isAuthenticatedRequested{
 			item: &authd.IARequest_AuthenticationData_Wait{Wait: layouts.True},
 		}))
 	}
@@ -87,6 +109,9 @@ func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			entry := m.focusableModels[m.focusIndex]
 			switch entry := entry.(type) {
 			case *textinputModel:
+				if len(m.fieldIDs) > 0 {
+					return m, m.submitFields()
+				}
 				return m, sendEvent(isAuthenticatedRequested{
 					item: &authd.IARequest_AuthenticationData_Secret{
 						Secret: entry.Value(),
@@ -95,25 +120,49 @@ func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "tab":
-			m.focusIndex++
-			if m.focusIndex == len(m.focusableModels) {
-				m.focusIndex = 0
-			}
-			var cmd tea.Cmd
-			for i, fm := range m.focusableModels {
-				if i != m.focusIndex {
-					fm.Blur()
-					continue
-				}
-				cmd = fm.Focus()
+			focusIndex := m.focusIndex + 1
+			if focusIndex == len(m.focusableModels) {
+				focusIndex = 0
 			}
-			return m, cmd
+			return m, m.focusField(focusIndex)
 		}
 	}
 
 	return m, m.updateFocusModel(msg)
 }
 
+// focusField focuses the focusable model at index, blurring the others.
+func (m *formModel) focusField(index int) tea.Cmd {
+	m.focusIndex = index
+	var cmd tea.Cmd
+	for i, fm := range m.focusableModels {
+		if i != m.focusIndex {
+			fm.Blur()
+			continue
+		}
+		cmd = fm.Focus()
+	}
+	return cmd
+}
+
+// submitFields moves to the next field of a multi-field form, or requests the authentication with the values of all
+// the fields once the last one is entered.
+func (m *formModel) submitFields() tea.Cmd {
+	if m.focusIndex < len(m.fieldIDs)-1 {
+		return m.focusField(m.focusIndex + 1)
+	}
+
+	secrets := make(map[string]string, len(m.fieldIDs))
+	for i, id := range m.fieldIDs {
+		secrets[id] = convertTo[*textinputModel](m.focusableModels[i]).Value()
+	}
+	return sendEvent(isAuthenticatedRequested{
+		item: &authd.IARequest_AuthenticationData_Fields{
+			Fields: &authd.IARequest_AuthenticationData_FieldSecrets{Secrets: secrets},
+		},
+	})
+}
+
 func (m *formModel) updateFocusModel(msg tea.Msg) tea.Cmd {
 	if m.focusIndex >= len(m.focusableModels) {
 		return nil
@@ -132,7 +181,10 @@ func (m formModel) View() string {
 		fields = append(fields, m.label)
 	}
 
-	for _, fm := range m.focusableModels {
+	for i, fm := range m.focusableModels {
+		if i < len(m.fieldLabels) {
+			fields = append(fields, m.fieldLabels[i])
+		}
 		fields = append(fields, fm.View())
 	}
 
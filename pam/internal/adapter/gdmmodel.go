@@ -257,6 +257,16 @@ func (m gdmModel) Update(msg tea.Msg) (gdmModel, tea.Cmd) {
 			StartAuthentication: &gdm.Events_StartAuthentication{},
 		})
 
+	case webAuthnTouchRequested:
+		return m, m.emitEvent(&gdm.EventData_WebAuthnTouchRequested{
+			WebAuthnTouchRequested: &gdm.Events_WebAuthnTouchRequested{},
+		})
+
+	case isAuthenticatedProgressReceived:
+		return m, m.emitEvent(&gdm.EventData_AuthenticationProgress{
+			AuthenticationProgress: &gdm.Events_AuthenticationProgress{Message: msg.msg},
+		})
+
 	case stopAuthentication:
 		m.waitingAuth = false
 
@@ -46,6 +46,8 @@ type gdmConvHandler struct {
 
 	startAuthRequested chan struct{}
 	authEvents         []*authd.IAResponse
+	progressMessages   []string
+	infoMessages       []string
 }
 
 func (h *gdmConvHandler) checkAllEventsHaveBeenEmitted() bool {
@@ -65,6 +67,9 @@ func (h *gdmConvHandler) RespondPAM(style pam.Style, prompt string) (string, err
 	switch style {
 	case pam.TextInfo:
 		h.t.Logf("GDM PAM Info Message: %s", prompt)
+		h.mu.Lock()
+		h.infoMessages = append(h.infoMessages, prompt)
+		h.mu.Unlock()
 	case pam.ErrorMsg:
 		h.t.Logf("GDM PAM Error Message: %s", prompt)
 	default:
@@ -255,6 +260,9 @@ func (h *gdmConvHandler) handleEvent(event *gdm.EventData) error {
 
 	case *gdm.EventData_AuthEvent:
 		h.authEvents = append(h.authEvents, ev.AuthEvent.Response)
+
+	case *gdm.EventData_AuthenticationProgress:
+		h.progressMessages = append(h.progressMessages, ev.AuthenticationProgress.Message)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"cmp"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
@@ -18,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/pam/internal/gdm"
@@ -28,6 +30,7 @@ import (
 )
 
 var gdmTestPrivateKey *rsa.PrivateKey
+var gdmTestWebAuthnAuthenticator *pam_test.SoftwareAuthenticator
 
 const gdmTestIgnoredMessage string = "<ignored>"
 
@@ -73,6 +76,12 @@ func TestGdmModel(t *testing.T) {
 		}, nil),
 		pam_test.WithUILayout(newPasswordUILayoutID, "New Password form", pam_test.NewPasswordUILayout()),
 	}
+	firstPassClientOptions := append(slices.Clone(singleBrokerClientOptions),
+		pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+		pam_test.WithUILayout(passwordUILayoutID, "Password authentication",
+			pam_test.FormUILayout(pam_test.WithEntry(entries.CharsPassword))),
+		pam_test.WithIsAuthenticatedWantSecret("gdm-good-password"),
+	)
 	multiBrokerClientOptions := append(slices.Clone(singleBrokerClientOptions),
 		pam_test.WithAvailableBrokers([]*authd.ABResponse_BrokerInfo{
 			firstBrokerInfo, secondBrokerInfo,
@@ -90,11 +99,15 @@ func TestGdmModel(t *testing.T) {
 		protoVersion     uint32
 		convError        map[string]error
 		timeout          time.Duration
+		firstPass        FirstPassPolicy
+		pamAuthTok       string
 
 		wantExitStatus     PamReturnStatus
 		wantGdmRequests    []gdm.RequestType
 		wantGdmEvents      []gdm.EventType
 		wantGdmAuthRes     []*authd.IAResponse
+		wantGdmProgress    []string
+		wantGdmInfo        []string
 		wantNoGdmRequests  []gdm.RequestType
 		wantNoGdmEvents    []gdm.EventType
 		wantNoBrokers      bool
@@ -102,6 +115,7 @@ func TestGdmModel(t *testing.T) {
 		wantStage          pam_proto.Stage
 		wantUsername       string
 		wantMessages       []tea.Msg
+		wantAuthTok        string
 	}{
 		"User_selection_stage": {
 			wantGdmRequests: []gdm.RequestType{gdm.RequestType_uiLayoutCapabilities},
@@ -263,6 +277,201 @@ func TestGdmModel(t *testing.T) {
 			wantStage:      pam_proto.Stage_challenge,
 			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
 		},
+		"Authenticated_with_use_first_pass_without_prompting": {
+			clientOptions:      slices.Clone(firstPassClientOptions),
+			pamUser:            "pam-preset-user-with-use-first-pass",
+			firstPass:          UseFirstPass,
+			pamAuthTok:         "gdm-good-password",
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+			},
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantStage:      gdmTestIgnoreStage,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+			wantAuthTok:    "gdm-good-password",
+		},
+		"Authenticated_with_try_first_pass_without_prompting": {
+			clientOptions:      slices.Clone(firstPassClientOptions),
+			pamUser:            "pam-preset-user-with-try-first-pass",
+			firstPass:          TryFirstPass,
+			pamAuthTok:         "gdm-good-password",
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+			},
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantStage:      gdmTestIgnoreStage,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+			wantAuthTok:    "gdm-good-password",
+		},
+		"Authenticated_with_try_first_pass_after_prompting_if_first_pass_is_wrong": {
+			clientOptions: append(slices.Clone(firstPassClientOptions),
+				pam_test.WithIsAuthenticatedMaxRetries(1),
+			),
+			pamUser:    "pam-preset-user-with-wrong-try-first-pass",
+			firstPass:  TryFirstPass,
+			pamAuthTok: "gdm-wrong-password",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Secret{
+							Secret: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+			wantAuthTok:    "gdm-good-password",
+		},
+		"Authenticated_with_try_first_pass_after_prompting_if_first_pass_is_unset": {
+			clientOptions: slices.Clone(firstPassClientOptions),
+			pamUser:       "pam-preset-user-with-unset-try-first-pass",
+			firstPass:     TryFirstPass,
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Secret{
+							Secret: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+			wantAuthTok:    "gdm-good-password",
+		},
+		"Authenticated_with_password_sets_PAM_AUTHTOK": {
+			clientOptions: slices.Clone(firstPassClientOptions),
+			pamUser:       "pam-preset-user-setting-authtok",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Secret{
+							Secret: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+			wantAuthTok:    "gdm-good-password",
+		},
+		"Authenticated_with_X25519_encryption_and_server_side_broker_and_authMode_selection": {
+			clientOptions: append(slices.Clone(singleBrokerClientOptions),
+				pam_test.WithX25519Encryption(),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantSecret("gdm-good-password")),
+			pamUser: "pam-preset-user-and-daemon-selected-broker",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Secret{
+							Secret: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
 		"Authenticated_with_preset_PAM_user_using_legacy_challenge_and_server_side_broker_and_authMode_selection": {
 			clientOptions: append(slices.Clone(singleBrokerClientOptions),
 				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
@@ -354,6 +563,46 @@ func TestGdmModel(t *testing.T) {
 			wantStage:      pam_proto.Stage_challenge,
 			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
 		},
+		"Authenticated_on_fallback_broker_with_preset_PAM_user_and_server_side_broker_selection": {
+			clientOptions: append(slices.Clone(multiBrokerClientOptions),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithSelectBrokerFallback(secondBrokerInfo.Id),
+				pam_test.WithIsAuthenticatedWantSecret("gdm-good-password"),
+			),
+			pamUser: "pam-preset-user-and-daemon-selected-broker",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Secret{
+							Secret: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_authEvent,
+				gdm.EventType_startAuthentication,
+			},
+			wantGdmInfo: []string{
+				fmt.Sprintf("%s is unavailable, using %s instead", firstBrokerInfo.Name, secondBrokerInfo.Name),
+			},
+			wantStage:      pam_proto.Stage_challenge,
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantExitStatus: PamSuccess{BrokerID: secondBrokerInfo.Id},
+		},
 		"Authenticated_with_message_with_preset_PAM_user_and_server_side_broker_and_authMode_selection": {
 			clientOptions: append(slices.Clone(multiBrokerClientOptions),
 				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
@@ -399,6 +648,215 @@ func TestGdmModel(t *testing.T) {
 				msg:      "Hi GDM, it's a pleasure to get you in!",
 			},
 		},
+		"Authenticated_with_choice_after_server_side_broker_and_authMode_selection": {
+			clientOptions: []pam_test.DummyClientOptions{
+				pam_test.WithIgnoreSessionIDChecks(),
+				pam_test.WithAvailableBrokers([]*authd.ABResponse_BrokerInfo{
+					firstBrokerInfo,
+				}, nil),
+				pam_test.WithUILayout(layouts.Choice, "Phone selection", pam_test.ChoiceUILayout(
+					&authd.UILayout_Choice{Id: "phone1", Label: "Work phone"},
+					&authd.UILayout_Choice{Id: "phone2", Label: "Personal phone"},
+				)),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantChoice("phone2"),
+			},
+			pamUser: "pam-preset-user-and-daemon-selected-broker",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Choice{
+							Choice: "phone2",
+						}}),
+					},
+				},
+			},
+			supportedLayouts:   []*authd.UILayout{pam_test.ChoiceUILayout()},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> choice
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantStage: pam_proto.Stage_challenge,
+			wantGdmAuthRes: []*authd.IAResponse{{
+				Access: auth.Granted,
+			}},
+			wantExitStatus: PamSuccess{
+				BrokerID: firstBrokerInfo.Id,
+			},
+		},
+		"Authenticated_with_webauthn_after_server_side_broker_and_authMode_selection": {
+			clientOptions: []pam_test.DummyClientOptions{
+				pam_test.WithIgnoreSessionIDChecks(),
+				pam_test.WithAvailableBrokers([]*authd.ABResponse_BrokerInfo{
+					firstBrokerInfo,
+				}, nil),
+				pam_test.WithUILayout(layouts.WebAuthn, "Security key", pam_test.WebAuthnUILayout(
+					pam_test.WithWebAuthnChallenge([]byte("gdm-challenge"), "authd.example.com",
+						[]byte("credential1"), []byte("credential2")),
+				)),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantWebAuthn(gdmTestWebAuthnAuthenticator.PublicKey(),
+					"authd.example.com", []byte("gdm-challenge")),
+			},
+			pamUser: "pam-preset-user-and-daemon-selected-broker",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Webauthn{}}),
+					},
+				},
+			},
+			supportedLayouts:   []*authd.UILayout{pam_test.WebAuthnUILayout()},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> webauthn
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_webAuthnTouchRequested,
+				gdm.EventType_authEvent,
+			},
+			wantStage: pam_proto.Stage_challenge,
+			wantGdmAuthRes: []*authd.IAResponse{{
+				Access: auth.Granted,
+			}},
+			wantExitStatus: PamSuccess{
+				BrokerID: firstBrokerInfo.Id,
+			},
+		},
+		"Authenticated_with_progress_messages_after_server_side_broker_and_authMode_selection": {
+			clientOptions: []pam_test.DummyClientOptions{
+				pam_test.WithIgnoreSessionIDChecks(),
+				pam_test.WithAvailableBrokers([]*authd.ABResponse_BrokerInfo{
+					firstBrokerInfo,
+				}, nil),
+				pam_test.WithUILayout(layouts.Choice, "Phone selection", pam_test.ChoiceUILayout(
+					&authd.UILayout_Choice{Id: "phone1", Label: "Work phone"},
+				)),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantChoice("phone1"),
+				pam_test.WithIsAuthenticatedProgress("Push sent to your phone", "Number to match: 42"),
+			},
+			pamUser: "pam-preset-user-and-daemon-selected-broker",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Choice{
+							Choice: "phone1",
+						}}),
+					},
+				},
+			},
+			supportedLayouts:   []*authd.UILayout{pam_test.ChoiceUILayout()},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> choice
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authenticationProgress,
+				gdm.EventType_authenticationProgress,
+				gdm.EventType_authEvent,
+			},
+			wantStage: pam_proto.Stage_challenge,
+			wantGdmAuthRes: []*authd.IAResponse{{
+				Access: auth.Granted,
+			}},
+			wantGdmProgress: []string{"Push sent to your phone", "Number to match: 42"},
+			wantExitStatus: PamSuccess{
+				BrokerID: firstBrokerInfo.Id,
+			},
+		},
+		"Authenticated_with_multiple_fields_after_server_side_broker_and_authMode_selection": {
+			clientOptions: []pam_test.DummyClientOptions{
+				pam_test.WithIgnoreSessionIDChecks(),
+				pam_test.WithAvailableBrokers([]*authd.ABResponse_BrokerInfo{
+					firstBrokerInfo,
+				}, nil),
+				pam_test.WithUILayout(passwordUILayoutID, "Username and PIN", pam_test.FormUILayout(
+					pam_test.WithFields(
+						&authd.UILayout_Field{Id: "username", Label: "Username", Entry: entries.Chars},
+						&authd.UILayout_Field{Id: "pin", Label: "PIN", Entry: entries.CharsPassword},
+					),
+				)),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantFields(map[string]string{
+					"username": "gdm-user",
+					"pin":      "gdm-good-pin",
+				}),
+			},
+			pamUser: "pam-preset-user-and-daemon-selected-broker",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Fields{
+							Fields: &authd.IARequest_AuthenticationData_FieldSecrets{
+								Secrets: map[string]string{
+									"username": "gdm-user",
+									"pin":      "gdm-good-pin",
+								},
+							},
+						}}),
+					},
+				},
+			},
+			supportedLayouts:   []*authd.UILayout{pam_test.FormUILayout(pam_test.WithSupportsFields())},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> form
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantStage: pam_proto.Stage_challenge,
+			wantGdmAuthRes: []*authd.IAResponse{{
+				Access: auth.Granted,
+			}},
+			wantExitStatus: PamSuccess{
+				BrokerID: firstBrokerInfo.Id,
+			},
+		},
 		"New_password_changed_after_server_side_broker_and_authMode_selection": {
 			clientOptions: append(slices.Clone(singleBrokerNewPasswordClientOptions),
 				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
@@ -434,7 +892,8 @@ func TestGdmModel(t *testing.T) {
 				gdm.EventType_startAuthentication,
 				gdm.EventType_authEvent,
 			},
-			wantStage: pam_proto.Stage_challenge,
+			wantAuthTok: "gdm-good-password",
+			wantStage:   pam_proto.Stage_challenge,
 			wantGdmAuthRes: []*authd.IAResponse{{
 				Access: auth.Granted,
 			}},
@@ -478,7 +937,8 @@ func TestGdmModel(t *testing.T) {
 				gdm.EventType_startAuthentication,
 				gdm.EventType_authEvent,
 			},
-			wantStage: pam_proto.Stage_challenge,
+			wantAuthTok: "gdm-good-password",
+			wantStage:   pam_proto.Stage_challenge,
 			wantGdmAuthRes: []*authd.IAResponse{{
 				Access: auth.Granted,
 				Msg:    "Hi GDM, it's a pleasure to change your password!",
@@ -2113,6 +2573,63 @@ func TestGdmModel(t *testing.T) {
 				msg:    "you're not allowed!",
 			},
 		},
+		"Error_on_use_first_pass_with_wrong_password": {
+			clientOptions:      slices.Clone(firstPassClientOptions),
+			pamUser:            "pam-preset-user-with-wrong-use-first-pass",
+			firstPass:          UseFirstPass,
+			pamAuthTok:         "gdm-wrong-password",
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+			},
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantStage: gdmTestIgnoreStage,
+			wantExitStatus: pamError{
+				status: pam.ErrAuth,
+				msg:    "Access denied",
+			},
+		},
+		"Error_on_use_first_pass_with_wrong_password_even_if_broker_allows_retries": {
+			clientOptions: append(slices.Clone(firstPassClientOptions),
+				pam_test.WithIsAuthenticatedMaxRetries(1),
+			),
+			pamUser:            "pam-preset-user-with-retried-use-first-pass",
+			firstPass:          UseFirstPass,
+			pamAuthTok:         "gdm-wrong-password",
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantStage: gdmTestIgnoreStage,
+			wantExitStatus: pamError{
+				status: pam.ErrAuth,
+				msg:    "Access denied",
+			},
+		},
+		"Error_on_use_first_pass_without_password": {
+			clientOptions:      slices.Clone(firstPassClientOptions),
+			pamUser:            "pam-preset-user-with-unset-use-first-pass",
+			firstPass:          UseFirstPass,
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantStage: gdmTestIgnoreStage,
+			wantExitStatus: pamError{
+				status: pam.ErrAuth,
+				msg:    "no password set by a previous module",
+			},
+		},
 		"Error_on_authentication_client_denied_because_of_wrong_password": {
 			clientOptions: append(slices.Clone(singleBrokerClientOptions),
 				pam_test.WithIsAuthenticatedWantSecret("gdm-good-password"),
@@ -2471,7 +2988,8 @@ func TestGdmModel(t *testing.T) {
 
 			var exitStatus PamReturnStatus
 			uiModel := newUIModelForClients(pam_test.NewModuleTransactionDummy(gdmHandler),
-				Gdm, authd.SessionMode_LOGIN, tc.client, nil, &exitStatus)
+				Gdm, authd.SessionMode_LOGIN, "C", tc.client, nil, &exitStatus)
+			uiModel.firstPass = tc.firstPass
 
 			appState := gdmTestUIModel{
 				uiModel:             uiModel,
@@ -2493,6 +3011,9 @@ func TestGdmModel(t *testing.T) {
 			if tc.pamUser != "" {
 				require.NoError(t, uiModel.pamMTx.SetItem(pam.User, tc.pamUser))
 			}
+			if tc.pamAuthTok != "" {
+				require.NoError(t, uiModel.pamMTx.SetItem(pam.Authtok, tc.pamAuthTok))
+			}
 			if tc.pamUser != "" && tc.wantUsername == "" {
 				tc.wantUsername = tc.pamUser
 			}
@@ -2670,6 +3191,12 @@ func TestGdmModel(t *testing.T) {
 
 			gdm_test.RequireEqualData(t, tc.wantGdmAuthRes, gdmHandler.authEvents)
 
+			authTok, err := appState.pamMTx.GetItem(pam.Authtok)
+			require.NoError(t, err, "Failed to get the PAM auth token")
+			require.Equal(t, cmp.Or(tc.wantAuthTok, tc.pamAuthTok), authTok, "PAM auth token does not match")
+			require.Equal(t, tc.wantGdmProgress, gdmHandler.progressMessages, "Progress messages do not match")
+			require.Equal(t, tc.wantGdmInfo, gdmHandler.infoMessages, "Info messages do not match")
+
 			if r, ok := tc.wantExitStatus.(PamReturnError); ok {
 				// If the model exited with error and that matches, we don't
 				// care much comparing all the expectations, since the final exit status
@@ -2706,6 +3233,11 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		panic(fmt.Sprintf("could not create an valid rsa key: %v", err))
 	}
+	gdmTestWebAuthnAuthenticator, err = pam_test.NewSoftwareAuthenticator([]byte("credential1"), []byte("gdm-user"))
+	if err != nil {
+		panic(fmt.Sprintf("could not create a webauthn authenticator: %v", err))
+	}
+	localAuthenticator = gdmTestWebAuthnAuthenticator
 	defer pam_test.MaybeDoLeakCheck()
 
 	m.Run()
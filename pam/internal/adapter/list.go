@@ -36,7 +36,7 @@ type listItemSelected struct {
 	item tea_list.Item
 }
 
-// itemLayout is the rendering delegation of [brokerItem] and [authModeItem].
+// itemLayout is the rendering delegation of [brokerItem], [authModeItem] and [choiceItem].
 type itemLayout struct{}
 
 // Height returns height of the items.
@@ -56,6 +56,8 @@ func (d itemLayout) Render(w io.Writer, m tea_list.Model, index int, item tea_li
 		label = item.name
 	case authModeItem:
 		label = item.label
+	case choiceItem:
+		label = item.label
 	default:
 		log.Errorf(context.TODO(), "Unexpected item element type: %t", item)
 		return
@@ -3,9 +3,6 @@ package adapter
 
 import (
 	"context"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -13,7 +10,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/proto"
@@ -47,9 +46,11 @@ var (
 
 // sessionInfo contains the global broker session information.
 type sessionInfo struct {
-	brokerID      string
-	sessionID     string
-	encryptionKey *rsa.PublicKey
+	brokerID  string
+	sessionID string
+	encrypter encryption.Encrypter
+	// password is the password the user authenticated with, to be set as PAM_AUTHTOK.
+	password string
 }
 
 // uiModel is the global models orchestrator.
@@ -62,12 +63,21 @@ type uiModel struct {
 	clientType PamClientType
 	// sessionMode is the mode of the session invoked by the module.
 	sessionMode authd.SessionMode
+	// lang is the language of the messages shown to the user.
+	lang string
 
 	// client is the [authd.PAMClient] handle used to communicate with authd.
 	client authd.PAMClient
 
+	// firstPass is how the password set in PAM_AUTHTOK by a previous module is used.
+	firstPass FirstPassPolicy
+	// firstPassTried is set once the password set in PAM_AUTHTOK has been sent.
+	firstPassTried bool
+
 	sessionStartingForBroker string
 	currentSession           *sessionInfo
+	// notice is an information message shown to the user in the interactive terminal.
+	notice string
 
 	healthCheckCancel      func()
 	userSelectionModel     userSelectionModel
@@ -97,9 +107,14 @@ type BrokerSelected struct {
 
 // SessionStarted signals that we started a session with a given broker.
 type SessionStarted struct {
-	brokerID      string
-	sessionID     string
-	encryptionKey string
+	brokerID string
+	// unavailableBrokerID is the ID of the selected broker, if the session was started on a fallback broker instead.
+	unavailableBrokerID string
+	sessionID           string
+	// brokerSessionID is the ID of the session known by the broker, which the secrets are bound to.
+	brokerSessionID     string
+	encryptionKey       string
+	encryptionAlgorithm authd.EncryptionAlgorithm
 }
 
 // GetAuthenticationModesRequested signals that a model needs to get the broker authentication modes.
@@ -127,23 +142,25 @@ type ChangeStage struct {
 type StageChanged ChangeStage
 
 // NewUIModel creates and initializes the main model orchestrator.
-func NewUIModel(mTx pam.ModuleTransaction, clientType PamClientType, mode authd.SessionMode, conn *grpc.ClientConn, exitStatus *PamReturnStatus) tea.Model {
+func NewUIModel(mTx pam.ModuleTransaction, clientType PamClientType, mode authd.SessionMode, lang string, conn *grpc.ClientConn, firstPass FirstPassPolicy, exitStatus *PamReturnStatus) tea.Model {
 	var userServiceClient authd.UserServiceClient
 	if conn != nil && isSSHSession(mTx) {
 		userServiceClient = authd.NewUserServiceClient(conn)
 	}
 
-	m := newUIModelForClients(mTx, clientType, mode, authd.NewPAMClient(conn), userServiceClient, exitStatus)
+	m := newUIModelForClients(mTx, clientType, mode, lang, authd.NewPAMClient(conn), userServiceClient, exitStatus)
 	m.conn = conn
+	m.firstPass = firstPass
 	return m
 }
 
 // newUIModelForClients is the internal implementation of [NewUIModel] for testing purposes.
-func newUIModelForClients(mTx pam.ModuleTransaction, clientType PamClientType, mode authd.SessionMode, pamClient authd.PAMClient, userServiceClient authd.UserServiceClient, exitStatus *PamReturnStatus) uiModel {
+func newUIModelForClients(mTx pam.ModuleTransaction, clientType PamClientType, mode authd.SessionMode, lang string, pamClient authd.PAMClient, userServiceClient authd.UserServiceClient, exitStatus *PamReturnStatus) uiModel {
 	m := uiModel{
 		pamMTx:      mTx,
 		clientType:  clientType,
 		sessionMode: mode,
+		lang:        lang,
 		exitStatus:  exitStatus,
 		client:      pamClient,
 	}
@@ -156,13 +173,13 @@ func newUIModelForClients(mTx pam.ModuleTransaction, clientType PamClientType, m
 	case Gdm:
 		m.gdmModel = gdmModel{pamMTx: m.pamMTx}
 	case Native:
-		m.nativeModel = newNativeModel(m.pamMTx, userServiceClient)
+		m.nativeModel = newNativeModel(m.pamMTx, userServiceClient, m.lang)
 	}
 
-	m.userSelectionModel = newUserSelectionModel(m.pamMTx, m.clientType)
-	m.brokerSelectionModel = newBrokerSelectionModel(m.client, m.clientType)
+	m.userSelectionModel = newUserSelectionModel(m.pamMTx, m.clientType, m.lang)
+	m.brokerSelectionModel = newBrokerSelectionModel(m.pamMTx, m.client, m.clientType)
 	m.authModeSelectionModel = newAuthModeSelectionModel(m.clientType)
-	m.authenticationModel = newAuthenticationModel(m.client, m.clientType)
+	m.authenticationModel = newAuthenticationModel(m.client, m.clientType, m.lang)
 	m.healthCheckCancel = func() {}
 
 	return m
@@ -267,6 +284,9 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		*m.exitStatus = msg
+		if _, ok := msg.(PamSuccess); ok {
+			m.setAuthTok()
+		}
 		return m, m.quit()
 
 	// Events
@@ -290,7 +310,7 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		safeMessageDebug(msg)
 		if m.sessionStartingForBroker == "" {
 			m.sessionStartingForBroker = msg.BrokerID
-			return m, startBrokerSession(m.client, msg.BrokerID, m.username(), m.sessionMode)
+			return m, startBrokerSession(m.pamMTx, m.client, msg.BrokerID, m.username(), m.lang, m.sessionMode)
 		}
 		if m.sessionStartingForBroker != msg.BrokerID {
 			return m, tea.Sequence(endSession(m.client, m.currentSession), sendEvent(msg))
@@ -298,34 +318,17 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case SessionStarted:
 		safeMessageDebug(msg)
 		m.sessionStartingForBroker = ""
-		pubASN1, err := base64.StdEncoding.DecodeString(msg.encryptionKey)
-		if err != nil {
-			return m, sendEvent(pamError{
-				status: pam.ErrSystem,
-				msg:    fmt.Sprintf("encryption key sent by broker is not a valid base64 encoded string: %v", err),
-			})
-		}
-
-		pubKey, err := x509.ParsePKIXPublicKey(pubASN1)
+		encrypter, err := encryption.NewEncrypter(msg.encryptionAlgorithm, msg.encryptionKey, msg.brokerSessionID)
 		if err != nil {
-			return m, sendEvent(pamError{
-				status: pam.ErrSystem,
-				msg:    fmt.Sprintf("encryption key send by broker is not valid: %v", err),
-			})
-		}
-		rsaPublicKey, ok := pubKey.(*rsa.PublicKey)
-		if !ok {
-			return m, sendEvent(pamError{
-				status: pam.ErrSystem,
-				msg:    fmt.Sprintf("expected encryption key sent by broker to be  RSA public key, got %T", pubKey),
-			})
+			return m, sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 		}
 
 		m.currentSession = &sessionInfo{
-			brokerID:      msg.brokerID,
-			sessionID:     msg.sessionID,
-			encryptionKey: rsaPublicKey,
+			brokerID:  msg.brokerID,
+			sessionID: msg.sessionID,
+			encrypter: encrypter,
 		}
+		m.notifyFallbackBroker(msg)
 		return m, sendEvent(GetAuthenticationModesRequested{})
 
 	case ChangeStage:
@@ -371,17 +374,29 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.currentSession == nil {
 			return m, nil
 		}
+		if m.canTryFirstPass(msg.layout) {
+			m.firstPassTried = true
+			return m, m.tryFirstPass(msg.layout)
+		}
 
 		return m, tea.Sequence(
 			m.authenticationModel.Compose(
 				m.currentSession.brokerID,
 				m.currentSession.sessionID,
-				m.currentSession.encryptionKey,
+				m.currentSession.encrypter,
 				msg.layout,
 			),
 			m.updateClientModel(msg),
 		)
 
+	case firstPassResultReceived:
+		safeMessageDebug(msg)
+		return m, m.handleFirstPassResult(msg)
+
+	case isAuthenticatedResultReceived:
+		// The authentication model handles the result, we only keep the password to set as PAM_AUTHTOK.
+		m.keepPassword(msg)
+
 	case SessionEnded:
 		safeMessageDebug(msg)
 		m.sessionStartingForBroker = ""
@@ -441,9 +456,14 @@ func (m uiModel) View() string {
 		view.WriteString(debug)
 	}
 
+	if view.Len() > 0 && m.notice != "" {
+		view.WriteString("\n")
+		view.WriteString(infoMsgStyle.Render(m.notice))
+	}
+
 	if view.Len() > 0 && m.canGoBack() {
 		infoMessage := infoMsgStyle.Render(fmt.Sprintf("Press escape key to %s",
-			goBackLabel(m.previousStage())))
+			goBackLabel(m.lang, m.previousStage())))
 		return lipgloss.JoinVertical(lipgloss.Left, view.String(), infoMessage)
 	}
 
@@ -557,6 +577,34 @@ func (m uiModel) username() string {
 	return m.userSelectionModel.Username()
 }
 
+// brokerName returns the name of the broker with the given ID, or the ID itself if the broker is unknown.
+func (m uiModel) brokerName(brokerID string) string {
+	for _, b := range m.availableBrokers() {
+		if b.GetId() == brokerID {
+			return b.GetName()
+		}
+	}
+	return brokerID
+}
+
+// notifyFallbackBroker tells the user when the session was started on a fallback broker.
+func (m *uiModel) notifyFallbackBroker(msg SessionStarted) {
+	m.notice = ""
+	if msg.unavailableBrokerID == "" {
+		return
+	}
+
+	notice := i18n.Sprintf(m.lang, "%s is unavailable, using %s instead",
+		m.brokerName(msg.unavailableBrokerID), m.brokerName(msg.brokerID))
+	if m.clientType == InteractiveTerminal {
+		m.notice = notice
+		return
+	}
+	if _, err := m.pamMTx.StartStringConv(pam.TextInfo, notice); err != nil {
+		log.Warningf(context.TODO(), "Impossible to show fallback broker message: %v", err)
+	}
+}
+
 // availableBrokers returns currently available brokers.
 func (m uiModel) availableBrokers() []*authd.ABResponse_BrokerInfo {
 	return m.brokerSelectionModel.availableBrokers
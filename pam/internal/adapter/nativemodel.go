@@ -17,6 +17,7 @@ import (
 	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/proto"
@@ -33,6 +34,7 @@ type nativeModel struct {
 	uiLayout         *authd.UILayout
 
 	serviceName          string
+	lang                 string
 	interactive          bool
 	currentStage         proto.Stage
 	busy                 bool
@@ -77,8 +79,8 @@ var errGoBack = errors.New("request to go back")
 var errEmptyResponse = errors.New("empty response received")
 var errNotAnInteger = errors.New("parsed value is not an integer")
 
-func newNativeModel(mTx pam.ModuleTransaction, userServiceClient authd.UserServiceClient) nativeModel {
-	m := nativeModel{pamMTx: mTx, userServiceClient: userServiceClient}
+func newNativeModel(mTx pam.ModuleTransaction, userServiceClient authd.UserServiceClient, lang string) nativeModel {
+	m := nativeModel{pamMTx: mTx, userServiceClient: userServiceClient, lang: lang}
 
 	var err error
 	m.serviceName, err = m.pamMTx.GetItem(pam.Service)
@@ -104,15 +106,17 @@ func (m nativeModel) Init() tea.Cmd {
 			entries.Digits,
 			entries.DigitsPassword,
 		)
+		supportsFields := true
 
 		supportedLayouts := supportedUILayoutsReceived{
 			layouts: []*authd.UILayout{
 				{
-					Type:   layouts.Form,
-					Label:  &required,
-					Entry:  &supportedEntries,
-					Wait:   &layouts.OptionalWithBooleans,
-					Button: &optional,
+					Type:           layouts.Form,
+					Label:          &required,
+					Entry:          &supportedEntries,
+					Wait:           &layouts.OptionalWithBooleans,
+					Button:         &optional,
+					SupportsFields: &supportsFields,
 				},
 				{
 					Type:   layouts.NewPassword,
@@ -120,6 +124,17 @@ func (m nativeModel) Init() tea.Cmd {
 					Entry:  &supportedEntries,
 					Button: &optional,
 				},
+				{
+					Type:  layouts.Choice,
+					Label: &optional,
+				},
+				{
+					Type:        layouts.WebAuthn,
+					Label:       &optional,
+					Challenge:   &required,
+					RpId:        &required,
+					Credentials: &optional,
+				},
 			},
 		}
 
@@ -307,6 +322,12 @@ func (m nativeModel) Update(msg tea.Msg) (nativeModel, tea.Cmd) {
 		}
 		return m.startAsyncOp(m.startChallenge)
 
+	case webAuthnTouchRequested:
+		return m, maybeSendPamError(m.sendInfo(i18n.Translate(m.lang, "Touch your security key")))
+
+	case isAuthenticatedProgressReceived:
+		return m, maybeSendPamError(m.sendInfo(msg.msg))
+
 	case newPasswordCheckResult:
 		if msg.msg != "" {
 			if cmd := maybeSendPamError(m.sendError(msg.msg)); cmd != nil {
@@ -394,7 +415,7 @@ func (m nativeModel) promptForNumericInputUntilValid(style pam.Style, prompt str
 		return value, err
 	}
 
-	err = m.sendError("Unsupported input")
+	err = m.sendError(i18n.Translate(m.lang, "Unsupported input"))
 	if err != nil {
 		return -1, err
 	}
@@ -442,7 +463,7 @@ func (m nativeModel) promptForChoiceWithMessage(title string, message string, ch
 	}
 
 	if goBackLabel := m.goBackActionLabel(); goBackLabel != "" {
-		msg += fmt.Sprintf("\nOr enter '%s' to %s", nativeCancelKey, goBackLabel)
+		msg += "\n" + i18n.Sprintf(m.lang, "Or enter '%s' to %s", nativeCancelKey, goBackLabel)
 	}
 
 	for {
@@ -456,7 +477,7 @@ func (m nativeModel) promptForChoiceWithMessage(title string, message string, ch
 		// TODO: Maybe add support for default selection...
 
 		if idx < 1 || idx > len(choices) {
-			if err := m.sendError("Invalid selection"); err != nil {
+			if err := m.sendError(i18n.Translate(m.lang, "Invalid selection")); err != nil {
 				return "", err
 			}
 			continue
@@ -482,7 +503,7 @@ func (m nativeModel) startAsyncOp(cmd func() tea.Cmd) (nativeModel, tea.Cmd) {
 }
 
 func (m nativeModel) userSelection() tea.Cmd {
-	user, err := m.promptForInput(pam.PromptEchoOn, inputPromptStyleInline, "Username")
+	user, err := m.promptForInput(pam.PromptEchoOn, inputPromptStyleInline, i18n.Translate(m.lang, "Username"))
 	if errors.Is(err, errEmptyResponse) {
 		return sendEvent(nativeUserSelection{})
 	}
@@ -519,7 +540,7 @@ func (m nativeModel) brokerSelection() tea.Cmd {
 		choices = append(choices, choicePair{id: b.Id, label: b.Name})
 	}
 
-	id, err := m.promptForChoice("Provider selection", choices, "Choose your provider")
+	id, err := m.promptForChoice(i18n.Translate(m.lang, "Provider selection"), choices, i18n.Translate(m.lang, "Choose your provider"))
 	if errors.Is(err, errGoBack) {
 		return sendEvent(nativeGoBack{})
 	}
@@ -538,8 +559,8 @@ func (m nativeModel) authModeSelection() tea.Cmd {
 		choices = append(choices, choicePair{id: am.Id, label: am.Label})
 	}
 
-	id, err := m.promptForChoice("Authentication method selection", choices,
-		"Choose your authentication method")
+	id, err := m.promptForChoice(i18n.Translate(m.lang, "Authentication method selection"), choices,
+		i18n.Translate(m.lang, "Choose your authentication method"))
 	if errors.Is(err, errGoBack) {
 		return sendEvent(nativeGoBack{})
 	}
@@ -582,6 +603,12 @@ func (m nativeModel) startChallenge() tea.Cmd {
 	case layouts.NewPassword:
 		return m.handleNewPassword()
 
+	case layouts.Choice:
+		return m.handleChoice()
+
+	case layouts.WebAuthn:
+		return m.handleWebAuthn()
+
 	default:
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
@@ -601,17 +628,17 @@ func (m nativeModel) selectedAuthModeLabel(fallback string) string {
 }
 
 func (m nativeModel) handleFormChallenge(hasWait bool) tea.Cmd {
-	authMode := m.selectedAuthModeLabel("Authentication")
+	authMode := m.selectedAuthModeLabel(i18n.Translate(m.lang, "Authentication"))
 
 	if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 		choices := []choicePair{
-			{id: "continue", label: fmt.Sprintf("Proceed with %s", authMode)},
+			{id: "continue", label: i18n.Sprintf(m.lang, "Proceed with %s", authMode)},
 		}
 		if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 			choices = append(choices, choicePair{id: layouts.Button, label: buttonLabel})
 		}
 
-		id, err := m.promptForChoice(authMode, choices, "Choose action")
+		id, err := m.promptForChoice(authMode, choices, i18n.Translate(m.lang, "Choose action"))
 		if errors.Is(err, errGoBack) {
 			return sendEvent(nativeGoBack{})
 		}
@@ -626,6 +653,10 @@ func (m nativeModel) handleFormChallenge(hasWait bool) tea.Cmd {
 		}
 	}
 
+	if len(m.uiLayout.GetFields()) > 0 {
+		return m.handleFormFieldsChallenge(authMode)
+	}
+
 	var prompt string
 	if m.uiLayout.Label != nil {
 		prompt = strings.TrimSuffix(*m.uiLayout.Label, ":")
@@ -639,18 +670,18 @@ func (m nativeModel) handleFormChallenge(hasWait bool) tea.Cmd {
 
 	var instructions string
 	if m.canGoBack() {
-		instructions = "Enter '%[1]s' to cancel the request and %[2]s"
+		instructions = i18n.Translate(m.lang, "Enter '%[1]s' to cancel the request and %[2]s")
 	}
 
 	if hasWait {
 		// Duplicating some contents here, as it will be better for translators once we've them
-		instructions = "Leave the input field empty to wait for the alternative authentication method"
+		instructions = i18n.Translate(m.lang, "Leave the input field empty to wait for the alternative authentication method")
 		if m.uiLayout.GetEntry() == "" {
-			instructions = "Press Enter to wait for authentication"
+			instructions = i18n.Translate(m.lang, "Press Enter to wait for authentication")
 		}
 
 		if m.canGoBack() {
-			instructions += " or enter '%[1]s' to %[2]s"
+			instructions += i18n.Translate(m.lang, " or enter '%[1]s' to %[2]s")
 		}
 	}
 
@@ -680,8 +711,47 @@ func (m nativeModel) handleFormChallenge(hasWait bool) tea.Cmd {
 	})
 }
 
+func (m nativeModel) handleFormFieldsChallenge(authMode string) tea.Cmd {
+	var instructions string
+	if goBackLabel := m.goBackActionLabel(); goBackLabel != "" {
+		instructions = fmt.Sprintf("\nEnter '%[1]s' to cancel the request and %[2]s",
+			nativeCancelKey, goBackLabel)
+	}
+	if label := m.uiLayout.GetLabel(); label != "" {
+		instructions = "\n" + label + instructions
+	}
+	if cmd := maybeSendPamError(m.sendInfo("== %s ==%s", authMode, instructions)); cmd != nil {
+		return cmd
+	}
+
+	secrets := make(map[string]string, len(m.uiLayout.GetFields()))
+	for _, field := range m.uiLayout.GetFields() {
+		value, err := m.promptForEntry(field.GetEntry(), strings.TrimSuffix(field.GetLabel(), ":"))
+		if errors.Is(err, errGoBack) {
+			return sendEvent(nativeGoBack{})
+		}
+		if errors.Is(err, errEmptyResponse) {
+			return sendEvent(nativeChallengeRequested{})
+		}
+		if err != nil {
+			return maybeSendPamError(err)
+		}
+		secrets[field.GetId()] = value
+	}
+
+	return sendEvent(isAuthenticatedRequested{
+		item: &authd.IARequest_AuthenticationData_Fields{
+			Fields: &authd.IARequest_AuthenticationData_FieldSecrets{Secrets: secrets},
+		},
+	})
+}
+
 func (m nativeModel) promptForSecret(prompt string) (string, error) {
-	switch m.uiLayout.GetEntry() {
+	return m.promptForEntry(m.uiLayout.GetEntry(), prompt)
+}
+
+func (m nativeModel) promptForEntry(entry string, prompt string) (string, error) {
+	switch entry {
 	case entries.Chars, "":
 		return m.promptForInput(pam.PromptEchoOn, inputPromptStyleMultiLine, prompt)
 	case entries.CharsPassword:
@@ -691,7 +761,7 @@ func (m nativeModel) promptForSecret(prompt string) (string, error) {
 	case entries.DigitsPassword:
 		return m.promptForNumericInputAsString(pam.PromptEchoOff, prompt)
 	default:
-		return "", fmt.Errorf("Unhandled entry %q", m.uiLayout.GetEntry())
+		return "", fmt.Errorf("Unhandled entry %q", entry)
 	}
 }
 
@@ -743,14 +813,14 @@ func (m nativeModel) handleQrCode() tea.Cmd {
 	qrcodeView = append(qrcodeView, " ")
 
 	choices := []choicePair{
-		{id: layouts.Wait, label: "Wait for authentication result"},
+		{id: layouts.Wait, label: i18n.Translate(m.lang, "Wait for authentication result")},
 	}
 	if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 		choices = append(choices, choicePair{id: layouts.Button, label: buttonLabel})
 	}
 
-	id, err := m.promptForChoiceWithMessage(m.selectedAuthModeLabel("QR code"),
-		strings.Join(qrcodeView, "\n"), choices, "Choose action")
+	id, err := m.promptForChoiceWithMessage(m.selectedAuthModeLabel(i18n.Translate(m.lang, "QR code")),
+		strings.Join(qrcodeView, "\n"), choices, i18n.Translate(m.lang, "Choose action"))
 	if errors.Is(err, errGoBack) {
 		return sendEvent(nativeGoBack{})
 	}
@@ -797,14 +867,14 @@ func centerString(s string, reference string) string {
 func (m nativeModel) handleNewPassword() tea.Cmd {
 	if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 		choices := []choicePair{
-			{id: "continue", label: "Proceed with password update"},
+			{id: "continue", label: i18n.Translate(m.lang, "Proceed with password update")},
 		}
 		if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 			choices = append(choices, choicePair{id: layouts.Button, label: buttonLabel})
 		}
 
-		label := m.selectedAuthModeLabel("Password Update")
-		id, err := m.promptForChoice(label, choices, "Choose action")
+		label := m.selectedAuthModeLabel(i18n.Translate(m.lang, "Password Update"))
+		id, err := m.promptForChoice(label, choices, i18n.Translate(m.lang, "Choose action"))
 		if errors.Is(err, errGoBack) {
 			return sendEvent(nativeGoBack{})
 		}
@@ -824,14 +894,61 @@ func (m nativeModel) handleNewPassword() tea.Cmd {
 	return m.newPasswordChallenge(nil)
 }
 
+func (m nativeModel) handleChoice() tea.Cmd {
+	var choices []choicePair
+	for _, c := range m.uiLayout.GetChoices() {
+		choices = append(choices, choicePair{id: c.GetId(), label: c.GetLabel()})
+	}
+
+	title := m.selectedAuthModeLabel(i18n.Translate(m.lang, "Selection"))
+	id, err := m.promptForChoiceWithMessage(title, m.uiLayout.GetLabel(), choices, i18n.Translate(m.lang, "Choose an option"))
+	if errors.Is(err, errGoBack) {
+		return sendEvent(nativeGoBack{})
+	}
+	if errors.Is(err, errEmptyResponse) {
+		return sendEvent(nativeChallengeRequested{})
+	}
+	if err != nil {
+		return maybeSendPamError(err)
+	}
+
+	return sendEvent(isAuthenticatedRequested{
+		item: &authd.IARequest_AuthenticationData_Choice{Choice: id},
+	})
+}
+
+func (m nativeModel) handleWebAuthn() tea.Cmd {
+	instructions := "\n" + i18n.Translate(m.lang, "Connect your security key and press Enter")
+	if label := m.uiLayout.GetLabel(); label != "" {
+		instructions = "\n" + label + instructions
+	}
+	if goBackLabel := m.goBackActionLabel(); goBackLabel != "" {
+		instructions += i18n.Sprintf(m.lang, " or enter '%[1]s' to %[2]s", nativeCancelKey, goBackLabel)
+	}
+	title := m.selectedAuthModeLabel(i18n.Translate(m.lang, "Security key"))
+	if cmd := maybeSendPamError(m.sendInfo("== %s ==%s", title, instructions)); cmd != nil {
+		return cmd
+	}
+
+	_, err := m.promptForInput(pam.PromptEchoOn, inputPromptStyleInline, i18n.Translate(m.lang, "Security key"))
+	if errors.Is(err, errGoBack) {
+		return sendEvent(nativeGoBack{})
+	}
+	if err != nil && !errors.Is(err, errEmptyResponse) {
+		return maybeSendPamError(err)
+	}
+
+	return requestWebAuthnAssertion()
+}
+
 func (m nativeModel) newPasswordChallenge(previousPassword *string) tea.Cmd {
 	if previousPassword == nil {
 		var instructions string
 		if goBackLabel := m.goBackActionLabel(); goBackLabel != "" {
-			instructions = fmt.Sprintf("\nEnter '%[1]s' to cancel the request and %[2]s",
+			instructions = "\n" + i18n.Sprintf(m.lang, "Enter '%[1]s' to cancel the request and %[2]s",
 				nativeCancelKey, goBackLabel)
 		}
-		title := m.selectedAuthModeLabel("Password Update")
+		title := m.selectedAuthModeLabel(i18n.Translate(m.lang, "Password Update"))
 		if cmd := maybeSendPamError(m.sendInfo("== %s ==%s", title, instructions)); cmd != nil {
 			return cmd
 		}
@@ -839,7 +956,7 @@ func (m nativeModel) newPasswordChallenge(previousPassword *string) tea.Cmd {
 
 	prompt := m.uiLayout.GetLabel()
 	if previousPassword != nil {
-		prompt = "Confirm Password"
+		prompt = i18n.Translate(m.lang, "Confirm Password")
 	}
 
 	password, err := m.promptForSecret(prompt)
@@ -854,7 +971,7 @@ func (m nativeModel) newPasswordChallenge(previousPassword *string) tea.Cmd {
 		return sendEvent(newPasswordCheck{password: password})
 	}
 	if password != *previousPassword {
-		err := m.sendError("Password entries don't match")
+		err := m.sendError(i18n.Translate(m.lang, "Password entries don't match"))
 		if err != nil {
 			return maybeSendPamError(err)
 		}
@@ -906,7 +1023,7 @@ func (m nativeModel) goBackActionLabel() string {
 		return ""
 	}
 
-	return goBackLabel(m.previousStage())
+	return goBackLabel(m.lang, m.previousStage())
 }
 
 func sendAuthWaitCommand() tea.Cmd {
@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 )
@@ -14,6 +15,7 @@ import (
 // newPasswordModel is the form layout type to allow authentication and return a password.
 type newPasswordModel struct {
 	errorMsg  string
+	lang      string
 	label     string
 	skippable bool
 
@@ -24,7 +26,7 @@ type newPasswordModel struct {
 }
 
 // newNewPasswordModel initializes and return a new newPasswordModel.
-func newNewPasswordModel(label, entryType, buttonLabel string) newPasswordModel {
+func newNewPasswordModel(lang, label, entryType, buttonLabel string) newPasswordModel {
 	var focusableModels []authenticationComponent
 	var passwordEntries []*textinputModel
 	var skippable bool
@@ -43,11 +45,12 @@ func newNewPasswordModel(label, entryType, buttonLabel string) newPasswordModel
 	}
 
 	return newPasswordModel{
+		lang:      lang,
 		label:     label,
 		skippable: skippable,
 
 		passwordEntries: passwordEntries,
-		passwordLabels:  []string{"New password:", "Confirm password:"},
+		passwordLabels:  []string{i18n.Translate(lang, "New password:"), i18n.Translate(lang, "Confirm password:")},
 		focusableModels: focusableModels,
 	}
 }
@@ -121,7 +124,7 @@ func (m newPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Check both entries are matching
 					if m.passwordEntries[0].Value() != m.passwordEntries[1].Value() {
 						return m, tea.Sequence(m.Clear(),
-							sendEvent(errMsgToDisplay{msg: "Password entries don't match"}))
+							sendEvent(errMsgToDisplay{msg: i18n.Translate(m.lang, "Password entries don't match")}))
 					}
 				}
 
@@ -7,21 +7,23 @@ import "C"
 
 import (
 	"errors"
-	"fmt"
 	"sync"
 	"unsafe"
+
+	"github.com/ubuntu/authd/internal/i18n"
 )
 
 var passwordQualityMu sync.Mutex
 
-// checkPasswordQuality checks the quality of the new password using the pwquality library.
-func checkPasswordQuality(oldPassword, newPassword string) error {
+// checkPasswordQuality checks the quality of the new password using the pwquality library. The errors are translated
+// in lang.
+func checkPasswordQuality(lang, oldPassword, newPassword string) error {
 	passwordQualityMu.Lock()
 	defer passwordQualityMu.Unlock()
 
 	pwq := C.pwquality_default_settings()
 	if pwq == nil {
-		return errors.New("could not allocate pw quality default settings")
+		return errors.New(i18n.Translate(lang, "could not allocate pw quality default settings"))
 	}
 	defer C.pwquality_free_settings(pwq)
 
@@ -32,7 +34,7 @@ func checkPasswordQuality(oldPassword, newPassword string) error {
 	if ret := C.pwquality_read_config(pwq, nil, &auxErrPointer); ret < 0 {
 		var buf [C.PWQ_MAX_ERROR_MESSAGE_LEN]C.char
 		errMsg := C.GoString(C.pwquality_strerror(&buf[0], C.size_t(len(buf)), ret, auxErrPointer))
-		return fmt.Errorf("can't ready pwquality configuration: %s", errMsg)
+		return errors.New(i18n.Sprintf(lang, "can't read pwquality configuration: %s", errMsg))
 	}
 
 	oldC := C.CString(oldPassword)
@@ -43,6 +45,7 @@ func checkPasswordQuality(oldPassword, newPassword string) error {
 
 	if ret := C.pwquality_check(pwq, newC, oldC, nil, &auxErrPointer); ret < 0 {
 		var buf [C.PWQ_MAX_ERROR_MESSAGE_LEN]C.char
+		// libpwquality translates its own messages.
 		errMsg := C.GoString(C.pwquality_strerror(&buf[0], C.size_t(len(buf)), ret, auxErrPointer))
 		return errors.New(errMsg)
 	}
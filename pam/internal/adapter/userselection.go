@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/proto"
 )
@@ -39,14 +40,14 @@ func sendUserSelected(username string) tea.Cmd {
 }
 
 // newUserSelectionModel returns an initialized userSelectionModel.
-func newUserSelectionModel(pamMTx pam.ModuleTransaction, clientType PamClientType) userSelectionModel {
+func newUserSelectionModel(pamMTx pam.ModuleTransaction, clientType PamClientType, lang string) userSelectionModel {
 	u := textinput.New()
 	if clientType != InteractiveTerminal {
 		// Cursor events are racy: https://github.com/charmbracelet/bubbletea/issues/909.
 		// FIXME: Avoid initializing the text input Model at all.
 		u.Cursor.SetMode(cursor.CursorHide)
 	}
-	u.Prompt = "Username: " // TODO: i18n
+	u.Prompt = i18n.Translate(lang, "Username: ")
 	u.Placeholder = "user name"
 
 	//TODO: u.Validate
@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/term"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/proto"
 	pam_proto "github.com/ubuntu/authd/pam/internal/proto"
@@ -71,6 +75,31 @@ func isSSHSessionFunc(mTx pam.ModuleTransaction) bool {
 	return false
 }
 
+// GetPAMContext returns the context of the PAM transaction, as set by the PAM application.
+func GetPAMContext(mTx pam.ModuleTransaction) *authd.PAMContext {
+	// All the items are optional, so we just ignore the ones we can't get.
+	service, _ := mTx.GetItem(pam.Service)
+	tty, _ := mTx.GetItem(pam.Tty)
+	rhost, _ := mTx.GetItem(pam.Rhost)
+	ruser, _ := mTx.GetItem(pam.Ruser)
+
+	return &authd.PAMContext{
+		Service: service,
+		Tty:     tty,
+		Rhost:   rhost,
+		Ruser:   ruser,
+	}
+}
+
+// SessionLang returns the language of the messages of the PAM transaction. It's read from the PAM environment, where
+// applications such as GDM set the language chosen by the user, and defaults to the language of the process.
+func SessionLang(mTx pam.ModuleTransaction) string {
+	if lang := i18n.LangFromEnv(mTx.GetEnv); lang != "" {
+		return lang
+	}
+	return i18n.Lang()
+}
+
 // isSSHSession checks if the module transaction is currently handling a SSH session.
 func isSSHSession(mTx pam.ModuleTransaction) bool {
 	isSSHSessionOnce.Do(func() { isSSHSessionValue = isSSHSessionFunc(mTx) })
@@ -139,6 +168,27 @@ func maybeSendPamError(err error) tea.Cmd {
 	return sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 }
 
+// pamStatusFromError returns the PAM status matching the authd error code attached by the daemon to err, or fallback
+// if there is none.
+func pamStatusFromError(err error, fallback pam.Error) pam.Error {
+	switch errmessages.ErrorCode(err) {
+	case authd.ErrorCode_ERROR_BROKER_UNAVAILABLE:
+		// The PAM stack can fall back to the other modules, as with any unreachable authentication service.
+		return pam.ErrAuthinfoUnavail
+	case authd.ErrorCode_ERROR_USER_CONFLICT:
+		return pam.ErrPermDenied
+	case authd.ErrorCode_ERROR_LOCKED:
+		// The database of the daemon is busy, which is a failure of the system rather than of the authentication token.
+		return pam.ErrSystem
+	case authd.ErrorCode_ERROR_INVALID_LAYOUT:
+		return pam.ErrService
+	case authd.ErrorCode_ERROR_INTERNAL:
+		return pam.ErrSystem
+	default:
+		return fallback
+	}
+}
+
 var debugMessageFormatter = defaultSafeMessageFormatter
 
 func defaultSafeMessageFormatter(msg tea.Msg) string {
@@ -149,6 +199,9 @@ func defaultSafeMessageFormatter(msg tea.Msg) string {
 	case newPasswordCheckResult:
 		return fmt.Sprintf("%#v",
 			newPasswordCheckResult{password: "***********", msg: msg.msg, ctx: msg.ctx})
+	case firstPassResultReceived:
+		return fmt.Sprintf("%#v",
+			firstPassResultReceived{password: "***********", access: msg.access, msg: msg.msg})
 	case isAuthenticatedRequested:
 		switch item := msg.item.(type) {
 		case *authd.IARequest_AuthenticationData_Secret:
@@ -157,12 +210,18 @@ func defaultSafeMessageFormatter(msg tea.Msg) string {
 			return fmt.Sprintf("%T{%T{Wait:%q}}", msg, item, item.Wait)
 		case *authd.IARequest_AuthenticationData_Skip:
 			return fmt.Sprintf("%T{%T{Skip:%q}}", msg, item, item.Skip)
+		case *authd.IARequest_AuthenticationData_Choice:
+			return fmt.Sprintf("%T{%T{Choice:%q}}", msg, item, item.Choice)
+		case *authd.IARequest_AuthenticationData_Fields:
+			return fmt.Sprintf("%T{%T{Fields:%q}}", msg, item, slices.Sorted(maps.Keys(item.Fields.GetSecrets())))
 		default:
 			return fmt.Sprintf("%T{%T{}}", msg, item)
 		}
 	case isAuthenticatedRequestedSend:
 		return fmt.Sprintf("%T{%s}", msg,
 			defaultSafeMessageFormatter(msg.isAuthenticatedRequested))
+	case isAuthenticatedProgressReceived:
+		return fmt.Sprintf("%T{msg:%q}", msg, msg.msg)
 	case UILayoutReceived:
 		return fmt.Sprintf("%T{%#v}", msg, msg.layout)
 	case ChangeStage:
@@ -192,6 +251,8 @@ func testMessageFormatter(msg tea.Msg) string {
 		switch item := msg.item.(type) {
 		case *authd.IARequest_AuthenticationData_Secret:
 			return fmt.Sprintf(`%T{%T{Secret:%q}}`, msg, item, item.Secret)
+		case *authd.IARequest_AuthenticationData_Fields:
+			return fmt.Sprintf(`%T{%T{Fields:%v}}`, msg, item, item.Fields.GetSecrets())
 		default:
 			return defaultSafeMessageFormatter(msg)
 		}
@@ -239,16 +300,16 @@ func safeMessageDebugWithPrefix(prefix string, msg tea.Msg, formatAndArgs ...any
 	log.Debugf(context.Background(), "%s, %s", m, fmt.Sprintf(format, args...))
 }
 
-func goBackLabel(previousStage pam_proto.Stage) string {
+func goBackLabel(lang string, previousStage pam_proto.Stage) string {
 	switch previousStage {
 	case proto.Stage_authModeSelection:
-		return "go back to select the authentication method"
+		return i18n.Translate(lang, "go back to select the authentication method")
 	case proto.Stage_brokerSelection:
-		return "go back to choose the provider"
+		return i18n.Translate(lang, "go back to choose the provider")
 	case proto.Stage_challenge:
-		return "go back to authentication"
+		return i18n.Translate(lang, "go back to authentication")
 	case proto.Stage_userSelection:
-		return "go back to user selection"
+		return i18n.Translate(lang, "go back to user selection")
 	default:
 		return ""
 	}
@@ -2,14 +2,21 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/msteinert/pam/v2"
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/authd/pam/internal/pam_test"
 	"github.com/ubuntu/authd/pam/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestDebugMessageFormatter(t *testing.T) {
@@ -313,3 +320,88 @@ func TestSafeMessageDebug(t *testing.T) {
 		})
 	}
 }
+
+func TestPamStatusFromError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err error
+
+		want pam.Error
+	}{
+		"Broker_unavailable_is_authinfo_unavailable": {
+			err:  errmessages.WithErrorCode(authd.ErrorCode_ERROR_BROKER_UNAVAILABLE, errors.New("broker unavailable")),
+			want: pam.ErrAuthinfoUnavail,
+		},
+		"User_conflict_is_permission_denied": {
+			err:  errmessages.WithErrorCode(authd.ErrorCode_ERROR_USER_CONFLICT, errors.New("user conflict")),
+			want: pam.ErrPermDenied,
+		},
+		"Locked_is_system_error": {
+			err:  errmessages.WithErrorCode(authd.ErrorCode_ERROR_LOCKED, errors.New("locked")),
+			want: pam.ErrSystem,
+		},
+		"Invalid_layout_is_service_error": {
+			err:  errmessages.WithErrorCode(authd.ErrorCode_ERROR_INVALID_LAYOUT, errors.New("invalid layout")),
+			want: pam.ErrService,
+		},
+		"Internal_error_is_system_error": {
+			err:  fmt.Errorf("wrapped: %w", errmessages.WithErrorCode(authd.ErrorCode_ERROR_INTERNAL, errors.New("internal"))),
+			want: pam.ErrSystem,
+		},
+		"Error_code_of_gRPC_status_is_used": {
+			err:  grpcErrorWithCode(t, authd.ErrorCode_ERROR_USER_CONFLICT),
+			want: pam.ErrPermDenied,
+		},
+		"Error_without_code_returns_fallback": {
+			err:  errors.New("some error"),
+			want: pam.ErrAbort,
+		},
+		"Unspecified_error_code_returns_fallback": {
+			err:  errmessages.WithErrorCode(authd.ErrorCode_ERROR_UNSPECIFIED, errors.New("unspecified")),
+			want: pam.ErrAbort,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := pamStatusFromError(tc.err, pam.ErrAbort)
+			require.Equal(t, tc.want, got, "pamStatusFromError returned an unexpected status")
+		})
+	}
+}
+
+func TestSessionLang(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		pamEnv []string
+
+		want string
+	}{
+		"Language_of_PAM_environment_is_used":      {pamEnv: []string{"LANG=fr_FR.UTF-8"}, want: "fr_FR"},
+		"LC_ALL_of_PAM_environment_has_precedence": {pamEnv: []string{"LANG=de_DE", "LC_ALL=fr_FR.UTF-8"}, want: "fr_FR"},
+		"Default_to_language_of_process":           {pamEnv: []string{"PATH=/usr/bin"}, want: i18n.Lang()},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mTx := pam_test.NewModuleTransactionDummy(nil)
+			for _, env := range tc.pamEnv {
+				require.NoError(t, mTx.PutEnv(env), "Setup: could not set PAM environment")
+			}
+
+			require.Equal(t, tc.want, SessionLang(mTx), "SessionLang returned an unexpected language")
+		})
+	}
+}
+
+func grpcErrorWithCode(t *testing.T, code authd.ErrorCode) error {
+	t.Helper()
+
+	st, err := status.New(codes.Unknown, "error").WithDetails(&authd.ErrorDetails{Code: code})
+	require.NoError(t, err, "Setup: could not attach error details")
+	return st.Err()
+}
@@ -0,0 +1,120 @@
+package adapter
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// webAuthnAuthenticator is a local FIDO2 authenticator, such as a security key.
+type webAuthnAuthenticator interface {
+	// GetAssertion waits for the user to confirm their presence on the authenticator and returns the assertion of
+	// the SHA-256 hash of the client data for the relying party, made with one of the allowed credentials if any is
+	// provided.
+	GetAssertion(ctx context.Context, rpID string, clientData []byte, allowedCredentials [][]byte) (*authd.IARequest_AuthenticationData_WebAuthnAssertion, error)
+}
+
+// localAuthenticator is the authenticator used to answer the webauthn challenges.
+var localAuthenticator webAuthnAuthenticator = fido2Authenticator{}
+
+// webAuthnTouchRequested is the event signalling that the user is expected to touch their security key.
+type webAuthnTouchRequested struct{}
+
+// webAuthnAssertionRequested is the internal event signalling that the local authenticator has to answer the
+// challenge of the current webauthn layout.
+type webAuthnAssertionRequested struct {
+	ctx context.Context
+}
+
+// requestWebAuthnAssertion requests the authentication with the assertion of the local authenticator.
+// An empty assertion is replaced by the one of the local authenticator before being sent to the broker.
+func requestWebAuthnAssertion() tea.Cmd {
+	return sendEvent(isAuthenticatedRequested{
+		item: &authd.IARequest_AuthenticationData_Webauthn{},
+	})
+}
+
+// needsWebAuthnAssertion returns whether the authentication item is a webauthn one that still has to be asserted by
+// the local authenticator.
+func needsWebAuthnAssertion(item authd.IARequestAuthenticationDataItem) bool {
+	webauthn, ok := item.(*authd.IARequest_AuthenticationData_Webauthn)
+	return ok && len(webauthn.Webauthn.GetSignature()) == 0
+}
+
+// getWebAuthnAssertion asks the local authenticator for the assertion of the challenge of the webauthn layout.
+func getWebAuthnAssertion(ctx context.Context, layout *authd.UILayout) tea.Cmd {
+	return func() tea.Msg {
+		challenge, err := layouts.ParseChallenge(layout.GetChallenge())
+		if err != nil {
+			return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("invalid webauthn layout: %v", err)}
+		}
+		credentials, err := layouts.ParseCredentials(layout.GetCredentials())
+		if err != nil {
+			return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("invalid webauthn layout: %v", err)}
+		}
+
+		clientData, err := layouts.FormatClientData(challenge, layout.GetRpId())
+		if err != nil {
+			return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("could not create webauthn client data: %v", err)}
+		}
+
+		assertion, err := localAuthenticator.GetAssertion(ctx, layout.GetRpId(), clientData, credentials)
+		if ctx.Err() != nil {
+			return isAuthenticatedResultReceived{access: auth.Cancelled}
+		}
+		if err != nil {
+			errMsg, _ := json.Marshal(fmt.Sprintf("Security key error: %v", err))
+			return isAuthenticatedResultReceived{
+				access: auth.Retry,
+				msg:    fmt.Sprintf(`{"message": %s}`, errMsg),
+			}
+		}
+
+		// The broker needs the client data to check the signature and that it is for its challenge.
+		assertion.ClientDataJson = clientData
+
+		return isAuthenticatedRequestedSend{
+			isAuthenticatedRequested: isAuthenticatedRequested{
+				item: &authd.IARequest_AuthenticationData_Webauthn{Webauthn: assertion},
+			},
+			ctx: ctx,
+		}
+	}
+}
+
+// decodeCBORByteString returns the content of a CBOR encoded byte string, which is how libfido2 provides the
+// authenticator data.
+func decodeCBORByteString(data []byte) ([]byte, error) {
+	// Major type 2 is a byte string.
+	if len(data) == 0 || data[0]>>5 != 2 {
+		return nil, errors.New("not a CBOR byte string")
+	}
+
+	var length uint64
+	var header int
+	switch info := data[0] & 0x1f; {
+	case info < 24:
+		length, header = uint64(info), 1
+	case info == 24 && len(data) >= 2:
+		length, header = uint64(data[1]), 2
+	case info == 25 && len(data) >= 3:
+		length, header = uint64(binary.BigEndian.Uint16(data[1:3])), 3
+	case info == 26 && len(data) >= 5:
+		length, header = uint64(binary.BigEndian.Uint32(data[1:5])), 5
+	default:
+		return nil, errors.New("unsupported CBOR byte string length")
+	}
+
+	if uint64(len(data)-header) != length {
+		return nil, fmt.Errorf("CBOR byte string length mismatch: expected %d, got %d", length, len(data)-header)
+	}
+	return data[header:], nil
+}
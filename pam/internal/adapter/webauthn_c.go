@@ -0,0 +1,144 @@
+package adapter
+
+// #cgo pkg-config: libfido2
+// #include <stdlib.h>
+// #include <fido.h>
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+const fido2MaxDevices = 64
+
+var fido2InitOnce sync.Once
+
+// fido2Authenticator is the [webAuthnAuthenticator] using the first security key found by libfido2.
+type fido2Authenticator struct{}
+
+// GetAssertion waits for the user to touch the security key and returns its assertion of the client data, which
+// libfido2 hashes.
+func (fido2Authenticator) GetAssertion(ctx context.Context, rpID string, clientData []byte, allowedCredentials [][]byte) (*authd.IARequest_AuthenticationData_WebAuthnAssertion, error) {
+	fido2InitOnce.Do(func() { C.fido_init(0) })
+
+	path, err := fido2FirstDevicePath()
+	if err != nil {
+		return nil, err
+	}
+
+	dev := C.fido_dev_new()
+	if dev == nil {
+		return nil, errors.New("could not allocate security key device")
+	}
+	defer C.fido_dev_free(&dev)
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	if r := C.fido_dev_open(dev, cPath); r != C.FIDO_OK {
+		return nil, fido2Error("could not open security key", r)
+	}
+	defer C.fido_dev_close(dev)
+
+	assert := C.fido_assert_new()
+	if assert == nil {
+		return nil, errors.New("could not allocate security key assertion")
+	}
+	defer C.fido_assert_free(&assert)
+
+	cRPID := C.CString(rpID)
+	defer C.free(unsafe.Pointer(cRPID))
+	if r := C.fido_assert_set_rp(assert, cRPID); r != C.FIDO_OK {
+		return nil, fido2Error("could not set relying party", r)
+	}
+
+	cClientData := C.CBytes(clientData)
+	defer C.free(cClientData)
+	if r := C.fido_assert_set_clientdata(assert, (*C.uchar)(cClientData), C.size_t(len(clientData))); r != C.FIDO_OK {
+		return nil, fido2Error("could not set client data", r)
+	}
+
+	for _, id := range allowedCredentials {
+		cID := C.CBytes(id)
+		r := C.fido_assert_allow_cred(assert, (*C.uchar)(cID), C.size_t(len(id)))
+		C.free(cID)
+		if r != C.FIDO_OK {
+			return nil, fido2Error("could not allow credential", r)
+		}
+	}
+
+	if r := C.fido_assert_set_up(assert, C.FIDO_OPT_TRUE); r != C.FIDO_OK {
+		return nil, fido2Error("could not require user presence", r)
+	}
+
+	// Getting the assertion blocks until the user touches the security key, so cancel it on the device if we are
+	// not waiting for it anymore.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+			C.fido_dev_cancel(dev)
+		case <-done:
+		}
+	}()
+	r := C.fido_dev_get_assert(dev, assert, nil)
+	close(done)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if r != C.FIDO_OK {
+		return nil, fido2Error("could not get security key assertion", r)
+	}
+	if C.fido_assert_count(assert) == 0 {
+		return nil, errors.New("security key returned no assertion")
+	}
+
+	authData, err := decodeCBORByteString(C.GoBytes(unsafe.Pointer(C.fido_assert_authdata_ptr(assert, 0)),
+		C.int(C.fido_assert_authdata_len(assert, 0))))
+	if err != nil {
+		return nil, fmt.Errorf("invalid authenticator data: %v", err)
+	}
+
+	return &authd.IARequest_AuthenticationData_WebAuthnAssertion{
+		CredentialId: C.GoBytes(unsafe.Pointer(C.fido_assert_id_ptr(assert, 0)),
+			C.int(C.fido_assert_id_len(assert, 0))),
+		AuthenticatorData: authData,
+		Signature: C.GoBytes(unsafe.Pointer(C.fido_assert_sig_ptr(assert, 0)),
+			C.int(C.fido_assert_sig_len(assert, 0))),
+		UserHandle: C.GoBytes(unsafe.Pointer(C.fido_assert_user_id_ptr(assert, 0)),
+			C.int(C.fido_assert_user_id_len(assert, 0))),
+	}, nil
+}
+
+// fido2FirstDevicePath returns the path of the first security key connected to the machine.
+func fido2FirstDevicePath() (string, error) {
+	devList := C.fido_dev_info_new(fido2MaxDevices)
+	if devList == nil {
+		return "", errors.New("could not allocate security keys list")
+	}
+	defer C.fido_dev_info_free(&devList, fido2MaxDevices)
+
+	var n C.size_t
+	if r := C.fido_dev_info_manifest(devList, fido2MaxDevices, &n); r != C.FIDO_OK {
+		return "", fido2Error("could not list security keys", r)
+	}
+	if n == 0 {
+		return "", errors.New("no security key found")
+	}
+
+	return C.GoString(C.fido_dev_info_path(C.fido_dev_info_ptr(devList, 0))), nil
+}
+
+func fido2Error(msg string, r C.int) error {
+	return fmt.Errorf("%s: %s", msg, C.GoString(C.fido_strerr(r)))
+}
@@ -0,0 +1,47 @@
+package adapter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCBORByteString(t *testing.T) {
+	t.Parallel()
+
+	longData := bytes.Repeat([]byte{0xab}, 300)
+
+	testCases := map[string]struct {
+		data []byte
+
+		want    []byte
+		wantErr bool
+	}{
+		"Empty_byte_string":                  {data: []byte{0x40}, want: []byte{}},
+		"Short_byte_string":                  {data: []byte{0x43, 1, 2, 3}, want: []byte{1, 2, 3}},
+		"Byte_string_with_one_byte_length":   {data: append([]byte{0x58, 30}, longData[:30]...), want: longData[:30]},
+		"Byte_string_with_two_bytes_length":  {data: append([]byte{0x59, 0x01, 0x2c}, longData...), want: longData},
+		"Byte_string_with_four_bytes_length": {data: append([]byte{0x5a, 0, 0, 0x01, 0x2c}, longData...), want: longData},
+
+		"Error_on_empty_data":                   {data: nil, wantErr: true},
+		"Error_on_not_a_byte_string":            {data: []byte{0x63, 'a', 'b', 'c'}, wantErr: true},
+		"Error_on_unsupported_length":           {data: []byte{0x5b, 0, 0, 0, 0, 0, 0, 0, 1, 0}, wantErr: true},
+		"Error_on_truncated_length":             {data: []byte{0x59, 0x01}, wantErr: true},
+		"Error_on_data_shorter_than_the_length": {data: []byte{0x43, 1, 2}, wantErr: true},
+		"Error_on_data_longer_than_the_length":  {data: []byte{0x41, 1, 2}, wantErr: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := decodeCBORByteString(tc.data)
+			if tc.wantErr {
+				require.Error(t, err, "decodeCBORByteString should have failed")
+				return
+			}
+			require.NoError(t, err, "decodeCBORByteString should not have failed")
+			require.Equal(t, tc.want, got, "Decoded byte string mismatch")
+		})
+	}
+}
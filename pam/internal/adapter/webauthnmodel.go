@@ -0,0 +1,84 @@
+package adapter
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/log"
+)
+
+// webAuthnModel is the webauthn layout type to allow authentication with a security key.
+type webAuthnModel struct {
+	lang  string
+	label string
+
+	focused  bool
+	touching bool
+}
+
+// newWebAuthnModel initializes and return a new webAuthnModel.
+func newWebAuthnModel(lang, label string) *webAuthnModel {
+	return &webAuthnModel{lang: lang, label: label}
+}
+
+// Init initializes webAuthnModel.
+func (m *webAuthnModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles events and actions.
+func (m *webAuthnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case startAuthentication:
+		m.touching = false
+		return m, nil
+
+	case webAuthnTouchRequested:
+		m.touching = true
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() != "enter" || m.touching || !m.focused {
+			return m, nil
+		}
+		return m, requestWebAuthnAssertion()
+	}
+
+	return m, nil
+}
+
+// View renders a text view of the webauthn authentication.
+func (m *webAuthnModel) View() string {
+	var fields []string
+	if m.label != "" {
+		fields = append(fields, m.label)
+	}
+
+	instructions := i18n.Translate(m.lang, "Press Enter to use your security key")
+	if m.touching {
+		instructions = i18n.Translate(m.lang, "Touch your security key…")
+	}
+	fields = append(fields, instructions)
+
+	return lipgloss.JoinVertical(lipgloss.Left, fields...)
+}
+
+// Focus focuses this model.
+func (m *webAuthnModel) Focus() tea.Cmd {
+	log.Debugf(context.TODO(), "%T: Focus", m)
+	m.focused = true
+	return nil
+}
+
+// Focused returns whether this model is focused.
+func (m *webAuthnModel) Focused() bool {
+	return m.focused
+}
+
+// Blur releases the focus from this model.
+func (m *webAuthnModel) Blur() {
+	log.Debugf(context.TODO(), "%T: Blur", m)
+	m.focused = false
+}
@@ -178,6 +178,10 @@ func EmitEvent(pamMTx pam.ModuleTransaction, event Event) error {
 		evType = EventType_userSelected
 	case *EventData_StartAuthentication:
 		evType = EventType_startAuthentication
+	case *EventData_WebAuthnTouchRequested:
+		evType = EventType_webAuthnTouchRequested
+	case *EventData_AuthenticationProgress:
+		evType = EventType_authenticationProgress
 	default:
 		return fmt.Errorf("no known event type %#v", event)
 	}
@@ -961,6 +961,14 @@ func TestDataEmitEvent(t *testing.T) {
 			event:         &EventData_StartAuthentication{},
 			wantEventType: EventType_startAuthentication,
 		},
+		"Emit_event_WebAuthnTouchRequested": {
+			event:         &EventData_WebAuthnTouchRequested{},
+			wantEventType: EventType_webAuthnTouchRequested,
+		},
+		"Emit_event_AuthenticationProgress": {
+			event:         &EventData_AuthenticationProgress{},
+			wantEventType: EventType_authenticationProgress,
+		},
 
 		// Error cases
 		"Error_on_nil_event": {
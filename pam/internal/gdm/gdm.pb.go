@@ -184,6 +184,10 @@ const (
 	EventType_isAuthenticatedCancelled EventType = 11
 	// EventType_stageChanged is stage changed EventType.
 	EventType_stageChanged EventType = 12
+	// EventType_webAuthnTouchRequested is a security key touch request EventType.
+	EventType_webAuthnTouchRequested EventType = 13
+	// EventType_authenticationProgress is an authentication progress message EventType.
+	EventType_authenticationProgress EventType = 14
 )
 
 // Enum value maps for EventType.
@@ -202,6 +206,8 @@ var (
 		10: "isAuthenticatedRequested",
 		11: "isAuthenticatedCancelled",
 		12: "stageChanged",
+		13: "webAuthnTouchRequested",
+		14: "authenticationProgress",
 	}
 	EventType_value = map[string]int32{
 		"unknownEvent":             0,
@@ -217,6 +223,8 @@ var (
 		"isAuthenticatedRequested": 10,
 		"isAuthenticatedCancelled": 11,
 		"stageChanged":             12,
+		"webAuthnTouchRequested":   13,
+		"authenticationProgress":   14,
 	}
 )
 
@@ -680,6 +688,8 @@ type EventData struct {
 	//	*EventData_StartAuthentication
 	//	*EventData_UserSelected
 	//	*EventData_IsAuthenticatedCancelled
+	//	*EventData_WebAuthnTouchRequested
+	//	*EventData_AuthenticationProgress
 	Data          isEventData_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -837,6 +847,24 @@ func (x *EventData) GetIsAuthenticatedCancelled() *Events_IsAuthenticatedCancell
 	return nil
 }
 
+func (x *EventData) GetWebAuthnTouchRequested() *Events_WebAuthnTouchRequested {
+	if x != nil {
+		if x, ok := x.Data.(*EventData_WebAuthnTouchRequested); ok {
+			return x.WebAuthnTouchRequested
+		}
+	}
+	return nil
+}
+
+func (x *EventData) GetAuthenticationProgress() *Events_AuthenticationProgress {
+	if x != nil {
+		if x, ok := x.Data.(*EventData_AuthenticationProgress); ok {
+			return x.AuthenticationProgress
+		}
+	}
+	return nil
+}
+
 type isEventData_Data interface {
 	isEventData_Data()
 }
@@ -889,6 +917,14 @@ type EventData_IsAuthenticatedCancelled struct {
 	IsAuthenticatedCancelled *Events_IsAuthenticatedCancelled `protobuf:"bytes,21,opt,name=isAuthenticatedCancelled,proto3,oneof"`
 }
 
+type EventData_WebAuthnTouchRequested struct {
+	WebAuthnTouchRequested *Events_WebAuthnTouchRequested `protobuf:"bytes,22,opt,name=webAuthnTouchRequested,proto3,oneof"`
+}
+
+type EventData_AuthenticationProgress struct {
+	AuthenticationProgress *Events_AuthenticationProgress `protobuf:"bytes,23,opt,name=authenticationProgress,proto3,oneof"`
+}
+
 func (*EventData_BrokersReceived) isEventData_Data() {}
 
 func (*EventData_BrokerSelected) isEventData_Data() {}
@@ -913,6 +949,10 @@ func (*EventData_UserSelected) isEventData_Data() {}
 
 func (*EventData_IsAuthenticatedCancelled) isEventData_Data() {}
 
+func (*EventData_WebAuthnTouchRequested) isEventData_Data() {}
+
+func (*EventData_AuthenticationProgress) isEventData_Data() {}
+
 type Requests_UiLayoutCapabilities struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1577,6 +1617,86 @@ func (x *Events_UiLayoutReceived) GetUiLayout() *authd.UILayout {
 	return nil
 }
 
+type Events_WebAuthnTouchRequested struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Events_WebAuthnTouchRequested) Reset() {
+	*x = Events_WebAuthnTouchRequested{}
+	mi := &file_gdm_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Events_WebAuthnTouchRequested) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Events_WebAuthnTouchRequested) ProtoMessage() {}
+
+func (x *Events_WebAuthnTouchRequested) ProtoReflect() protoreflect.Message {
+	mi := &file_gdm_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Events_WebAuthnTouchRequested.ProtoReflect.Descriptor instead.
+func (*Events_WebAuthnTouchRequested) Descriptor() ([]byte, []int) {
+	return file_gdm_proto_rawDescGZIP(), []int{6, 12}
+}
+
+type Events_AuthenticationProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Events_AuthenticationProgress) Reset() {
+	*x = Events_AuthenticationProgress{}
+	mi := &file_gdm_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Events_AuthenticationProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Events_AuthenticationProgress) ProtoMessage() {}
+
+func (x *Events_AuthenticationProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_gdm_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Events_AuthenticationProgress.ProtoReflect.Descriptor instead.
+func (*Events_AuthenticationProgress) Descriptor() ([]byte, []int) {
+	return file_gdm_proto_rawDescGZIP(), []int{6, 13}
+}
+
+func (x *Events_AuthenticationProgress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_gdm_proto protoreflect.FileDescriptor
 
 var file_gdm_proto_rawDesc = string([]byte{
@@ -1643,7 +1763,7 @@ var file_gdm_proto_rawDesc = string([]byte{
 	0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
 	0x65, 0x73, 0x48, 0x00, 0x52, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61,
 	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x22, 0xa9, 0x05, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x53, 0x0a,
+	0x74, 0x61, 0x22, 0xf7, 0x05, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x53, 0x0a,
 	0x0f, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
 	0x12, 0x40, 0x0a, 0x0c, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x73,
 	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x41,
@@ -1685,105 +1805,126 @@ var file_gdm_proto_rawDesc = string([]byte{
 	0x10, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
 	0x64, 0x12, 0x2b, 0x0a, 0x08, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x55, 0x49, 0x4c, 0x61,
-	0x79, 0x6f, 0x75, 0x74, 0x52, 0x08, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x22, 0xcd,
-	0x07, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x6d,
-	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x12, 0x47, 0x0a, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69,
-	0x76, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x64, 0x6d, 0x2e,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72,
-	0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x44, 0x0a, 0x0e, 0x62, 0x72, 0x6f,
-	0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42,
-	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52,
-	0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12,
-	0x4d, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65,
-	0x69, 0x76, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x64, 0x6d,
-	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65,
-	0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x11, 0x61, 0x75, 0x74,
-	0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x4a,
-	0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65,
-	0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f,
-	0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x62, 0x0a, 0x18, 0x69, 0x73,
-	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67,
-	0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x65, 0x64, 0x48, 0x00, 0x52, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x3e,
-	0x0a, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x0f,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x73, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x48, 0x00,
-	0x52, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x4a,
-	0x0a, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f,
-	0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x09, 0x61, 0x75,
-	0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
-	0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x61, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x12, 0x4a, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74,
-	0x68, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64,
-	0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63,
-	0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x10, 0x72, 0x65, 0x73,
-	0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x53, 0x0a,
-	0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x64, 0x6d,
-	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x13, 0x73,
+	0x79, 0x6f, 0x75, 0x74, 0x52, 0x08, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x1a, 0x18,
+	0x0a, 0x16, 0x57, 0x65, 0x62, 0x41, 0x75, 0x74, 0x68, 0x6e, 0x54, 0x6f, 0x75, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x1a, 0x32, 0x0a, 0x16, 0x41, 0x75, 0x74, 0x68,
+	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x89, 0x09, 0x0a,
+	0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x47,
+	0x0a, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x44, 0x0a, 0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42, 0x72, 0x6f,
+	0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0e, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x4d, 0x0a,
+	0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d,
+	0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x10,
+	0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65,
+	0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x62, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75,
+	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67, 0x64, 0x6d,
+	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e,
+	0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64,
+	0x48, 0x00, 0x52, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x3e, 0x0a, 0x0c,
+	0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x53, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0c,
+	0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x10,
+	0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x2e, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67, 0x64,
+	0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x61, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x4a, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d,
+	0x6f, 0x64, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41,
+	0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x53, 0x0a, 0x13, 0x73,
 	0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x48, 0x00, 0x52, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x12, 0x62, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x15,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x73, 0x2e, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x48, 0x00, 0x52, 0x18, 0x69, 0x73,
-	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e,
-	0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a, 0x76,
-	0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x75, 0x6e,
-	0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x68,
-	0x65, 0x6c, 0x6c, 0x6f, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x10,
-	0x02, 0x12, 0x0c, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x6b, 0x10, 0x03, 0x12,
-	0x0b, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x70, 0x6f,
-	0x6c, 0x6c, 0x10, 0x06, 0x12, 0x10, 0x0a, 0x0c, 0x70, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x10, 0x07, 0x2a, 0x82, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77,
-	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x75, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x10,
-	0x01, 0x12, 0x1d, 0x0a, 0x19, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x69, 0x65, 0x77, 0x10, 0x02,
-	0x12, 0x18, 0x0a, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61,
-	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x63, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x10, 0x04, 0x2a, 0xa7, 0x02, 0x0a, 0x09,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x75, 0x6e, 0x6b,
-	0x6e, 0x6f, 0x77, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x75,
-	0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x13, 0x0a,
-	0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
-	0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65,
-	0x63, 0x74, 0x65, 0x64, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f,
-	0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x04, 0x12, 0x14, 0x0a,
-	0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65,
-	0x64, 0x10, 0x05, 0x12, 0x14, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41,
-	0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x61, 0x75, 0x74,
-	0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x07, 0x12, 0x14, 0x0a, 0x10, 0x75, 0x69, 0x4c, 0x61,
-	0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x08, 0x12, 0x17,
-	0x0a, 0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x09, 0x12, 0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x65, 0x64, 0x10, 0x0a, 0x12, 0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65,
-	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65,
-	0x64, 0x10, 0x0b, 0x12, 0x10, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e,
-	0x67, 0x65, 0x64, 0x10, 0x0c, 0x42, 0x21, 0x5a, 0x1f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x75, 0x62, 0x75, 0x6e, 0x74, 0x75, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x64,
-	0x2f, 0x70, 0x61, 0x6d, 0x2f, 0x67, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65,
+	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x13, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x3e, 0x0a, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x48, 0x00, 0x52, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x12, 0x62, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x15, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x48, 0x00, 0x52, 0x18, 0x69, 0x73, 0x41, 0x75,
+	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x6c, 0x65, 0x64, 0x12, 0x5c, 0x0a, 0x16, 0x77, 0x65, 0x62, 0x41, 0x75, 0x74, 0x68, 0x6e,
+	0x54, 0x6f, 0x75, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x16,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x2e, 0x57, 0x65, 0x62, 0x41, 0x75, 0x74, 0x68, 0x6e, 0x54, 0x6f, 0x75, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x16, 0x77, 0x65, 0x62, 0x41,
+	0x75, 0x74, 0x68, 0x6e, 0x54, 0x6f, 0x75, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x65, 0x64, 0x12, 0x5c, 0x0a, 0x16, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x17, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x16, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e,
+	0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a, 0x76, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x10, 0x01,
+	0x12, 0x09, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x6b, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x70, 0x6f, 0x6c, 0x6c, 0x10, 0x06, 0x12, 0x10,
+	0x0a, 0x0c, 0x70, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x10, 0x07,
+	0x2a, 0x82, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x12, 0x0a, 0x0e, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x63,
+	0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x56, 0x69, 0x65, 0x77, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x75, 0x69,
+	0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74,
+	0x61, 0x67, 0x65, 0x10, 0x04, 0x2a, 0xdf, 0x02, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e,
+	0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x03,
+	0x12, 0x15, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d,
+	0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x05, 0x12, 0x14, 0x0a,
+	0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64,
+	0x65, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x10, 0x07, 0x12, 0x14, 0x0a, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x08, 0x12, 0x17, 0x0a, 0x13, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10,
+	0x09, 0x12, 0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x10, 0x0a, 0x12,
+	0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x0b, 0x12, 0x10, 0x0a,
+	0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x10, 0x0c, 0x12,
+	0x1a, 0x0a, 0x16, 0x77, 0x65, 0x62, 0x41, 0x75, 0x74, 0x68, 0x6e, 0x54, 0x6f, 0x75, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x10, 0x0d, 0x12, 0x1a, 0x0a, 0x16, 0x61,
+	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x10, 0x0e, 0x42, 0x21, 0x5a, 0x1f, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x75, 0x62, 0x75, 0x6e, 0x74, 0x75, 0x2f, 0x61, 0x75, 0x74,
+	0x68, 0x64, 0x2f, 0x70, 0x61, 0x6d, 0x2f, 0x67, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 })
 
 var (
@@ -1799,7 +1940,7 @@ func file_gdm_proto_rawDescGZIP() []byte {
 }
 
 var file_gdm_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_gdm_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_gdm_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_gdm_proto_goTypes = []any{
 	(DataType)(0),                                // 0: gdm.DataType
 	(RequestType)(0),                             // 1: gdm.RequestType
@@ -1828,12 +1969,14 @@ var file_gdm_proto_goTypes = []any{
 	(*Events_IsAuthenticatedCancelled)(nil),      // 24: gdm.Events.IsAuthenticatedCancelled
 	(*Events_StageChanged)(nil),                  // 25: gdm.Events.StageChanged
 	(*Events_UiLayoutReceived)(nil),              // 26: gdm.Events.UiLayoutReceived
-	(proto.Stage)(0),                             // 27: pam.Stage
-	(*authd.UILayout)(nil),                       // 28: authd.UILayout
-	(*authd.ABResponse_BrokerInfo)(nil),          // 29: authd.ABResponse.BrokerInfo
-	(*authd.GAMResponse_AuthenticationMode)(nil), // 30: authd.GAMResponse.AuthenticationMode
-	(*authd.IAResponse)(nil),                     // 31: authd.IAResponse
-	(*authd.IARequest_AuthenticationData)(nil),   // 32: authd.IARequest.AuthenticationData
+	(*Events_WebAuthnTouchRequested)(nil),        // 27: gdm.Events.WebAuthnTouchRequested
+	(*Events_AuthenticationProgress)(nil),        // 28: gdm.Events.AuthenticationProgress
+	(proto.Stage)(0),                             // 29: pam.Stage
+	(*authd.UILayout)(nil),                       // 30: authd.UILayout
+	(*authd.ABResponse_BrokerInfo)(nil),          // 31: authd.ABResponse.BrokerInfo
+	(*authd.GAMResponse_AuthenticationMode)(nil), // 32: authd.GAMResponse.AuthenticationMode
+	(*authd.IAResponse)(nil),                     // 33: authd.IAResponse
+	(*authd.IARequest_AuthenticationData)(nil),   // 34: authd.IARequest.AuthenticationData
 }
 var file_gdm_proto_depIdxs = []int32{
 	0,  // 0: gdm.Data.type:type_name -> gdm.DataType
@@ -1861,19 +2004,21 @@ var file_gdm_proto_depIdxs = []int32{
 	18, // 22: gdm.EventData.startAuthentication:type_name -> gdm.Events.StartAuthentication
 	17, // 23: gdm.EventData.userSelected:type_name -> gdm.Events.UserSelected
 	24, // 24: gdm.EventData.isAuthenticatedCancelled:type_name -> gdm.Events.IsAuthenticatedCancelled
-	27, // 25: gdm.Requests.ChangeStage.stage:type_name -> pam.Stage
-	28, // 26: gdm.Responses.UiLayoutCapabilities.supportedUiLayouts:type_name -> authd.UILayout
-	29, // 27: gdm.Events.BrokersReceived.brokersInfos:type_name -> authd.ABResponse.BrokerInfo
-	30, // 28: gdm.Events.AuthModesReceived.authModes:type_name -> authd.GAMResponse.AuthenticationMode
-	31, // 29: gdm.Events.AuthEvent.response:type_name -> authd.IAResponse
-	32, // 30: gdm.Events.IsAuthenticatedRequested.authentication_data:type_name -> authd.IARequest.AuthenticationData
-	27, // 31: gdm.Events.StageChanged.stage:type_name -> pam.Stage
-	28, // 32: gdm.Events.UiLayoutReceived.uiLayout:type_name -> authd.UILayout
-	33, // [33:33] is the sub-list for method output_type
-	33, // [33:33] is the sub-list for method input_type
-	33, // [33:33] is the sub-list for extension type_name
-	33, // [33:33] is the sub-list for extension extendee
-	0,  // [0:33] is the sub-list for field type_name
+	27, // 25: gdm.EventData.webAuthnTouchRequested:type_name -> gdm.Events.WebAuthnTouchRequested
+	28, // 26: gdm.EventData.authenticationProgress:type_name -> gdm.Events.AuthenticationProgress
+	29, // 27: gdm.Requests.ChangeStage.stage:type_name -> pam.Stage
+	30, // 28: gdm.Responses.UiLayoutCapabilities.supportedUiLayouts:type_name -> authd.UILayout
+	31, // 29: gdm.Events.BrokersReceived.brokersInfos:type_name -> authd.ABResponse.BrokerInfo
+	32, // 30: gdm.Events.AuthModesReceived.authModes:type_name -> authd.GAMResponse.AuthenticationMode
+	33, // 31: gdm.Events.AuthEvent.response:type_name -> authd.IAResponse
+	34, // 32: gdm.Events.IsAuthenticatedRequested.authentication_data:type_name -> authd.IARequest.AuthenticationData
+	29, // 33: gdm.Events.StageChanged.stage:type_name -> pam.Stage
+	30, // 34: gdm.Events.UiLayoutReceived.uiLayout:type_name -> authd.UILayout
+	35, // [35:35] is the sub-list for method output_type
+	35, // [35:35] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
 }
 
 func init() { file_gdm_proto_init() }
@@ -1903,6 +2048,8 @@ func file_gdm_proto_init() {
 		(*EventData_StartAuthentication)(nil),
 		(*EventData_UserSelected)(nil),
 		(*EventData_IsAuthenticatedCancelled)(nil),
+		(*EventData_WebAuthnTouchRequested)(nil),
+		(*EventData_AuthenticationProgress)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1910,7 +2057,7 @@ func file_gdm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gdm_proto_rawDesc), len(file_gdm_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   24,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
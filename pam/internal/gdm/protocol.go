@@ -185,7 +185,22 @@ func stringifyEventDataFiltered(ed *EventData) string {
 	if authData == nil {
 		return ed.String()
 	}
-	if _, ok = authData.Item.(*authd.IARequest_AuthenticationData_Secret); !ok {
+
+	filteredData := &authd.IARequest_AuthenticationData{}
+	switch item := authData.Item.(type) {
+	case *authd.IARequest_AuthenticationData_Secret:
+		filteredData.Item = &authd.IARequest_AuthenticationData_Secret{
+			Secret: "**************",
+		}
+	case *authd.IARequest_AuthenticationData_Fields:
+		secrets := make(map[string]string, len(item.Fields.GetSecrets()))
+		for id := range item.Fields.GetSecrets() {
+			secrets[id] = "**************"
+		}
+		filteredData.Item = &authd.IARequest_AuthenticationData_Fields{
+			Fields: &authd.IARequest_AuthenticationData_FieldSecrets{Secrets: secrets},
+		}
+	default:
 		return ed.String()
 	}
 
@@ -193,11 +208,7 @@ func stringifyEventDataFiltered(ed *EventData) string {
 		Type: ed.Type,
 		Data: &EventData_IsAuthenticatedRequested{
 			IsAuthenticatedRequested: &Events_IsAuthenticatedRequested{
-				AuthenticationData: &authd.IARequest_AuthenticationData{
-					Item: &authd.IARequest_AuthenticationData_Secret{
-						Secret: "**************",
-					},
-				},
+				AuthenticationData: filteredData,
 			},
 		},
 	}).String()
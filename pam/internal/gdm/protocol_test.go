@@ -711,6 +711,24 @@ func TestSafeString(t *testing.T) {
 			wantString:     `type:isAuthenticatedRequested isAuthenticatedRequested:{authentication_data:{secret:"SuperSecretValue!#DON'T SHARE!"}}`,
 			wantSafeString: `type:isAuthenticatedRequested isAuthenticatedRequested:{authentication_data:{secret:"**************"}}`,
 		},
+		"AuthenticatedRequest_with_fields_is_stringified_without_their_values": {
+			eventData: &gdm.EventData{
+				Type: gdm.EventType_isAuthenticatedRequested,
+				Data: &gdm.EventData_IsAuthenticatedRequested{
+					&gdm.Events_IsAuthenticatedRequested{
+						AuthenticationData: &authd.IARequest_AuthenticationData{
+							Item: &authd.IARequest_AuthenticationData_Fields{
+								Fields: &authd.IARequest_AuthenticationData_FieldSecrets{
+									Secrets: map[string]string{"pin": "1234"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantString:     `type:isAuthenticatedRequested isAuthenticatedRequested:{authentication_data:{fields:{secrets:{key:"pin" value:"1234"}}}}`,
+			wantSafeString: `type:isAuthenticatedRequested isAuthenticatedRequested:{authentication_data:{fields:{secrets:{key:"pin" value:"**************"}}}}`,
+		},
 	}
 
 	for name, tc := range tests {
@@ -1,20 +1,26 @@
 package pam_test
 
 import (
+	"cmp"
 	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/sha512"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 	"github.com/ubuntu/authd/internal/proto/authd"
@@ -31,8 +37,11 @@ type options struct {
 	getPreviousBrokerRet string
 	getPreviousBrokerErr error
 
-	selectBrokerRet *authd.SBResponse
-	selectBrokerErr error
+	getBrokerIconRet map[string]*authd.GBIResponse
+
+	selectBrokerRet      *authd.SBResponse
+	selectBrokerErr      error
+	selectBrokerFallback string
 
 	getAuthenticationModesRet []*authd.GAMResponse_AuthenticationMode
 	getAuthenticationModesErr error
@@ -44,20 +53,35 @@ type options struct {
 	isAuthenticatedErr        error
 	isAuthenticatedWantSecret string
 	isAuthenticatedWantSkip   bool
+	isAuthenticatedWantChoice string
+	isAuthenticatedWantFields map[string]string
 	isAuthenticatedWantWait   time.Duration
 	isAuthenticatedMessage    string
 	isAuthenticatedMaxRetries int
+	isAuthenticatedProgress   []string
+
+	isAuthenticatedWantWebAuthnKey       *ecdsa.PublicKey
+	isAuthenticatedWantWebAuthnRPID      string
+	isAuthenticatedWantWebAuthnChallenge []byte
 
 	endSessionErr error
 
 	defaultBrokerForUser       map[string]string
 	setDefaultBrokerForUserErr error
 
+	checkAccountRet *authd.CAResponse
+	checkAccountErr error
+
+	userSessionRet *authd.USResponse
+	userSessionErr error
+
 	uiLayouts map[string]*authd.UILayout
 	authModes map[string]*authd.GAMResponse_AuthenticationMode
 
 	ignoreSessionIDChecks     bool
 	ignoreSessionIDGeneration bool
+
+	x25519Encryption bool
 }
 
 // DummyClient is a dummy implementation of [authd.PAMClient].
@@ -67,6 +91,8 @@ type DummyClient struct {
 
 	privateKey    *rsa.PrivateKey
 	encryptionKey string
+	// x25519Key is the key of the current session, when using X25519 encryption.
+	x25519Key *ecdh.PrivateKey
 
 	currentSessionID string
 	selectedBrokerID string
@@ -100,6 +126,16 @@ func WithGetPreviousBrokerReturn(ret string, err error) func(o *options) {
 	}
 }
 
+// WithBrokerIcon is the option to define the GetBrokerIcon return value for a broker.
+func WithBrokerIcon(brokerID string, ret *authd.GBIResponse) func(o *options) {
+	return func(o *options) {
+		if o.getBrokerIconRet == nil {
+			o.getBrokerIconRet = make(map[string]*authd.GBIResponse)
+		}
+		o.getBrokerIconRet[brokerID] = ret
+	}
+}
+
 // WithSelectBrokerReturn is the option to define the SelectBroker return values.
 func WithSelectBrokerReturn(ret *authd.SBResponse, err error) func(o *options) {
 	return func(o *options) {
@@ -108,6 +144,14 @@ func WithSelectBrokerReturn(ret *authd.SBResponse, err error) func(o *options) {
 	}
 }
 
+// WithSelectBrokerFallback is the option to start the sessions on a fallback broker, as if the selected one was
+// unavailable.
+func WithSelectBrokerFallback(brokerID string) func(o *options) {
+	return func(o *options) {
+		o.selectBrokerFallback = brokerID
+	}
+}
+
 // WithGetAuthenticationModesReturn is the option to define the GetAuthenticationModes return values.
 func WithGetAuthenticationModesReturn(ret []*authd.GAMResponse_AuthenticationMode, err error) func(o *options) {
 	return func(o *options) {
@@ -146,6 +190,38 @@ func WithIsAuthenticatedWantSkip() func(o *options) {
 	}
 }
 
+// WithIsAuthenticatedWantChoice is the option to define the IsAuthenticated wanted choice.
+func WithIsAuthenticatedWantChoice(choice string) func(o *options) {
+	return func(o *options) {
+		o.isAuthenticatedWantChoice = choice
+	}
+}
+
+// WithIsAuthenticatedWantFields is the option to define the IsAuthenticated wanted field values.
+func WithIsAuthenticatedWantFields(fields map[string]string) func(o *options) {
+	return func(o *options) {
+		o.isAuthenticatedWantFields = fields
+	}
+}
+
+// WithIsAuthenticatedProgress is the option to define the progress messages sent by IsAuthenticatedStream before its
+// result.
+func WithIsAuthenticatedProgress(messages ...string) func(o *options) {
+	return func(o *options) {
+		o.isAuthenticatedProgress = messages
+	}
+}
+
+// WithIsAuthenticatedWantWebAuthn is the option to define the IsAuthenticated wanted webauthn assertion signer,
+// relying party and challenge.
+func WithIsAuthenticatedWantWebAuthn(publicKey *ecdsa.PublicKey, rpID string, challenge []byte) func(o *options) {
+	return func(o *options) {
+		o.isAuthenticatedWantWebAuthnKey = publicKey
+		o.isAuthenticatedWantWebAuthnRPID = rpID
+		o.isAuthenticatedWantWebAuthnChallenge = challenge
+	}
+}
+
 // WithIsAuthenticatedWantWait is the option to define the IsAuthenticated wait duration.
 func WithIsAuthenticatedWantWait(wait time.Duration) func(o *options) {
 	return func(o *options) {
@@ -181,6 +257,23 @@ func WithSetDefaultBrokerReturn(err error) func(o *options) {
 	}
 }
 
+// WithCheckAccountReturn is the option to define the CheckAccount return values.
+func WithCheckAccountReturn(ret *authd.CAResponse, err error) func(o *options) {
+	return func(o *options) {
+		o.checkAccountRet = ret
+		o.checkAccountErr = err
+	}
+}
+
+// WithUserSessionReturn is the option to define the OpenUserSession, CloseUserSession and SetUserCredentials return
+// values.
+func WithUserSessionReturn(ret *authd.USResponse, err error) func(o *options) {
+	return func(o *options) {
+		o.userSessionRet = ret
+		o.userSessionErr = err
+	}
+}
+
 // WithUILayout is the option to define the UI layouts supported return values.
 func WithUILayout(authModeID string, label string, uiLayout *authd.UILayout) func(o *options) {
 	return func(o *options) {
@@ -203,6 +296,13 @@ func WithIgnoreSessionIDGeneration() func(o *options) {
 	}
 }
 
+// WithX25519Encryption is the option to start the sessions with a new X25519 key instead of the RSA one.
+func WithX25519Encryption() func(o *options) {
+	return func(o *options) {
+		o.x25519Encryption = true
+	}
+}
+
 // NewDummyClient returns a Dummy client with the given options.
 func NewDummyClient(privateKey *rsa.PrivateKey, args ...DummyClientOptions) *DummyClient {
 	// Set default options.
@@ -235,7 +335,7 @@ func NewDummyClient(privateKey *rsa.PrivateKey, args ...DummyClientOptions) *Dum
 }
 
 // AvailableBrokers simulates AvailableBrokers using the provided parameters.
-func (dc *DummyClient) AvailableBrokers(ctx context.Context, in *authd.Empty, opts ...grpc.CallOption) (*authd.ABResponse, error) {
+func (dc *DummyClient) AvailableBrokers(ctx context.Context, in *authd.ABRequest, opts ...grpc.CallOption) (*authd.ABResponse, error) {
 	log.Debugf(ctx, "AvailableBrokers Called: %#v", in)
 	dc.mu.Lock()
 	defer dc.mu.Unlock()
@@ -270,6 +370,21 @@ func (dc *DummyClient) GetPreviousBroker(ctx context.Context, in *authd.GPBReque
 	return &authd.GPBResponse{PreviousBroker: brokerID}, nil
 }
 
+// GetBrokerIcon simulates GetBrokerIcon using the provided parameters.
+func (dc *DummyClient) GetBrokerIcon(ctx context.Context, in *authd.GBIRequest, opts ...grpc.CallOption) (*authd.GBIResponse, error) {
+	log.Debugf(ctx, "GetBrokerIcon Called: %#v", in)
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if in == nil || in.BrokerId == "" {
+		return nil, errors.New("no broker ID provided")
+	}
+	icon, ok := dc.getBrokerIconRet[in.BrokerId]
+	if !ok {
+		return nil, fmt.Errorf("broker %q has no icon", in.BrokerId)
+	}
+	return icon, nil
+}
+
 // SelectBroker simulates SelectBroker using the provided parameters.
 func (dc *DummyClient) SelectBroker(ctx context.Context, in *authd.SBRequest, opts ...grpc.CallOption) (*authd.SBResponse, error) {
 	log.Debugf(ctx, "SelectBroker Called: %#v", in)
@@ -318,13 +433,33 @@ func (dc *DummyClient) SelectBroker(ctx context.Context, in *authd.SBRequest, op
 	}) {
 		return nil, fmt.Errorf("broker %q not found", in.BrokerId)
 	}
-	dc.selectedBrokerID = in.BrokerId
+	brokerID := cmp.Or(dc.selectBrokerFallback, in.BrokerId)
+	dc.selectedBrokerID = brokerID
 	dc.selectedLang = in.Lang
 	dc.selectedUsername = in.Username
 	dc.currentSessionID = sessionID
+
+	if dc.x25519Encryption {
+		if !slices.Contains(in.SupportedEncryptionAlgorithms, authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305) {
+			return nil, errors.New("X25519 encryption is not supported by the client")
+		}
+		key, encryptionKey, err := encryption.NewX25519Key()
+		if err != nil {
+			return nil, err
+		}
+		dc.x25519Key = key
+		return &authd.SBResponse{
+			SessionId:           dc.currentSessionID,
+			EncryptionKey:       encryptionKey,
+			BrokerId:            brokerID,
+			EncryptionAlgorithm: authd.EncryptionAlgorithm_ENCRYPTION_X25519_CHACHA20_POLY1305,
+		}, nil
+	}
+
 	return &authd.SBResponse{
 		SessionId:     dc.currentSessionID,
 		EncryptionKey: dc.encryptionKey,
+		BrokerId:      brokerID,
 	}, nil
 }
 
@@ -452,28 +587,128 @@ func (dc *DummyClient) IsAuthenticated(ctx context.Context, in *authd.IARequest,
 			return nil, errors.New("no wanted skip requested")
 		}
 		return &authd.IAResponse{Msg: msg}, nil
+	case *authd.IARequest_AuthenticationData_Fields:
+		if dc.isAuthenticatedWantFields == nil {
+			return nil, errors.New("no wanted fields provided")
+		}
+		return dc.handleFields(item.Fields.GetSecrets(), msg)
+	case *authd.IARequest_AuthenticationData_Choice:
+		if dc.isAuthenticatedWantChoice == "" {
+			return nil, errors.New("no wanted choice provided")
+		}
+		if item.Choice != dc.isAuthenticatedWantChoice {
+			return &authd.IAResponse{Access: auth.Denied, Msg: msg}, nil
+		}
+		return &authd.IAResponse{Access: auth.Granted, Msg: msg}, nil
+	case *authd.IARequest_AuthenticationData_Webauthn:
+		if dc.isAuthenticatedWantWebAuthnKey == nil {
+			return nil, errors.New("no wanted webauthn assertion provided")
+		}
+		if err := VerifyWebAuthnAssertion(dc.isAuthenticatedWantWebAuthnKey, dc.isAuthenticatedWantWebAuthnRPID,
+			dc.isAuthenticatedWantWebAuthnChallenge, item.Webauthn); err != nil {
+			log.Debugf(ctx, "Invalid webauthn assertion: %v", err)
+			return &authd.IAResponse{Access: auth.Denied, Msg: msg}, nil
+		}
+		return &authd.IAResponse{Access: auth.Granted, Msg: msg}, nil
 	default:
 		return nil, errors.New("no authentication data provided")
 	}
 }
 
-func (dc *DummyClient) handleChallenge(secret string, msg string) (*authd.IAResponse, error) {
-	if secret == "" {
-		return nil, errors.New("no secret provided")
+// IsAuthenticatedStream simulates IsAuthenticatedStream through the dummy client, streaming the progress messages
+// before the IsAuthenticated result.
+func (dc *DummyClient) IsAuthenticatedStream(ctx context.Context, in *authd.IARequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[authd.IAStreamResponse], error) {
+	dc.mu.Lock()
+	progress := slices.Clone(dc.isAuthenticatedProgress)
+	dc.mu.Unlock()
+
+	return &dummyIsAuthenticatedStream{
+		progress: progress,
+		result: func() (*authd.IAResponse, error) {
+			return dc.IsAuthenticated(ctx, in, opts...)
+		},
+	}, nil
+}
+
+// dummyIsAuthenticatedStream is the client stream of [DummyClient.IsAuthenticatedStream].
+type dummyIsAuthenticatedStream struct {
+	grpc.ClientStream
+
+	progress []string
+	result   func() (*authd.IAResponse, error)
+	done     bool
+}
+
+// Recv returns the next progress message, then the result, and finally the end of the stream.
+func (s *dummyIsAuthenticatedStream) Recv() (*authd.IAStreamResponse, error) {
+	if len(s.progress) > 0 {
+		message := s.progress[0]
+		s.progress = s.progress[1:]
+		return &authd.IAStreamResponse{
+			Response: &authd.IAStreamResponse_Progress{Progress: message},
+		}, nil
 	}
-	ciphertext, err := base64.StdEncoding.DecodeString(secret)
+
+	if s.done {
+		return nil, io.EOF
+	}
+	s.done = true
+
+	res, err := s.result()
 	if err != nil {
 		return nil, err
 	}
+	return &authd.IAStreamResponse{
+		Response: &authd.IAStreamResponse_Result{Result: res},
+	}, nil
+}
+
+func (dc *DummyClient) handleFields(secrets map[string]string, msg string) (*authd.IAResponse, error) {
+	if len(secrets) != len(dc.isAuthenticatedWantFields) {
+		return &authd.IAResponse{Access: auth.Denied, Msg: msg}, nil
+	}
+	for id, secret := range secrets {
+		plaintext, err := dc.decryptSecret(secret)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", id, err)
+		}
+		if want, ok := dc.isAuthenticatedWantFields[id]; !ok || plaintext != want {
+			return &authd.IAResponse{Access: auth.Denied, Msg: msg}, nil
+		}
+	}
+	return &authd.IAResponse{Access: auth.Granted, Msg: msg}, nil
+}
+
+func (dc *DummyClient) decryptSecret(secret string) (string, error) {
+	if dc.x25519Key != nil {
+		brokerSessionID := brokers.BrokerSessionID(dc.selectedBrokerID, dc.currentSessionID)
+		return encryption.DecryptX25519(dc.x25519Key, brokerSessionID, secret)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
 	if dc.privateKey == nil {
-		return nil, errors.New("no private key defined")
+		return "", errors.New("no private key defined")
 	}
 	plaintext, err := rsa.DecryptOAEP(sha512.New(), nil, dc.privateKey, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (dc *DummyClient) handleChallenge(secret string, msg string) (*authd.IAResponse, error) {
+	if secret == "" {
+		return nil, errors.New("no secret provided")
+	}
+	plaintext, err := dc.decryptSecret(secret)
 	if err != nil {
 		return nil, err
 	}
 
-	if string(plaintext) == dc.isAuthenticatedWantSecret {
+	if plaintext == dc.isAuthenticatedWantSecret {
 		return &authd.IAResponse{
 			Access: auth.Granted,
 			Msg:    msg,
@@ -537,6 +772,79 @@ func (dc *DummyClient) SetDefaultBrokerForUser(ctx context.Context, in *authd.SD
 	return &authd.Empty{}, nil
 }
 
+// CheckAccount simulates CheckAccount using the provided parameters.
+func (dc *DummyClient) CheckAccount(ctx context.Context, in *authd.CARequest, opts ...grpc.CallOption) (*authd.CAResponse, error) {
+	log.Debugf(ctx, "CheckAccount Called: %#v", in)
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.checkAccountErr != nil {
+		return nil, dc.checkAccountErr
+	}
+	if in == nil {
+		return nil, errors.New("no input values provided")
+	}
+	if in.Username == "" {
+		return nil, errors.New("no valid username provided")
+	}
+	if dc.checkAccountRet != nil {
+		return dc.checkAccountRet, nil
+	}
+	return &authd.CAResponse{Status: authd.AccountStatus_ACCOUNT_NOT_CHECKED}, nil
+}
+
+// OpenUserSession simulates OpenUserSession using the provided parameters.
+func (dc *DummyClient) OpenUserSession(ctx context.Context, in *authd.USRequest, opts ...grpc.CallOption) (*authd.USResponse, error) {
+	log.Debugf(ctx, "OpenUserSession Called: %#v", in)
+	return dc.userSession(in.GetUsername())
+}
+
+// CloseUserSession simulates CloseUserSession using the provided parameters.
+func (dc *DummyClient) CloseUserSession(ctx context.Context, in *authd.USRequest, opts ...grpc.CallOption) (*authd.USResponse, error) {
+	log.Debugf(ctx, "CloseUserSession Called: %#v", in)
+	return dc.userSession(in.GetUsername())
+}
+
+// SetUserCredentials simulates SetUserCredentials using the provided parameters.
+func (dc *DummyClient) SetUserCredentials(ctx context.Context, in *authd.SUCRequest, opts ...grpc.CallOption) (*authd.USResponse, error) {
+	log.Debugf(ctx, "SetUserCredentials Called: %#v", in)
+	if in.GetAction() == authd.CredentialsAction_CREDENTIALS_UNSPECIFIED {
+		return nil, errors.New("no valid credentials action provided")
+	}
+	return dc.userSession(in.GetUsername())
+}
+
+func (dc *DummyClient) userSession(username string) (*authd.USResponse, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.userSessionErr != nil {
+		return nil, dc.userSessionErr
+	}
+	if username == "" {
+		return nil, errors.New("no valid username provided")
+	}
+	if dc.userSessionRet != nil {
+		return dc.userSessionRet, nil
+	}
+	return &authd.USResponse{}, nil
+}
+
+// ListSessions simulates ListSessions using the current session, if any.
+func (dc *DummyClient) ListSessions(ctx context.Context, in *authd.Empty, opts ...grpc.CallOption) (*authd.LSResponse, error) {
+	log.Debugf(ctx, "ListSessions Called: %#v", in)
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.currentSessionID == "" {
+		return &authd.LSResponse{}, nil
+	}
+	return &authd.LSResponse{
+		Sessions: []*authd.LSResponse_SessionInfo{{
+			SessionId: dc.currentSessionID,
+			BrokerId:  dc.selectedBrokerID,
+			Username:  dc.selectedUsername,
+		}},
+	}, nil
+}
+
 // Utility functions for testing purposes.
 
 // SelectedUsername returns the selected Username on the client.
@@ -575,6 +883,11 @@ func WithButton(label string) func(l *authd.UILayout) {
 	return func(l *authd.UILayout) { l.Button = &label }
 }
 
+// WithEntry is an option for [FormUILayout] to set the entry parameter.
+func WithEntry(entry string) func(l *authd.UILayout) {
+	return func(l *authd.UILayout) { l.Entry = &entry }
+}
+
 // WithWait is an option for [FormUILayout] to enable wait in FormUI UI.
 func WithWait(hasWait bool) func(l *authd.UILayout) {
 	wait := (*string)(nil)
@@ -584,6 +897,17 @@ func WithWait(hasWait bool) func(l *authd.UILayout) {
 	return func(l *authd.UILayout) { l.Wait = wait }
 }
 
+// WithFields is an option for [FormUILayout] to set the fields of a multi-field form.
+func WithFields(fields ...*authd.UILayout_Field) func(l *authd.UILayout) {
+	return func(l *authd.UILayout) { l.Fields = fields }
+}
+
+// WithSupportsFields is an option for [FormUILayout] to declare the support of multi-field forms.
+func WithSupportsFields() func(l *authd.UILayout) {
+	supportsFields := true
+	return func(l *authd.UILayout) { l.SupportsFields = &supportsFields }
+}
+
 // FormUILayout returns an [authd.UILayout] for forms.
 func FormUILayout(opts ...UIOptions) *authd.UILayout {
 	required := layouts.Optional
@@ -650,3 +974,41 @@ func NewPasswordUILayout() *authd.UILayout {
 		Button: &optional,
 	}
 }
+
+// ChoiceUILayout returns an [authd.UILayout] for choice lists.
+func ChoiceUILayout(choices ...*authd.UILayout_Choice) *authd.UILayout {
+	optional := layouts.Optional
+	return &authd.UILayout{
+		Type:    layouts.Choice,
+		Label:   &optional,
+		Choices: choices,
+	}
+}
+
+// WithWebAuthnChallenge is an option for [WebAuthnUILayout] to set the challenge to sign for the relying party, with
+// one of the allowed credentials if any.
+func WithWebAuthnChallenge(challenge []byte, rpID string, credentials ...[]byte) func(l *authd.UILayout) {
+	encodedChallenge := base64.StdEncoding.EncodeToString(challenge)
+	encodedCredentials := layouts.FormatCredentials(credentials...)
+	return func(l *authd.UILayout) {
+		l.Challenge = &encodedChallenge
+		l.RpId = &rpID
+		l.Credentials = &encodedCredentials
+	}
+}
+
+// WebAuthnUILayout returns an [authd.UILayout] for webauthn security keys.
+func WebAuthnUILayout(opts ...UIOptions) *authd.UILayout {
+	required, optional := layouts.Required, layouts.Optional
+	uiLayout := &authd.UILayout{
+		Type:        layouts.WebAuthn,
+		Label:       &optional,
+		Challenge:   &required,
+		RpId:        &required,
+		Credentials: &optional,
+	}
+	for _, f := range opts {
+		f(uiLayout)
+	}
+	return uiLayout
+}
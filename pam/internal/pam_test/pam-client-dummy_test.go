@@ -124,6 +124,51 @@ func TestGetPreviousBroker(t *testing.T) {
 	}
 }
 
+func TestGetBrokerIcon(t *testing.T) {
+	t.Parallel()
+
+	icon := &authd.GBIResponse{
+		Data:        []byte("<svg/>"),
+		ContentType: "image/svg+xml",
+		Hash:        "some-hash",
+	}
+
+	testCases := map[string]struct {
+		client authd.PAMClient
+		args   *authd.GBIRequest
+
+		wantRet   *authd.GBIResponse
+		wantError error
+	}{
+		"With_defined_icon": {
+			client:  NewDummyClient(nil, WithBrokerIcon("testBroker", icon)),
+			args:    &authd.GBIRequest{BrokerId: "testBroker"},
+			wantRet: icon,
+		},
+
+		// Error cases
+		"Error_with_missing_broker": {
+			client:    NewDummyClient(nil, WithBrokerIcon("testBroker", icon)),
+			args:      &authd.GBIRequest{},
+			wantError: errors.New("no broker ID provided"),
+		},
+		"Error_with_broker_without_icon": {
+			client:    NewDummyClient(nil, WithBrokerIcon("testBroker", icon)),
+			args:      &authd.GBIRequest{BrokerId: "otherBroker"},
+			wantError: errors.New(`broker "otherBroker" has no icon`),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ret, err := tc.client.GetBrokerIcon(context.TODO(), tc.args)
+			require.Equal(t, tc.wantError, err)
+			require.Equal(t, tc.wantRet, ret)
+		})
+	}
+}
+
 func TestSelectBroker(t *testing.T) {
 	t.Parallel()
 
@@ -166,7 +211,7 @@ func TestSelectBroker(t *testing.T) {
 					},
 				}, nil)),
 			args:                   &authd.SBRequest{BrokerId: "test-broker"},
-			wantRet:                &authd.SBResponse{},
+			wantRet:                &authd.SBResponse{BrokerId: "test-broker"},
 			wantGeneratedSessionID: true,
 		},
 		"With_valid_args_and_empty_return_value_with_ignored_ID_generation": {
@@ -180,7 +225,7 @@ func TestSelectBroker(t *testing.T) {
 					},
 				}, nil)),
 			args:    &authd.SBRequest{BrokerId: "test-broker"},
-			wantRet: &authd.SBResponse{},
+			wantRet: &authd.SBResponse{BrokerId: "test-broker"},
 		},
 		"With_valid_args_and_defined_return_value": {
 			client: NewDummyClient(nil,
@@ -212,6 +257,7 @@ func TestSelectBroker(t *testing.T) {
 			args: &authd.SBRequest{BrokerId: "test-broker"},
 			wantRet: &authd.SBResponse{
 				EncryptionKey: wantEncryptionKey,
+				BrokerId:      "test-broker",
 			},
 			wantGeneratedSessionID: true,
 		},
@@ -228,6 +274,7 @@ func TestSelectBroker(t *testing.T) {
 			args: &authd.SBRequest{BrokerId: "test-broker"},
 			wantRet: &authd.SBResponse{
 				EncryptionKey: wantEncryptionKey,
+				BrokerId:      "test-broker",
 			},
 		},
 		"With_private_key_and_valid_args_and_defined_return_value": {
@@ -288,7 +335,7 @@ func TestSelectBroker(t *testing.T) {
 			},
 			reselectAgainUser:      "an-user",
 			wantGeneratedSessionID: true,
-			wantRet:                &authd.SBResponse{},
+			wantRet:                &authd.SBResponse{BrokerId: "test-broker"},
 		},
 		"Starting_a_session_for_another_user_is_fine_when_ignoring_ID_checks": {
 			client: NewDummyClient(nil,
@@ -306,7 +353,7 @@ func TestSelectBroker(t *testing.T) {
 				Username: "an-user",
 			},
 			reselectAgainUser: "another-user",
-			wantRet:           &authd.SBResponse{},
+			wantRet:           &authd.SBResponse{BrokerId: "test-broker"},
 		},
 
 		// Error cases
@@ -0,0 +1,131 @@
+package pam_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+const (
+	webAuthnFlagUserPresent = 0x01
+	webAuthnAuthDataMinLen  = sha256.Size + 1 + 4
+)
+
+// SoftwareAuthenticator is a FIDO2 authenticator implemented in software, standing in for a security key.
+type SoftwareAuthenticator struct {
+	credentialID []byte
+	userHandle   []byte
+	key          *ecdsa.PrivateKey
+
+	mu        sync.Mutex
+	signCount uint32
+}
+
+// NewSoftwareAuthenticator creates a new [SoftwareAuthenticator] with a new P-256 key for the credential.
+func NewSoftwareAuthenticator(credentialID, userHandle []byte) (*SoftwareAuthenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &SoftwareAuthenticator{
+		credentialID: credentialID,
+		userHandle:   userHandle,
+		key:          key,
+	}, nil
+}
+
+// PublicKey returns the public key of the credential of the authenticator.
+func (a *SoftwareAuthenticator) PublicKey() *ecdsa.PublicKey {
+	return &a.key.PublicKey
+}
+
+// GetAssertion returns the assertion of the client data for the relying party, as a security key that has been
+// touched would do. It fails if the credential of the authenticator is not part of the allowed ones.
+func (a *SoftwareAuthenticator) GetAssertion(ctx context.Context, rpID string, clientData []byte, allowedCredentials [][]byte) (*authd.IARequest_AuthenticationData_WebAuthnAssertion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(allowedCredentials) > 0 && !slices.ContainsFunc(allowedCredentials, func(id []byte) bool {
+		return bytes.Equal(id, a.credentialID)
+	}) {
+		return nil, errors.New("no valid credentials provided")
+	}
+
+	a.mu.Lock()
+	a.signCount++
+	signCount := a.signCount
+	a.mu.Unlock()
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], webAuthnFlagUserPresent)
+	authData = binary.BigEndian.AppendUint32(authData, signCount)
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, webAuthnSignedDigest(authData, clientData))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authd.IARequest_AuthenticationData_WebAuthnAssertion{
+		CredentialId:      a.credentialID,
+		AuthenticatorData: authData,
+		Signature:         signature,
+		UserHandle:        a.userHandle,
+	}, nil
+}
+
+// VerifyWebAuthnAssertion checks that the assertion was made with the private key of publicKey for the relying party
+// and client data of the challenge, while the user was present.
+func VerifyWebAuthnAssertion(publicKey *ecdsa.PublicKey, rpID string, challenge []byte, assertion *authd.IARequest_AuthenticationData_WebAuthnAssertion) error {
+	clientData := assertion.GetClientDataJson()
+	var gotClientData layouts.ClientData
+	if err := json.Unmarshal(clientData, &gotClientData); err != nil {
+		return fmt.Errorf("invalid client data: %v", err)
+	}
+	wantClientData := layouts.ClientData{
+		Type:      "webauthn.get",
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    "https://" + rpID,
+	}
+	if gotClientData != wantClientData {
+		return fmt.Errorf("client data %#v does not match %#v", gotClientData, wantClientData)
+	}
+
+	authData := assertion.GetAuthenticatorData()
+	if len(authData) < webAuthnAuthDataMinLen {
+		return errors.New("authenticator data is too short")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	if !bytes.Equal(authData[:sha256.Size], rpIDHash[:]) {
+		return errors.New("assertion is not for the relying party")
+	}
+	if authData[sha256.Size]&webAuthnFlagUserPresent == 0 {
+		return errors.New("user was not present")
+	}
+
+	if !ecdsa.VerifyASN1(publicKey, webAuthnSignedDigest(authData, clientData), assertion.GetSignature()) {
+		return errors.New("invalid assertion signature")
+	}
+	return nil
+}
+
+// webAuthnSignedDigest returns the digest signed by the authenticator, which covers the authenticator data and the
+// hash of the client data.
+func webAuthnSignedDigest(authData, clientData []byte) []byte {
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(slices.Clone(authData), clientDataHash[:]...))
+	return digest[:]
+}
@@ -60,6 +60,9 @@ var supportedArgs = []string{
 	"connection_timeout",  // The timeout on connecting to authd socket in milliseconds (defaults to 2 seconds).
 	"force_native_client", // Use native PAM client instead of custom UIs.
 	"force_reauth",        // Whether the authentication should be performed again even if it has been already completed.
+	"offline_account",     // Whether accounts are allowed ("allow", the default) or denied ("deny") when their broker can't check them.
+	"try_first_pass",      // Authenticate with the password set by a previous module, if any, before prompting.
+	"use_first_pass",      // Authenticate with the password set by a previous module only, never prompting for it.
 }
 
 // parseArgs parses the PAM arguments and returns a map of them and a function that logs the parsing issues.
@@ -219,8 +222,8 @@ func (h *pamModule) ChangeAuthTok(mTx pam.ModuleTransaction, flags pam.Flags, ar
 }
 
 func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTransaction, flags pam.Flags, parsedArgs map[string]string, logArgsIssues func()) (err error) {
-	// Initialize localization
-	// TODO
+	// The messages are shown in the language of the user, for the whole transaction.
+	lang := adapter.SessionLang(mTx)
 
 	var pamClientType adapter.PamClientType
 	var teaOpts []tea.ProgramOption
@@ -241,7 +244,7 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 
 	if mode == authd.SessionMode_CHANGE_PASSWORD && flags&pam.PrelimCheck != 0 {
 		log.Debug(context.TODO(), "ChangeAuthTok, preliminary check")
-		c, closeConn, err := newClient(parsedArgs)
+		c, closeConn, err := newClient(parsedArgs, lang)
 		if err != nil {
 			log.Debugf(context.TODO(), "%s", err)
 			return fmt.Errorf("%w: %w", pam.ErrTryAgain, err)
@@ -304,7 +307,12 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 		teaOpts = append(teaOpts, modeOpts...)
 	}
 
-	conn, closeConn, err := newClientConnection(parsedArgs)
+	firstPass, err := parseFirstPassPolicy(parsedArgs)
+	if err != nil {
+		return fmt.Errorf("%w: %w", pam.ErrSystem, err)
+	}
+
+	conn, closeConn, err := newClientConnection(parsedArgs, lang)
 	if err != nil {
 		if err := showPamMessage(mTx, pam.ErrorMsg, err.Error()); err != nil {
 			log.Warningf(context.TODO(), "Impossible to show PAM message: %v", err)
@@ -318,7 +326,7 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 	}
 
 	var exitStatus adapter.PamReturnStatus
-	appState := adapter.NewUIModel(mTx, pamClientType, mode, conn, &exitStatus)
+	appState := adapter.NewUIModel(mTx, pamClientType, mode, lang, conn, firstPass, &exitStatus)
 	teaOpts = append(teaOpts, tea.WithFilter(adapter.MsgFilter))
 	p := tea.NewProgram(appState, teaOpts...)
 	if _, err := p.Run(); err != nil {
@@ -343,7 +351,9 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 	}
 }
 
-// AcctMgmt sets any used brokerID as default for the user.
+// AcctMgmt sets any used brokerID as default for the user. If the user did not authenticate with authd in this
+// transaction, such as when logging in with an SSH key, it asks the broker of the user whether their account can still
+// be used.
 func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []string) (err error) {
 	parsedArgs, logArgsIssues := parseArgs(args)
 	closeLogging, err := initLogging(mTx, parsedArgs, flags)
@@ -368,12 +378,12 @@ func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []
 
 	brokerData, err := mTx.GetData(authenticationBrokerIDKey)
 	if err != nil && errors.Is(err, pam.ErrNoModuleData) {
-		return pam.ErrIgnore
+		return checkAccount(mTx, parsedArgs)
 	}
 	if brokerData == nil {
 		// PAM can return no data without an error after that has been unset:
 		// See: https://github.com/linux-pam/linux-pam/pull/780
-		return pam.ErrIgnore
+		return checkAccount(mTx, parsedArgs)
 	}
 
 	brokerIDUsedToAuthenticate, ok := brokerData.(string)
@@ -405,7 +415,7 @@ func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []
 		return pam.ErrIgnore
 	}
 
-	client, closeConn, err := newClient(parsedArgs)
+	client, closeConn, err := newClient(parsedArgs, adapter.SessionLang(mTx))
 	if err != nil {
 		log.Debugf(context.TODO(), "%s", err)
 		return pam.ErrAuthinfoUnavail
@@ -427,10 +437,126 @@ func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []
 	return nil
 }
 
-func newClientConnection(args map[string]string) (conn *grpc.ClientConn, closeConn func(), err error) {
+// checkAccount asks the broker of the user whether their account can still be used. When offline accounts are denied,
+// the account is also denied if it can't be checked for any reason.
+func checkAccount(mTx pam.ModuleTransaction, args map[string]string) error {
+	denyOffline, err := parseOfflineAccountPolicy(args)
+	if err != nil {
+		log.Errorf(context.TODO(), "%v", err)
+		return pam.ErrPermDenied
+	}
+
+	user, err := mTx.GetItem(pam.User)
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return pam.ErrIgnore
+	}
+
+	client, closeConn, err := newClient(args, adapter.SessionLang(mTx))
+	if err != nil {
+		log.Debugf(context.TODO(), "%s", err)
+		if denyOffline {
+			return pam.ErrPermDenied
+		}
+		return pam.ErrAuthinfoUnavail
+	}
+	defer closeConn()
+
+	resp, err := client.CheckAccount(context.TODO(), &authd.CARequest{Username: user})
+	if err != nil {
+		log.Warningf(context.TODO(), "Could not check account of user %q: %v", user, err)
+		if denyOffline {
+			return pam.ErrPermDenied
+		}
+		return pam.ErrIgnore
+	}
+
+	if msg := resp.GetMsg(); msg != "" {
+		if err := showPamMessage(mTx, pam.ErrorMsg, msg); err != nil {
+			log.Warningf(context.TODO(), "Impossible to show PAM message: %v", err)
+		}
+	}
+
+	return accountStatusToPamError(resp.GetStatus(), denyOffline)
+}
+
+// parseOfflineAccountPolicy returns whether the accounts should be denied when their broker can't check them.
+func parseOfflineAccountPolicy(args map[string]string) (deny bool, err error) {
+	switch policy := args["offline_account"]; policy {
+	case "", "allow":
+		return false, nil
+	case "deny":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid value %q for offline_account, denying accounts", policy)
+	}
+}
+
+// parseFirstPassPolicy returns how the password set by a previous module is used, use_first_pass taking precedence.
+func parseFirstPassPolicy(args map[string]string) (adapter.FirstPassPolicy, error) {
+	useFirstPass, err := parseFlag(args, "use_first_pass")
+	if err != nil {
+		return adapter.NoFirstPass, err
+	}
+	tryFirstPass, err := parseFlag(args, "try_first_pass")
+	if err != nil {
+		return adapter.NoFirstPass, err
+	}
+
+	if useFirstPass {
+		return adapter.UseFirstPass, nil
+	}
+	if tryFirstPass {
+		return adapter.TryFirstPass, nil
+	}
+	return adapter.NoFirstPass, nil
+}
+
+// parseFlag returns whether the flag argument is enabled, which is the case when it is set without a value or with a
+// true boolean value.
+func parseFlag(args map[string]string, name string) (bool, error) {
+	value, ok := args[name]
+	if !ok {
+		return false, nil
+	}
+	if value == "" {
+		return true, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for %s", value, name)
+	}
+	return enabled, nil
+}
+
+// accountStatusToPamError returns the PAM result matching the account status.
+func accountStatusToPamError(status authd.AccountStatus, denyOffline bool) error {
+	switch status {
+	case authd.AccountStatus_ACCOUNT_VALID:
+		return nil
+	case authd.AccountStatus_ACCOUNT_EXPIRED:
+		return pam.ErrAcctExpired
+	case authd.AccountStatus_ACCOUNT_PASSWORD_CHANGE_REQUIRED:
+		return pam.ErrNewAuthtokReqd
+	case authd.AccountStatus_ACCOUNT_DENIED:
+		return pam.ErrPermDenied
+	case authd.AccountStatus_ACCOUNT_BROKER_UNAVAILABLE:
+		if denyOffline {
+			return pam.ErrPermDenied
+		}
+		return pam.ErrIgnore
+	default:
+		return pam.ErrIgnore
+	}
+}
+
+func newClientConnection(args map[string]string, lang string) (conn *grpc.ClientConn, closeConn func(), err error) {
 	conn, err = grpc.NewClient("unix://"+getSocketPath(args),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage))
+		grpc.WithUnaryInterceptor(errmessages.FormatErrorMessageIn(lang)),
+		grpc.WithStreamInterceptor(errmessages.FormatErrorMessageStreamIn(lang)))
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not connect to authd: %v", err)
 	}
@@ -457,9 +583,9 @@ func newClientConnection(args map[string]string) (conn *grpc.ClientConn, closeCo
 	return conn, cleanup, err
 }
 
-// newClient returns a new GRPC client ready to emit requests.
-func newClient(args map[string]string) (client authd.PAMClient, closeConn func(), err error) {
-	conn, closeConn, err := newClientConnection(args)
+// newClient returns a new GRPC client ready to emit requests, whose errors are translated in lang.
+func newClient(args map[string]string, lang string) (client authd.PAMClient, closeConn func(), err error) {
+	conn, closeConn, err := newClientConnection(args, lang)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -474,17 +600,113 @@ func getSocketPath(args map[string]string) string {
 	return consts.DefaultSocketPath
 }
 
-// SetCred is the method that is invoked during pam_setcred request.
-func (h *pamModule) SetCred(pam.ModuleTransaction, pam.Flags, []string) error {
-	return pam.ErrIgnore
+// SetCred asks the broker of the user to establish, delete, reinitialize or refresh its credentials, setting the
+// environment variables it returns. It never fails, so that an issue with the broker can't prevent the user from
+// logging in.
+func (h *pamModule) SetCred(mTx pam.ModuleTransaction, flags pam.Flags, args []string) error {
+	return handleUserSession(mTx, flags, args, "SetCred", func(client authd.PAMClient, user string) (*authd.USResponse, error) {
+		return client.SetUserCredentials(context.TODO(), &authd.SUCRequest{
+			Username: user,
+			Action:   credentialsAction(flags),
+		})
+	})
+}
+
+// OpenSession notifies the broker of the user that a session is opened, setting the environment variables it returns.
+func (h *pamModule) OpenSession(mTx pam.ModuleTransaction, flags pam.Flags, args []string) error {
+	return handleUserSession(mTx, flags, args, "OpenSession", func(client authd.PAMClient, user string) (*authd.USResponse, error) {
+		return client.OpenUserSession(context.TODO(), &authd.USRequest{
+			Username:   user,
+			PamContext: adapter.GetPAMContext(mTx),
+		})
+	})
+}
+
+// CloseSession notifies the broker of the user that a session is closed.
+func (h *pamModule) CloseSession(mTx pam.ModuleTransaction, flags pam.Flags, args []string) error {
+	return handleUserSession(mTx, flags, args, "CloseSession", func(client authd.PAMClient, user string) (*authd.USResponse, error) {
+		return client.CloseUserSession(context.TODO(), &authd.USRequest{
+			Username:   user,
+			PamContext: adapter.GetPAMContext(mTx),
+		})
+	})
+}
+
+// handleUserSession sends the session or credentials request of the PAM action to authd and applies its response.
+// The action is ignored on any error.
+func handleUserSession(mTx pam.ModuleTransaction, flags pam.Flags, args []string, action string,
+	request func(client authd.PAMClient, user string) (*authd.USResponse, error)) (err error) {
+	parsedArgs, logArgsIssues := parseArgs(args)
+	closeLogging, err := initLogging(mTx, parsedArgs, flags)
+	defer closeLogging()
+	defer func() {
+		log.Debugf(context.TODO(), "%s: exiting with error %v", action, err)
+	}()
+	if err != nil {
+		log.Warningf(context.TODO(), "Impossible to initialize logging: %v", err)
+		return pam.ErrIgnore
+	}
+	logArgsIssues()
+
+	user, err := mTx.GetItem(pam.User)
+	if err != nil {
+		log.Warningf(context.TODO(), "Impossible to get PAM user: %v", err)
+		return pam.ErrIgnore
+	}
+	if user == "" {
+		return pam.ErrIgnore
+	}
+
+	client, closeConn, err := newClient(parsedArgs, adapter.SessionLang(mTx))
+	if err != nil {
+		log.Debugf(context.TODO(), "%s", err)
+		return pam.ErrIgnore
+	}
+	defer closeConn()
+
+	resp, err := request(client, user)
+	if err != nil {
+		log.Warningf(context.TODO(), "%s failed for user %q: %v", action, user, err)
+		return pam.ErrIgnore
+	}
+
+	return applyUserSessionResponse(mTx, resp)
 }
 
-// OpenSession is the method that is invoked during pam_open_session request.
-func (h *pamModule) OpenSession(pam.ModuleTransaction, pam.Flags, []string) error {
-	return pam.ErrIgnore
+// applyUserSessionResponse sets the environment variables returned by the broker in the PAM environment. The action is
+// ignored if the broker did not handle it.
+func applyUserSessionResponse(mTx pam.ModuleTransaction, resp *authd.USResponse) error {
+	if !resp.GetHandled() {
+		return pam.ErrIgnore
+	}
+
+	env := resp.GetEnv()
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if err := mTx.PutEnv(name + "=" + env[name]); err != nil {
+			log.Warningf(context.TODO(), "Impossible to set environment variable %q: %v", name, err)
+		}
+	}
+
+	return nil
 }
 
-// CloseSession is the method that is invoked during pam_close_session request.
-func (h *pamModule) CloseSession(pam.ModuleTransaction, pam.Flags, []string) error {
-	return pam.ErrIgnore
+// credentialsAction returns the credentials action requested by the pam_setcred flags, which establishes the
+// credentials by default.
+func credentialsAction(flags pam.Flags) authd.CredentialsAction {
+	switch {
+	case flags&pam.DeleteCred != 0:
+		return authd.CredentialsAction_CREDENTIALS_DELETE
+	case flags&pam.ReinitializeCred != 0:
+		return authd.CredentialsAction_CREDENTIALS_REINITIALIZE
+	case flags&pam.RefreshCred != 0:
+		return authd.CredentialsAction_CREDENTIALS_REFRESH
+	default:
+		return authd.CredentialsAction_CREDENTIALS_ESTABLISH
+	}
 }
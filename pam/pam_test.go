@@ -1,18 +1,209 @@
 package main
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/msteinert/pam/v2"
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/pam/internal/adapter"
+	"github.com/ubuntu/authd/pam/internal/pam_test"
 )
 
-func TestUnimplementedActions(t *testing.T) {
-	module := &pamModule{}
+func TestAccountStatusToPamError(t *testing.T) {
+	t.Parallel()
 
-	// If these gets changed, go-exec module should be also adapted accordingly
-	// together with TestExecModuleUnimplementedActions
-	require.Error(t, module.SetCred(nil, pam.Flags(0), nil), pam.ErrIgnore)
-	require.Error(t, module.OpenSession(nil, pam.Flags(0), nil), pam.ErrIgnore)
-	require.Error(t, module.CloseSession(nil, pam.Flags(0), nil), pam.ErrIgnore)
+	tests := map[string]struct {
+		status authd.AccountStatus
+		args   map[string]string
+
+		wantErr error
+	}{
+		"Valid_account_succeeds":                              {status: authd.AccountStatus_ACCOUNT_VALID},
+		"Not_checked_account_is_ignored":                      {status: authd.AccountStatus_ACCOUNT_NOT_CHECKED, wantErr: pam.ErrIgnore},
+		"Expired_account_is_expired":                          {status: authd.AccountStatus_ACCOUNT_EXPIRED, wantErr: pam.ErrAcctExpired},
+		"Account_requiring_a_password_change_needs_new_token": {status: authd.AccountStatus_ACCOUNT_PASSWORD_CHANGE_REQUIRED, wantErr: pam.ErrNewAuthtokReqd},
+		"Denied_account_is_denied":                            {status: authd.AccountStatus_ACCOUNT_DENIED, wantErr: pam.ErrPermDenied},
+
+		"Account_with_unavailable_broker_is_ignored_by_default": {status: authd.AccountStatus_ACCOUNT_BROKER_UNAVAILABLE, wantErr: pam.ErrIgnore},
+		"Account_with_unavailable_broker_is_ignored_when_allow": {status: authd.AccountStatus_ACCOUNT_BROKER_UNAVAILABLE, args: map[string]string{"offline_account": "allow"}, wantErr: pam.ErrIgnore},
+		"Account_with_unavailable_broker_is_denied_when_deny":   {status: authd.AccountStatus_ACCOUNT_BROKER_UNAVAILABLE, args: map[string]string{"offline_account": "deny"}, wantErr: pam.ErrPermDenied},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			denyOffline, _ := parseOfflineAccountPolicy(tc.args)
+			err := accountStatusToPamError(tc.status, denyOffline)
+			if tc.wantErr == nil {
+				require.NoError(t, err, "accountStatusToPamError should not return an error, but did")
+				return
+			}
+			require.ErrorIs(t, err, tc.wantErr, "accountStatusToPamError should return the expected error")
+		})
+	}
+}
+
+func TestCheckAccount(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		offlineAccount string
+
+		wantErr error
+	}{
+		"Unreachable_authd_is_unavailable_by_default":     {wantErr: pam.ErrAuthinfoUnavail},
+		"Unreachable_authd_is_unavailable_when_allow":     {offlineAccount: "allow", wantErr: pam.ErrAuthinfoUnavail},
+		"Unreachable_authd_is_denied_when_deny":           {offlineAccount: "deny", wantErr: pam.ErrPermDenied},
+		"Invalid_offline_account_value_denies_the_access": {offlineAccount: "maybe", wantErr: pam.ErrPermDenied},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			args := map[string]string{
+				"socket":             filepath.Join(t.TempDir(), "authd.sock"),
+				"connection_timeout": "10",
+			}
+			if tc.offlineAccount != "" {
+				args["offline_account"] = tc.offlineAccount
+			}
+
+			mTx := pam_test.NewModuleTransactionDummy(nil)
+			require.NoError(t, mTx.SetItem(pam.User, "user"), "Setup: could not set PAM user")
+
+			err := checkAccount(mTx, args)
+			require.ErrorIs(t, err, tc.wantErr, "checkAccount should return the expected error")
+		})
+	}
+}
+
+func TestParseOfflineAccountPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args map[string]string
+
+		wantDeny bool
+		wantErr  bool
+	}{
+		"No_args_allows_accounts": {},
+		"Allow_allows_accounts":   {args: map[string]string{"offline_account": "allow"}},
+		"Deny_denies_accounts":    {args: map[string]string{"offline_account": "deny"}, wantDeny: true},
+
+		"Error_on_invalid_value": {args: map[string]string{"offline_account": "maybe"}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			deny, err := parseOfflineAccountPolicy(tc.args)
+			if tc.wantErr {
+				require.Error(t, err, "parseOfflineAccountPolicy should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "parseOfflineAccountPolicy should not return an error, but did")
+			require.Equal(t, tc.wantDeny, deny, "parseOfflineAccountPolicy should return the expected policy")
+		})
+	}
+}
+
+func TestApplyUserSessionResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		resp *authd.USResponse
+
+		wantEnv map[string]string
+		wantErr error
+	}{
+		"Handled_request_without_environment_succeeds": {resp: &authd.USResponse{Handled: true}, wantEnv: map[string]string{}},
+		"Handled_request_sets_the_environment": {
+			resp:    &authd.USResponse{Handled: true, Env: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_1000", "EMPTY": ""}},
+			wantEnv: map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc_1000", "EMPTY": ""},
+		},
+
+		"Unhandled_request_is_ignored":                   {resp: &authd.USResponse{}, wantEnv: map[string]string{}, wantErr: pam.ErrIgnore},
+		"Unhandled_request_does_not_set_the_environment": {resp: &authd.USResponse{Env: map[string]string{"FOO": "bar"}}, wantEnv: map[string]string{}, wantErr: pam.ErrIgnore},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mTx := pam_test.NewModuleTransactionDummy(nil)
+			err := applyUserSessionResponse(mTx, tc.resp)
+			if tc.wantErr == nil {
+				require.NoError(t, err, "applyUserSessionResponse should not return an error, but did")
+			} else {
+				require.ErrorIs(t, err, tc.wantErr, "applyUserSessionResponse should return the expected error")
+			}
+
+			env, err := mTx.GetEnvList()
+			require.NoError(t, err, "Setup: could not get PAM environment")
+			require.Equal(t, tc.wantEnv, env, "applyUserSessionResponse should set the expected environment")
+		})
+	}
+}
+
+func TestCredentialsAction(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		flags pam.Flags
+
+		want authd.CredentialsAction
+	}{
+		"No_flags_establishes_credentials":         {want: authd.CredentialsAction_CREDENTIALS_ESTABLISH},
+		"Establish_flag_establishes_credentials":   {flags: pam.EstablishCred, want: authd.CredentialsAction_CREDENTIALS_ESTABLISH},
+		"Delete_flag_deletes_credentials":          {flags: pam.DeleteCred, want: authd.CredentialsAction_CREDENTIALS_DELETE},
+		"Reinitialize_flag_reinitializes_them":     {flags: pam.ReinitializeCred, want: authd.CredentialsAction_CREDENTIALS_REINITIALIZE},
+		"Refresh_flag_refreshes_credentials":       {flags: pam.RefreshCred, want: authd.CredentialsAction_CREDENTIALS_REFRESH},
+		"Silent_flag_is_ignored":                   {flags: pam.Silent | pam.DeleteCred, want: authd.CredentialsAction_CREDENTIALS_DELETE},
+		"Silent_flag_alone_establishes_credential": {flags: pam.Silent, want: authd.CredentialsAction_CREDENTIALS_ESTABLISH},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, credentialsAction(tc.flags), "credentialsAction should return the expected action")
+		})
+	}
+}
+
+func TestParseFirstPassPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args map[string]string
+
+		want    adapter.FirstPassPolicy
+		wantErr bool
+	}{
+		"No_args_never_uses_first_pass":           {want: adapter.NoFirstPass},
+		"Other_args_never_use_first_pass":         {args: map[string]string{"debug": "true"}, want: adapter.NoFirstPass},
+		"Try_first_pass_tries_first_pass":         {args: map[string]string{"try_first_pass": ""}, want: adapter.TryFirstPass},
+		"Use_first_pass_uses_first_pass":          {args: map[string]string{"use_first_pass": ""}, want: adapter.UseFirstPass},
+		"Use_first_pass_takes_precedence":         {args: map[string]string{"try_first_pass": "", "use_first_pass": ""}, want: adapter.UseFirstPass},
+		"Use_first_pass_set_to_true_uses_it":      {args: map[string]string{"use_first_pass": "true"}, want: adapter.UseFirstPass},
+		"Use_first_pass_set_to_false_ignores_it":  {args: map[string]string{"use_first_pass": "false"}, want: adapter.NoFirstPass},
+		"Use_first_pass_set_to_false_lets_try_it": {args: map[string]string{"try_first_pass": "", "use_first_pass": "false"}, want: adapter.TryFirstPass},
+		"Try_first_pass_set_to_false_ignores_it":  {args: map[string]string{"try_first_pass": "false"}, want: adapter.NoFirstPass},
+
+		"Error_when_use_first_pass_value_is_invalid": {args: map[string]string{"use_first_pass": "maybe"}, wantErr: true},
+		"Error_when_try_first_pass_value_is_invalid": {args: map[string]string{"try_first_pass": "maybe"}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseFirstPassPolicy(tc.args)
+			if tc.wantErr {
+				require.Error(t, err, "parseFirstPassPolicy should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "parseFirstPassPolicy should not return an error, but did")
+			require.Equal(t, tc.want, got, "parseFirstPassPolicy should return the expected policy")
+		})
+	}
 }